@@ -1,16 +1,32 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"net"
 
+	"github.com/craftcms/nitro/pkg/apiauth"
 	"github.com/craftcms/nitro/protob"
 	"google.golang.org/grpc"
 )
 
 // NewClient is used for generating a new client to interact
-// with the gRPC API running in the proxy container
-func NewClient(ip, port string) (protob.NitroClient, error) {
-	cc, err := grpc.Dial(ip+":"+port, grpc.WithInsecure())
+// with the gRPC API running in the proxy container, the client
+// uses TLS and presents the token saved in the home directory.
+// The address is looked up with addr each time the client
+// connects, so the client can be created before the environment
+// is selected.
+func NewClient(addr func(ctx context.Context) (string, error), home string) (protob.NitroClient, error) {
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		a, err := addr(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return (&net.Dialer{}).DialContext(ctx, "tcp", a)
+	}
+
+	cc, err := grpc.Dial("nitrod", append(apiauth.DialOptions(home), grpc.WithContextDialer(dialer))...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create a gRPC client for nitrod, %w", err)
 	}
@@ -8,7 +8,7 @@ import (
 
 func main() {
 	// execute the nitro root command
-	if err := nitro.NewCommand().Execute(); err != nil {
+	if err := nitro.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/craftcms/nitro/pkg/api"
+	"github.com/craftcms/nitro/pkg/apiauth"
 	"github.com/craftcms/nitro/protob"
 )
 
@@ -27,8 +28,14 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// require TLS and the token created by `nitro init` for each request
+	opts, err := apiauth.ServerOptions(os.Getenv(apiauth.EnvToken), []byte(os.Getenv(apiauth.EnvCert)), []byte(os.Getenv(apiauth.EnvKey)))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// create the grpc server
-	s := grpc.NewServer()
+	s := grpc.NewServer(opts...)
 
 	protob.RegisterNitroServer(s, api.NewService(*addr))
 
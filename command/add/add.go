@@ -21,7 +21,10 @@ const exampleText = `  # add the current directory as a site
   nitro add
 
   # add a directory as the site
-  nitro add my-project`
+  nitro add my-project
+
+  # add a site without prompting (e.g. in scripts)
+  nitro add my-project --no-interaction --yes --hostname my-project.nitro --php 8.0 --skip-database`
 
 // NewCommand returns the command to add a site to the nitro config.
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
@@ -63,7 +66,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			output.Info("Adding site…")
 
-			if _, err := prompt.CreateSite(home, dir, output); err != nil {
+			if _, err := prompt.CreateSite(cmd, home, dir, output); err != nil {
 				return err
 			}
 
@@ -114,7 +117,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// if the wanted a new database edit the env
 			if pathexists.IsFile(envFilePath) {
 				// ask the user if we should update the .env?
-				updateEnv, err := output.Confirm("Should we update the env file?", false, "")
+				updateEnv, err := prompt.Confirm(cmd, output, "update-env", "Should we update the env file?", false)
 				if err != nil {
 					return err
 				}
@@ -159,5 +162,10 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	prompt.SiteFlags(cmd)
+	prompt.DatabaseFlags(cmd)
+	cmd.Flags().Bool("update-env", false, "update the env file with the database settings")
+	cmd.Flags().Bool("skip-apply", false, "skip applying changes")
+
 	return cmd
 }
@@ -61,6 +61,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			var options []string
 			for _, s := range sites {
 				options = append(options, s.Hostname)
@@ -78,7 +84,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					site, _ = cfg.FindSiteByHostName(options[0])
 				default:
 					// prompt for the site to alias
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -104,7 +110,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// prompt the user to add new alias
 			v := validate.MultipleHostnameValidator{}
-			alias, err := output.Ask("Enter the alias domain for the site (use commas to enter multiple)", "", ":", &v)
+			alias, err := prompt.Ask(cmd, output, "alias", "Enter the alias domain for the site (use commas to enter multiple)", "", ":", &v)
 			if err != nil {
 				return err
 			}
@@ -137,5 +143,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().String("alias", "", "the alias domains for the site, separated by commas (e.g. my-project.test)")
+
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
@@ -19,12 +19,18 @@ import (
 	"github.com/craftcms/nitro/command/apply/internal/customcontainer"
 	"github.com/craftcms/nitro/command/apply/internal/databasecontainer"
 	"github.com/craftcms/nitro/command/apply/internal/sitecontainer"
+	"github.com/craftcms/nitro/command/apply/internal/siteimage"
+	"github.com/craftcms/nitro/pkg/apiauth"
 	"github.com/craftcms/nitro/pkg/backup"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/phpextensions"
+	"github.com/craftcms/nitro/pkg/plan"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/wsl"
 
 	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/hooks"
 	"github.com/craftcms/nitro/pkg/hostedit"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/sudo"
@@ -38,7 +44,6 @@ import (
 
 var (
 	defaultFile = "/etc/hosts"
-	hostnames   []string
 	isWSL       = false
 )
 
@@ -48,6 +53,21 @@ const exampleText = `  # apply changes from a config
   # skip editing the hosts file
   nitro apply --skip-hosts
 
+  # apply without merging the .nitro.yaml in the current project
+  nitro apply --skip-project
+
+  # show the changes apply would make without making them
+  nitro apply --dry-run
+
+  # show the values that caused containers to be recreated
+  nitro apply --verbose
+
+  # check one container at a time
+  nitro apply --concurrency 1
+
+  # apply changes to sites and containers whenever the config file is saved
+  nitro apply --watch
+
   # you can also set the environment variable "NITRO_EDIT_HOSTS" to "false"`
 
 // NewCommand returns the command used to apply configuration file changes to a nitro environment.
@@ -65,6 +85,11 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 			return nil
 		},
 		PostRunE: func(cmd *cobra.Command, args []string) error {
+			// the cleanup is part of the plan for dry runs
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				return nil
+			}
+
 			ctx := cmd.Context()
 			if ctx == nil {
 				c, cancel := context.WithTimeout(context.Background(), time.Minute*5)
@@ -72,6 +97,13 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				ctx = c
 			}
 
+			// make sure another apply is not changing containers
+			lock, err := lockApply(home, output)
+			if err != nil {
+				return err
+			}
+			defer lock.Unlock()
+
 			// load the config
 			cfg, err := config.Load(home)
 			if err != nil {
@@ -79,43 +111,7 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 			}
 
 			// store all of the known container names
-			names := map[string]bool{}
-
-			// get all of the sites as hostnames
-			for _, s := range cfg.Sites {
-				names[s.Hostname] = true
-			}
-
-			// get the containers as hostnames
-			for _, c := range cfg.Containers {
-				names[fmt.Sprintf("%s%s", c.Name, customcontainer.Suffix)] = true
-			}
-
-			// get all of the databases
-			for _, d := range cfg.Databases {
-				h, _ := d.GetHostname()
-				names[h] = true
-			}
-
-			// is dynamodb enabled
-			if cfg.Services.DynamoDB {
-				names[dynamodb.Host] = true
-			}
-
-			// is mailhog enabled
-			if cfg.Services.Mailhog {
-				names[mailhog.Host] = true
-			}
-
-			// is minio enabled
-			if cfg.Services.Minio {
-				names[minio.Host] = true
-			}
-
-			// is redis enabled
-			if cfg.Services.Redis {
-				names[redis.Host] = true
-			}
+			names := knownContainers(cfg)
 
 			// create a filter for the environment
 			filter := filters.NewArgs()
@@ -149,7 +145,7 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				}
 
 				// set the container name
-				name := strings.TrimLeft(c.Names[0], "/")
+				name := containerlabels.Hostname(c.Names[0])
 
 				// check if this is a known container
 				if _, ok := names[name]; !ok {
@@ -216,69 +212,121 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				}
 			}
 
+			// remove the php extension images no longer used by a site
+			if err := siteimage.Prune(ctx, docker, home, cfg.Sites); err != nil {
+				output.Info("Unable to remove unused images", err.Error())
+			}
+
 			if isWSL {
 				output.Info(fmt.Sprintf("For your hostnames to work, add the following to `%s`:", `C:\Windows\System32\Drivers\etc\hosts`))
 				output.Info("---- COPY BELOW ----")
-				output.Info(fmt.Sprintf(`# <nitro>
-%s %s
-# </nitro>`, "127.0.0.1", strings.Join(hostnames, " ")))
+				output.Info(hostedit.Section("127.0.0.1", expectedHostnames(cfg)...))
 				output.Info("---- COPY ABOVE ----")
 			}
 
 			output.Info("Nitro is up and running 😃")
 
+			// keep applying changes to the config
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				// release the lock so other commands can apply while watching
+				if err := lock.Unlock(); err != nil {
+					return err
+				}
+
+				return watchConfig(cmd, home, docker, nitrod, output)
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Root().Context()
 
+			// dry runs only read the environment, so they do not wait for another apply
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if watch, _ := cmd.Flags().GetBool("watch"); watch && dryRun {
+				return fmt.Errorf("the --watch and --dry-run flags cannot be used together")
+			}
+
+			if !dryRun {
+				// make sure another apply is not changing containers
+				lock, err := lockApply(home, output)
+				if err != nil {
+					return err
+				}
+				defer lock.Unlock()
+			}
+
 			// load the config
 			cfg, err := config.Load(home)
 			if err != nil {
 				return err
 			}
 
-			// create a filter for the environment
-			filter := filters.NewArgs()
-			filter.Add("label", containerlabels.Nitro+"=true")
-
-			// add the filter for the network name
-			filter.Add("name", "nitro-network")
+			// merge the project config for the current directory
+			if cmd.Flag("skip-project").Value.String() != "true" {
+				if err := mergeProject(cmd, home, cfg, output, !dryRun); err != nil {
+					return err
+				}
+			}
 
-			output.Info("Checking network…")
+			// check the php extensions before making any changes
+			for _, s := range cfg.Sites {
+				if err := checkExtensions(s); err != nil {
+					return err
+				}
+			}
 
-			// check the network
-			var network types.NetworkResource
-			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
-			if err != nil {
-				return fmt.Errorf("unable to list docker networks\n%w", err)
+			// check the hooks before making any changes
+			if err := validateHooks(cfg); err != nil {
+				return err
 			}
 
-			// get the network for the environment
-			for _, n := range networks {
-				if n.Name == "nitro-network" {
-					network = n
-					break
+			// show the changes without making them
+			if dryRun {
+				p, err := buildPlan(ctx, cmd, home, docker, nitrod, cfg)
+				if err != nil {
+					return err
 				}
+
+				// show the plan as json for scripts
+				if j, ok := terminal.JSONOutput(output); ok {
+					return j.Result(p)
+				}
+
+				p.Print(output)
+
+				return nil
 			}
 
-			// if the network is not found
-			if network.ID == "" {
-				return fmt.Errorf("No network was found…\nrun `nitro init` to get started")
+			if runHooks(cmd) {
+				if err := hooks.Apply(ctx, docker, cfg, config.HookPreApply, output); err != nil {
+					return err
+				}
 			}
 
-			// remove the filter
-			filter.Del("name", "nitro-network")
+			output.Info("Checking network…")
+
+			// check the network
+			network, err := findNetwork(ctx, docker)
+			if err != nil {
+				return err
+			}
 
 			output.Success("network ready")
 
 			output.Info("Checking proxy…")
 
 			// check the proxy and ensure its started
-			_, err = proxycontainer.FindAndStart(ctx, docker)
+			_, err = proxycontainer.FindAndStart(ctx, docker, output)
 			if errors.Is(err, proxycontainer.ErrNoProxyContainer) {
+				// the proxy needs the credentials for the nitrod API
+				creds, err := apiauth.Ensure(home)
+				if err != nil {
+					return err
+				}
+
 				// create the proxy
-				if err := proxycontainer.Create(ctx, docker, output, network.ID); err != nil {
+				if err := proxycontainer.Create(ctx, docker, output, network.ID, creds); err != nil {
 					output.Info("unable to find the nitro proxy…\n run `nitro init` to resolve")
 					return err
 				}
@@ -289,145 +337,82 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 
 			output.Success("proxy ready")
 
-			output.Info("Checking databases…")
+			// the number of resources to reconcile at the same time
+			limit, _ := cmd.Flags().GetInt("concurrency")
 
-			// check the databases
-			for _, db := range cfg.Databases {
+			// databases, services, and custom containers do not depend on each other so they are
+			// checked at the same time, the hostnames are stored by position to keep the order
+			var jobs []job
+			dbHostnames := make([]string, len(cfg.Databases))
+			for i, db := range cfg.Databases {
+				i, db := i, db
 				n, _ := db.GetHostname()
-				output.Pending("checking", n)
 
-				// start or create the database
-				_, hostname, err := databasecontainer.StartOrCreate(ctx, docker, network.ID, db, output)
-				if err != nil {
-					output.Warning()
-					return err
-				}
+				jobs = append(jobs, job{name: n, run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+					// start or create the database
+					_, hostname, err := databasecontainer.StartOrCreate(ctx, docker, network.ID, db, output)
+					if err != nil {
+						return nil, err
+					}
 
-				// add the hostname to the hosts files
-				hostnames = append(hostnames, hostname)
+					// add the hostname to the hosts files
+					dbHostnames[i] = hostname
 
-				output.Done()
+					return nil, nil
+				}})
 			}
 
-			output.Info("Checking services…")
-
-			// check dynamodb service
-			switch cfg.Services.DynamoDB {
-			case false:
-				output.Pending("checking dynamodb")
-
-				if err := dynamodb.VerifyRemoved(ctx, docker, output); err != nil {
-					output.Warning()
-					return err
-				}
-
-				output.Done()
-			default:
-				output.Pending("checking dynamodb")
-
-				_, hostname, err := dynamodb.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
-
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
-
-				output.Done()
+			// check the services
+			services := []struct {
+				name    string
+				enabled bool
+				created func(context.Context, client.CommonAPIClient, string, terminal.Outputer) (string, string, error)
+				removed func(context.Context, client.CommonAPIClient, terminal.Outputer) error
+			}{
+				{name: "dynamodb", enabled: cfg.Services.DynamoDB, created: dynamodb.VerifyCreated, removed: dynamodb.VerifyRemoved},
+				{name: "mailhog", enabled: cfg.Services.Mailhog, created: mailhog.VerifyCreated, removed: mailhog.VerifyRemoved},
+				{name: "minio", enabled: cfg.Services.Minio, created: minio.VerifyCreated, removed: minio.VerifyRemoved},
+				{name: "redis", enabled: cfg.Services.Redis, created: redis.VerifyCreated, removed: redis.VerifyRemoved},
 			}
 
-			// check mailhog service
-			switch cfg.Services.Mailhog {
-			case false:
-				output.Pending("checking mailhog")
+			serviceHostnames := make([]string, len(services))
+			for i, svc := range services {
+				i, svc := i, svc
 
-				// make sure the service container is removed
-				if err := mailhog.VerifyRemoved(ctx, docker, output); err != nil {
-					return err
-				}
-
-				output.Done()
-			default:
-				output.Pending("checking mailhog")
+				jobs = append(jobs, job{name: svc.name, run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+					// make sure the service container is removed
+					if !svc.enabled {
+						return nil, svc.removed(ctx, docker, output)
+					}
 
-				// verify the mailhog container is created
-				_, hostname, err := mailhog.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
+					// verify the service container is created
+					_, hostname, err := svc.created(ctx, docker, network.ID, output)
+					if err != nil {
+						return nil, err
+					}
 
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+					serviceHostnames[i] = hostname
 
-				output.Done()
+					return nil, nil
+				}})
 			}
 
-			// check minio service
-			switch cfg.Services.Minio {
-			case false:
-				// make sure the service container is removed
-				err := minio.VerifyRemoved(ctx, docker, output)
-				if err != nil {
-					return err
-				}
-			default:
-				output.Pending("checking minio")
-
-				// verify the minio container is created
-				_, hostname, err := minio.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
-
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
-
-				output.Done()
+			// check the custom containers
+			for _, c := range cfg.Containers {
+				jobs = append(jobs, containerJob(cmd, home, docker, network.ID, c))
 			}
 
-			// check redis service
-			switch cfg.Services.Redis {
-			case false:
-				output.Pending("checking redis")
-
-				if err := redis.VerifyRemoved(ctx, docker, output); err != nil {
-					return err
-				}
-
-				output.Done()
-			default:
-				output.Pending("checking redis")
-
-				_, hostname, err := redis.VerifyCreated(ctx, docker, network.ID, output)
-				if err != nil {
-					return err
-				}
-
-				if hostname != "" {
-					hostnames = append(hostnames, hostname)
-				}
+			output.Info("Checking databases, services, and containers…")
 
-				output.Done()
+			if err := reconcile(ctx, terminal.NewProgress(output), limit, jobs); err != nil {
+				return err
 			}
 
-			if len(cfg.Containers) > 0 {
-				// get all of the containers
-				output.Info("Checking containers…")
-
-				for _, c := range cfg.Containers {
-					output.Pending("checking", fmt.Sprintf("%s.containers.nitro", c.Name))
-
-					// start, update or create the custom container
-					_, err := customcontainer.StartOrCreate(ctx, docker, home, network.ID, c)
-					if err != nil {
-						output.Warning()
-						return err
-					}
-
-					output.Done()
+			// the hostnames for the hosts file
+			var hostnames []string
+			for _, h := range append(dbHostnames, serviceHostnames...) {
+				if h != "" {
+					hostnames = append(hostnames, h)
 				}
 			}
 
@@ -435,18 +420,22 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 				// get all of the sites, their local path, the php version, and the type of project (nginx or PHP-FPM)
 				output.Info("Checking sites…")
 
-				// get the envs for the sites
-				for _, site := range cfg.Sites {
-					output.Pending("checking", site.Hostname)
+				// sites are checked once the databases they connect to are ready
+				var jobs []job
+				changes := make([]plan.Change, len(cfg.Sites))
+				for i, site := range cfg.Sites {
+					jobs = append(jobs, siteJob(cmd, home, docker, network.ID, site, cfg, &changes[i]))
+				}
 
-					// start, update or create the site container
-					_, err := sitecontainer.StartOrCreate(ctx, docker, home, network.ID, site, cfg)
-					if err != nil {
-						output.Warning()
+				if err := reconcile(ctx, terminal.NewProgress(output), limit, jobs); err != nil {
+					return err
+				}
+
+				// hooks show their output, so they run one at a time once the sites are ready
+				if runHooks(cmd) {
+					if err := siteHooks(ctx, home, docker, cfg.Sites, changes, output); err != nil {
 						return err
 					}
-
-					output.Done()
 				}
 			}
 
@@ -461,31 +450,27 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 
 			output.Done()
 
+			if runHooks(cmd) {
+				if err := hooks.Apply(ctx, docker, cfg, config.HookPostApply, output); err != nil {
+					return err
+				}
+			}
+
 			// should we update the hosts file?
-			if os.Getenv("NITRO_EDIT_HOSTS") == "false" || cmd.Flag("skip-hosts").Value.String() == "true" {
+			if skipHosts() || cmd.Flag("skip-hosts").Value.String() == "true" {
 				// skip updating the hosts file
 				return nil
 			}
 
 			// get all possible hostnames
-			for _, s := range cfg.Sites {
-				hostnames = append(hostnames, s.Hostname)
-				hostnames = append(hostnames, s.Aliases...)
-			}
-
-			// get custom container hostnames
-			for _, c := range cfg.Containers {
-				hostnames = append(hostnames, fmt.Sprintf("%s.containers.nitro", c.Name))
-			}
+			hostnames = append(hostnames, siteHostnames(cfg)...)
 
 			if len(hostnames) > 0 {
 				// is this wsl?
 				isWSL = wsl.IsWSL()
 
 				// set the hosts file based on the OS
-				if runtime.GOOS == "windows" {
-					defaultFile = `C:\Windows\System32\Drivers\etc\hosts`
-				}
+				defaultFile = hostsFile()
 
 				// check if hosts is already up to date
 				updated, err := hostedit.IsUpdated(defaultFile, "127.0.0.1", hostnames...)
@@ -506,7 +491,7 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 					case "windows":
 						// windows users should be running as admin, so just execute the hosts command
 						// as is
-						c := exec.Command(nitro, "hosts", "--hostnames="+strings.Join(hostnames, ","))
+						c := exec.Command(nitro, "hosts", "--env="+config.Environment, "--hostnames="+strings.Join(hostnames, ","))
 
 						c.Stdout = os.Stdout
 						c.Stderr = os.Stderr
@@ -518,7 +503,7 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 						output.Info("Updating hosts file (you might be prompted for your password)")
 
 						// add the hosts
-						if err := sudo.Run(nitro, "nitro", "hosts", "--hostnames="+strings.Join(hostnames, ",")); err != nil {
+						if err := sudo.Run(nitro, "nitro", "hosts", "--env="+config.Environment, "--hostnames="+strings.Join(hostnames, ",")); err != nil {
 							return err
 						}
 					}
@@ -531,11 +516,205 @@ func NewCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroC
 
 	// add flag to skip pulling images
 	cmd.Flags().Bool("skip-hosts", false, "skip modifying the hosts file")
+	cmd.Flags().Bool("skip-project", false, "skip merging the project config file")
+	cmd.Flags().Bool("trust-project", false, "trust the host hooks and builds in the project config file")
+	cmd.Flags().Bool("dry-run", false, "show the changes without making them")
+	cmd.Flags().Bool("verbose", false, "show the values that changed when containers are recreated")
+	cmd.Flags().Bool("watch", false, "keep running and apply changes when the config file changes")
+	cmd.Flags().Int("concurrency", Concurrency, "the number of containers to check at the same time")
+	cmd.Flags().Bool("skip-hooks", false, "skip running the site and apply hooks")
 
 	return cmd
 }
 
+// siteJob returns the job to start, create, or recreate the container for a site. The change
+// made to the container is stored in result so the hooks for the site can run once all of the
+// sites are ready.
+func siteJob(cmd *cobra.Command, home string, docker client.CommonAPIClient, networkID string, site config.Site, cfg *config.Config, result *plan.Change) job {
+	return job{name: site.Hostname, run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+		change, err := sitecontainer.Plan(ctx, docker, home, site, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		*result = change
+
+		// the existing container is destroyed when it is recreated
+		if change.Action == plan.Recreate && runHooks(cmd) {
+			if err := hooks.Site(ctx, docker, home, site, config.HookPreDestroy, output); err != nil {
+				return nil, err
+			}
+		}
+
+		if change.Action != plan.None {
+			output.Pending(string(change.Action) + "…")
+		}
+
+		// start, update or create the site container
+		if _, err := sitecontainer.Execute(ctx, docker, home, networkID, site, cfg, change); err != nil {
+			return nil, err
+		}
+
+		return differences(cmd, change), nil
+	}}
+}
+
+// containerJob returns the job to start, create, or recreate a custom container.
+func containerJob(cmd *cobra.Command, home string, docker client.CommonAPIClient, networkID string, c config.Container) job {
+	return job{name: c.Name + customcontainer.Suffix, run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+		change, err := customcontainer.Plan(ctx, docker, home, c)
+		if err != nil {
+			return nil, err
+		}
+
+		if change.Action != plan.None {
+			output.Pending(string(change.Action) + "…")
+		}
+
+		// start, update or create the custom container
+		if _, err := customcontainer.Execute(ctx, docker, home, networkID, c, change); err != nil {
+			return nil, err
+		}
+
+		return differences(cmd, change), nil
+	}}
+}
+
+// differences returns the lines that show why a container was recreated, the
+// verbose flag shows the actual and expected values for each difference.
+func differences(cmd *cobra.Command, change plan.Change) []string {
+	if change.Action != plan.Recreate || len(change.Differences) == 0 {
+		return nil
+	}
+
+	if verbose, _ := cmd.Flags().GetBool("verbose"); !verbose {
+		return []string{"    recreated because of changes to " + change.Fields()}
+	}
+
+	lines := []string{"    recreated because of changes to:"}
+	for _, d := range change.Differences {
+		lines = append(lines, "      "+d.String())
+	}
+
+	return lines
+}
+
+// checkExtensions makes sure the php extensions for a site are in the catalog
+// and can be installed for the php version, bundled extensions are skipped.
+func checkExtensions(site config.Site) error {
+	if err := phpextensions.Validate(site.Version, site.Extensions); err != nil {
+		return fmt.Errorf("invalid extensions for %s, %w", site.Hostname, err)
+	}
+
+	return nil
+}
+
+// lockApply locks the config directory so only one apply runs at a time, if another
+// apply is running it will wait for it to finish.
+func lockApply(home string, output terminal.Outputer) (*config.Lock, error) {
+	lock, err := config.LockApply(home, false)
+	if errors.Is(err, config.ErrLocked) {
+		output.Info("Waiting for another apply to finish…")
+
+		return config.LockApply(home, true)
+	}
+
+	return lock, err
+}
+
+// mergeProject looks for a project config from the current directory and merges
+// it into the global config. When save is true, the global config is saved when the
+// project adds or changes anything so the rest of the commands know about the project sites.
+// Host hooks, builds, and host paths outside of the project the project adds or changes
+// must be trusted first, since the project file usually comes from a repository.
+func mergeProject(cmd *cobra.Command, home string, cfg *config.Config, output terminal.Outputer, save bool) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := config.LoadProject(home, wd)
+	if errors.Is(err, config.ErrNoProjectFile) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// host hooks and builds are not run for a dry run
+	if untrusted := cfg.Untrusted(home, project); save && len(untrusted) > 0 {
+		output.Info(fmt.Sprintf("The project config %s runs commands or uses files on this machine:", project.File))
+		for _, u := range untrusted {
+			output.Info("  " + u)
+		}
+
+		// the yes flag does not trust a project config, it usually comes from a repository
+		trust := false
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes || cmd.Flags().Changed("trust-project") {
+			trust, err = prompt.Confirm(cmd, output, "trust-project", "Do you trust the project config", false)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !trust {
+			return fmt.Errorf("the project config %s is not trusted, use --trust-project to trust it or --skip-project to skip it", project.File)
+		}
+	}
+
+	output.Info("Merging project config…")
+
+	output.Pending("merging", project.File)
+
+	changed, err := cfg.MergeProject(project)
+	if err != nil {
+		output.Warning()
+		return err
+	}
+
+	if changed && save {
+		if err := cfg.Save(); err != nil {
+			output.Warning()
+			return err
+		}
+	}
+
+	output.Done()
+
+	return nil
+}
+
 func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod protob.NitroClient, cfg *config.Config) error {
+	sites := proxySites(cfg)
+
+	// if there are no sites, we are done
+	if len(sites) == 0 {
+		return nil
+	}
+
+	// wait for the api to be ready
+	for {
+		_, err := nitrod.Ping(ctx, &protob.PingRequest{})
+		if err == nil {
+			break
+		}
+	}
+
+	// configure the proxy with the sites
+	resp, err := nitrod.Apply(ctx, &protob.ApplyRequest{Sites: sites})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error {
+		return fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
+	}
+
+	return nil
+}
+
+// proxySites returns the sites, services, and custom containers the proxy routes to.
+func proxySites(cfg *config.Config) map[string]*protob.Site {
 	// convert the sites into the gRPC API Apply request
 	sites := make(map[string]*protob.Site)
 	for _, s := range cfg.Sites {
@@ -573,28 +752,5 @@ func updateProxy(ctx context.Context, docker client.ContainerAPIClient, nitrod p
 		}
 	}
 
-	// if there are no sites, we are done
-	if len(sites) == 0 {
-		return nil
-	}
-
-	// wait for the api to be ready
-	for {
-		_, err := nitrod.Ping(ctx, &protob.PingRequest{})
-		if err == nil {
-			break
-		}
-	}
-
-	// configure the proxy with the sites
-	resp, err := nitrod.Apply(ctx, &protob.ApplyRequest{Sites: sites})
-	if err != nil {
-		return err
-	}
-
-	if resp.Error {
-		return fmt.Errorf("unable to update the proxy, %s", resp.GetMessage())
-	}
-
-	return nil
+	return sites
 }
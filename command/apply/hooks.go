@@ -0,0 +1,59 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/hooks"
+	"github.com/craftcms/nitro/pkg/plan"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// validateHooks checks the hooks for each site and the apply hooks.
+func validateHooks(cfg *config.Config) error {
+	for _, s := range cfg.Sites {
+		if err := s.Hooks.Validate(); err != nil {
+			return fmt.Errorf("invalid hooks for %s, %w", s.Hostname, err)
+		}
+	}
+
+	if err := cfg.Hooks.Validate(cfg.Sites); err != nil {
+		return fmt.Errorf("invalid hooks, %w", err)
+	}
+
+	return nil
+}
+
+// runHooks returns false when the hooks are skipped with the skip-hooks flag.
+func runHooks(cmd *cobra.Command) bool {
+	skip, _ := cmd.Flags().GetBool("skip-hooks")
+
+	return !skip
+}
+
+// siteHooks runs the post_create hooks for the sites that were created, or recreated, and the
+// post_start hooks for the sites that were created or started. The changes are in the same
+// order as the sites.
+func siteHooks(ctx context.Context, home string, docker client.CommonAPIClient, sites []config.Site, changes []plan.Change, output terminal.Outputer) error {
+	for i, s := range sites {
+		var events []string
+		switch changes[i].Action {
+		case plan.Create, plan.Recreate:
+			events = []string{config.HookPostCreate, config.HookPostStart}
+		case plan.Start:
+			events = []string{config.HookPostStart}
+		}
+
+		for _, event := range events {
+			if err := hooks.Site(ctx, docker, home, s, event, output); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
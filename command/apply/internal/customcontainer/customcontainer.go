@@ -12,6 +12,7 @@ import (
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -24,7 +25,11 @@ import (
 
 const Suffix = ".containers.nitro"
 
-func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container) (hostname string, err error) {
+// Plan finds the custom containers existing container and returns the change needed
+// to match the configuration file without making any changes.
+func Plan(ctx context.Context, docker client.CommonAPIClient, home string, c config.Container) (plan.Change, error) {
+	change := plan.Change{Action: plan.None, Kind: "container", Name: c.Name + Suffix}
+
 	// set filters for the container
 	filter := filters.NewArgs()
 	filter.Add("label", containerlabels.Nitro+"=true")
@@ -33,49 +38,72 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 	// look for a container for the site
 	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
 	if err != nil {
-		return "", fmt.Errorf("error getting a list of containers")
+		return change, fmt.Errorf("error getting a list of containers")
 	}
 
 	// if there are no containers we need to create one
 	if len(containers) == 0 {
-		return create(ctx, docker, home, networkID, c)
+		change.Action = plan.Create
+		return change, nil
 	}
 
 	// there is a container, so inspect it and make sure it matched
 	container := containers[0]
+	change.ID = container.ID
+
+	// get the containers details that include environment variables
+	details, err := docker.ContainerInspect(ctx, container.ID)
+	if err != nil {
+		return change, err
+	}
+
+	// if the container is out of date
+	if diffs := match.ContainerDifferences(home, c, details); len(diffs) > 0 {
+		change.Action = plan.Recreate
+		change.Differences = diffs
+		return change, nil
+	}
 
-	// start the container if not running
 	if container.State != "running" {
-		if err := docker.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
-			return "", err
-		}
+		change.Action = plan.Start
 	}
 
-	// get the containers details that include environment variables
-	details, err := docker.ContainerInspect(ctx, container.ID)
+	return change, nil
+}
+
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container) (hostname string, err error) {
+	change, err := Plan(ctx, docker, home, c)
 	if err != nil {
 		return "", err
 	}
 
-	// if the container is out of date
-	if err := match.Container(home, c, details); err != nil {
-		fmt.Println(err)
-		fmt.Print("- updating… ")
+	return Execute(ctx, docker, home, networkID, c, change)
+}
 
+// Execute makes the change from the plan for the custom container and returns the container ID.
+func Execute(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container, change plan.Change) (string, error) {
+	switch change.Action {
+	case plan.Create:
+		return create(ctx, docker, home, networkID, c)
+	case plan.Recreate:
 		// stop container
-		if err := docker.ContainerStop(ctx, container.ID, nil); err != nil {
+		if err := docker.ContainerStop(ctx, change.ID, nil); err != nil {
 			return "", err
 		}
 
 		// remove container
-		if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
+		if err := docker.ContainerRemove(ctx, change.ID, types.ContainerRemoveOptions{}); err != nil {
 			return "", err
 		}
 
 		return create(ctx, docker, home, networkID, c)
+	case plan.Start:
+		if err := docker.ContainerStart(ctx, change.ID, types.ContainerStartOptions{}); err != nil {
+			return "", err
+		}
 	}
 
-	return container.ID, nil
+	return change.ID, nil
 }
 
 func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, c config.Container) (string, error) {
@@ -132,7 +160,7 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 	if len(c.Volumes) > 0 {
 		for _, v := range c.Volumes {
 			// generate the volume name
-			name := fmt.Sprintf("nitro_%s_%s", c.Name, strings.Replace(v, "/", "_", -1))
+			name := fmt.Sprintf("%s_%s_%s", containerlabels.VolumeName(), c.Name, strings.Replace(v, "/", "_", -1))
 
 			// filter for the volume
 			volFilter := filters.NewArgs()
@@ -198,14 +226,10 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 			PortBindings: portBindings,
 		},
 		&network.NetworkingConfig{
-			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
-					NetworkID: networkID,
-				},
-			},
+			EndpointsConfig: containerlabels.EndpointsConfig(networkID, fmt.Sprintf("%s%s", c.Name, Suffix)),
 		},
 		nil,
-		fmt.Sprintf("%s%s", c.Name, Suffix),
+		containerlabels.ContainerName(fmt.Sprintf("%s%s", c.Name, Suffix)),
 	)
 	if err != nil {
 		return "", fmt.Errorf("unable to create the container, %w", err)
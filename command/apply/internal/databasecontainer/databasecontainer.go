@@ -10,6 +10,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -27,45 +28,62 @@ var (
 	DatabaseImage = "%s:%s"
 )
 
-// StartOrCreate is used to find a specific database and start the container. If there is no container for the database,
-// it will create a new volume and container for the database.
-func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, output terminal.Outputer) (string, string, error) {
-	// create the filters for the database
-	filter := filters.NewArgs()
-	filter.Add("label", containerlabels.DatabaseEngine+"="+db.Engine)
-	filter.Add("label", containerlabels.DatabaseVersion+"="+db.Version)
-	filter.Add("label", containerlabels.DatabasePort+"="+db.Port)
-	filter.Add("label", containerlabels.Type+"=database")
-
+// Plan finds the container for a database and returns the change needed to match
+// the configuration file without making any changes.
+func Plan(ctx context.Context, docker client.CommonAPIClient, db config.Database) (plan.Change, error) {
 	hostname, err := db.GetHostname()
 	if err != nil {
-		return "", "", err
+		return plan.Change{}, err
 	}
 
-	// set the container database compatibility
-	if db.Engine == "mariadb" || db.Engine == "mysql" {
-		filter.Add("label", containerlabels.DatabaseCompatibility+"=mysql")
-	} else {
-		filter.Add("label", containerlabels.DatabaseCompatibility+"=postgres")
-	}
+	change := plan.Change{Action: plan.None, Kind: "database", Name: hostname}
 
 	// get the containers for the database
-	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter(db)})
 	if err != nil {
-		return "", "", fmt.Errorf("error getting a list of containers")
+		return change, fmt.Errorf("error getting a list of containers")
 	}
 
-	// if there is a container, we should start it and return
-	if len(containers) == 1 {
+	switch len(containers) {
+	case 1:
+		change.ID = containers[0].ID
+
 		// check if the container is running
 		if containers[0].State != "running" {
-			// start the container
-			if err := docker.ContainerStart(ctx, containers[0].ID, types.ContainerStartOptions{}); err != nil {
-				return "", "", err
-			}
+			change.Action = plan.Start
+		}
+	default:
+		change.Action = plan.Create
+	}
+
+	return change, nil
+}
+
+// StartOrCreate is used to find a specific database and start the container. If there is no container for the database,
+// it will create a new volume and container for the database.
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, output terminal.Outputer) (string, string, error) {
+	change, err := Plan(ctx, docker, db)
+	if err != nil {
+		return "", "", err
+	}
+
+	return Execute(ctx, docker, networkID, db, change, output)
+}
+
+// Execute makes the change from the plan for the database and returns the container ID and hostname.
+func Execute(ctx context.Context, docker client.CommonAPIClient, networkID string, db config.Database, change plan.Change, output terminal.Outputer) (string, string, error) {
+	hostname := change.Name
+
+	switch change.Action {
+	case plan.None:
+		return change.ID, hostname, nil
+	case plan.Start:
+		// start the container
+		if err := docker.ContainerStart(ctx, change.ID, types.ContainerStartOptions{}); err != nil {
+			return "", "", err
 		}
 
-		return containers[0].ID, hostname, nil
+		return change.ID, hostname, nil
 	}
 
 	// create the database labels for the new container
@@ -91,7 +109,7 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 	}
 
 	// create the volume
-	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{Driver: "local", Name: hostname, Labels: labels})
+	volume, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{Driver: "local", Name: containerlabels.ContainerName(hostname), Labels: labels})
 	if err != nil {
 		return "", "", fmt.Errorf("unable to create the volume, %w", err)
 	}
@@ -190,15 +208,11 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, networkID
 	}
 
 	networkConfig := &network.NetworkingConfig{
-		EndpointsConfig: map[string]*network.EndpointSettings{
-			"nitro-network": {
-				NetworkID: networkID,
-			},
-		},
+		EndpointsConfig: containerlabels.EndpointsConfig(networkID, hostname),
 	}
 
 	// create the container for the database
-	resp, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, hostname)
+	resp, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerlabels.ContainerName(hostname))
 	if err != nil {
 		return "", "", fmt.Errorf("unable to create the container, %w", err)
 	}
@@ -302,3 +316,21 @@ func waitForMySQLContainer(ctx context.Context, docker client.CommonAPIClient, c
 
 	return nil
 }
+
+// filter returns the filters to find the container for a database.
+func filter(db config.Database) filters.Args {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.DatabaseEngine+"="+db.Engine)
+	filter.Add("label", containerlabels.DatabaseVersion+"="+db.Version)
+	filter.Add("label", containerlabels.DatabasePort+"="+db.Port)
+	filter.Add("label", containerlabels.Type+"=database")
+
+	// set the container database compatibility
+	if db.Engine == "mariadb" || db.Engine == "mysql" {
+		filter.Add("label", containerlabels.DatabaseCompatibility+"=mysql")
+	} else {
+		filter.Add("label", containerlabels.DatabaseCompatibility+"=postgres")
+	}
+
+	return filter
+}
@@ -5,13 +5,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 
+	"github.com/craftcms/nitro/command/apply/internal/siteimage"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 )
 
 var (
@@ -19,26 +23,50 @@ var (
 	ErrMisMatchedLabel  = fmt.Errorf("container label does not match")
 	ErrEnvFileNotFound  = fmt.Errorf("unable to find the containers env file")
 	ErrMisMatchedEnvVar = fmt.Errorf("container environment variables do not match")
+	ErrMisMatchedPort   = fmt.Errorf("container ports do not match")
 )
 
 // Container checks if a custom container is up to date with the configuration
 func Container(home string, container config.Container, details types.ContainerJSON) error {
+	for _, d := range ContainerDifferences(home, container, details) {
+		switch {
+		case d.Field == "image":
+			return ErrMisMatchedImage
+		case d.Field == "name":
+			return ErrMisMatchedLabel
+		case d.Field == "env file":
+			return ErrEnvFileNotFound
+		case strings.HasPrefix(d.Field, "port"):
+			return ErrMisMatchedPort
+		default:
+			return ErrMisMatchedEnvVar
+		}
+	}
+
+	return nil
+}
+
+// ContainerDifferences returns the differences between a custom container and the configuration.
+func ContainerDifferences(home string, container config.Container, details types.ContainerJSON) []plan.Difference {
+	var diffs []plan.Difference
+
 	// check if the image does not match - this uses the image name, not ref
-	if fmt.Sprintf("%s:%s", container.Image, container.Tag) != details.Config.Image {
-		return ErrMisMatchedImage
+	if image := fmt.Sprintf("%s:%s", container.Image, container.Tag); image != details.Config.Image {
+		diffs = append(diffs, plan.Difference{Field: "image", Actual: details.Config.Image, Expected: image})
 	}
 
 	// check the name has been changed
 	if details.Config.Labels[containerlabels.NitroContainer] != container.Name {
-		return ErrMisMatchedLabel
+		diffs = append(diffs, plan.Difference{Field: "name", Actual: details.Config.Labels[containerlabels.NitroContainer], Expected: container.Name})
 	}
 
 	if container.EnvFile != "" {
 		customEnvs := make(map[string]string)
 
-		content, err := ioutil.ReadFile(filepath.Join(home, config.DirectoryName, "."+container.Name))
+		file := filepath.Join(home, config.DirectoryName, "."+container.Name)
+		content, err := ioutil.ReadFile(file)
 		if err != nil {
-			return ErrEnvFileNotFound
+			return append(diffs, plan.Difference{Field: "env file", Actual: "(missing)", Expected: file})
 		}
 
 		for _, line := range strings.Split(string(content), "\n") {
@@ -57,71 +85,274 @@ func Container(home string, container config.Container, details types.ContainerJ
 			// is there a custom env val for the variable?
 			if custom, ok := customEnvs[env]; ok {
 				if val != custom {
-					return ErrMisMatchedEnvVar
+					diffs = append(diffs, plan.Difference{Field: "env " + env})
 				}
 			}
 		}
 	}
 
-	// TODO(jasonmccallister) check the port mappings
+	// check the port mappings
+	diffs = append(diffs, portDifferences(container.Ports, details)...)
+
 	// TODO(jasonmccallister) check the volumes
 
-	return nil
+	return diffs
 }
 
 // Site takes the home directory, site, and a container to determine if they
 // match whats expected.
 func Site(home string, site config.Site, container types.ContainerJSON, blackfire config.Blackfire) bool {
+	return len(SiteDifferences(home, site, container, blackfire)) == 0
+}
+
+// SiteDifferences takes the home directory, site, and a container and returns
+// the differences between the container and the site in the config.
+func SiteDifferences(home string, site config.Site, container types.ContainerJSON, blackfire config.Blackfire) []plan.Difference {
+	var diffs []plan.Difference
+
 	// check if the image does not match - this uses the image name, not ref
-	if fmt.Sprintf("docker.io/craftcms/nginx:%s-dev", site.Version) != container.Config.Image {
-		return false
+	image, err := siteimage.Name(home, site)
+	if err != nil {
+		return append(diffs, plan.Difference{Field: "image", Actual: container.Config.Image, Expected: err.Error()})
+	}
+
+	if image != container.Config.Image {
+		diffs = append(diffs, plan.Difference{Field: "image", Actual: container.Config.Image, Expected: image})
 	}
 
 	// check the web root is defined and they match
 	if container.Config.Labels[containerlabels.Webroot] != site.Webroot {
-		return false
+		diffs = append(diffs, plan.Difference{Field: "webroot", Actual: container.Config.Labels[containerlabels.Webroot], Expected: site.Webroot})
 	}
 
 	// check the sites hostname using the label
 	if container.Config.Labels[containerlabels.Host] != site.Hostname {
-		return false
+		diffs = append(diffs, plan.Difference{Field: "hostname", Actual: container.Config.Labels[containerlabels.Host], Expected: site.Hostname})
 	}
 
 	// get the main site path (e.g. ~/dev/craft-dev)
 	path, err := site.GetAbsPath(home)
 	if err != nil {
-		return false
+		return append(diffs, plan.Difference{Field: "path", Actual: site.Path, Expected: err.Error()})
 	}
 
 	// check if the path exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return false
+		return append(diffs, plan.Difference{Field: "path", Actual: "(missing)", Expected: path})
+	}
+
+	// check the path and the additional mounts
+	diffs = append(diffs, mountDifferences(home, site, path, container.Mounts)...)
+
+	// check the extensions
+	if extensions := strings.Join(site.Extensions, ","); container.Config.Labels[containerlabels.Extensions] != extensions {
+		diffs = append(diffs, plan.Difference{Field: "extensions", Actual: container.Config.Labels[containerlabels.Extensions], Expected: extensions})
+	}
+
+	// check the php.ini directives
+	if container.Config.Labels[containerlabels.PHPINI] != site.PHPINI() {
+		diffs = append(diffs, plan.Difference{Field: "php.ini"})
+	}
+
+	// check the custom environment variables
+	custom, err := site.CustomEnvs(home)
+	if err != nil {
+		return append(diffs, plan.Difference{Field: "env", Expected: err.Error()})
 	}
 
-	// check the path
-	if len(container.Mounts) > 0 {
-		if path != container.Mounts[0].Source {
-			return false
+	diffs = append(diffs, customEnvDifferences(custom, container.Config.Labels[containerlabels.Env], container.Config.Env)...)
+
+	// run the final check on the environment variables
+	return append(diffs, envDifferences(site, blackfire, container.Config.Env)...)
+}
+
+// mountDifferences verifies the sites path is mounted to /app and the additional
+// mounts match the config. Bind mounts and site volumes that are no longer
+// in the config are returned as differences.
+func mountDifferences(home string, site config.Site, path string, mounts []types.MountPoint) []plan.Difference {
+	var diffs []plan.Difference
+
+	expected := make(map[string]types.MountPoint)
+	for _, m := range site.Mounts {
+		mp := types.MountPoint{Destination: m.GetTarget(), RW: !m.ReadOnly}
+
+		switch m.GetType() {
+		case config.MountTypeVolume:
+			mp.Type = mount.TypeVolume
+			mp.Name = containerlabels.SiteVolumeName(site.Hostname, m.GetVolumeName())
+		default:
+			source, err := m.GetAbsSource(home, site)
+			if err != nil {
+				diffs = append(diffs, plan.Difference{Field: "mount " + mp.Destination, Expected: err.Error()})
+				continue
+			}
+
+			mp.Type = mount.TypeBind
+			mp.Source = source
 		}
+
+		expected[mp.Destination] = mp
 	}
 
-	// TODO(jasonmccallister) check the labels for php extensions and write tests
-	switch len(site.Extensions) > 0 {
-	case false:
-		if container.Config.Labels[containerlabels.Extensions] != "" {
-			return false
+	found := make(map[string]bool)
+	for _, m := range mounts {
+		// check the sites path
+		if m.Destination == "/app" {
+			if m.Source != path {
+				diffs = append(diffs, plan.Difference{Field: "mount /app", Actual: m.Source, Expected: path})
+			}
+
+			continue
+		}
+
+		e, ok := expected[m.Destination]
+		if !ok {
+			// check for mounts that were removed from the config
+			if m.Type == mount.TypeBind || strings.HasPrefix(m.Name, containerlabels.SiteVolumeName(site.Hostname, "")) {
+				diffs = append(diffs, plan.Difference{Field: "mount " + m.Destination, Actual: describeMount(m)})
+			}
+
+			continue
 		}
-	default:
-		if container.Config.Labels[containerlabels.Extensions] != strings.Join(site.Extensions, ",") {
-			return false
+
+		found[m.Destination] = true
+
+		if m.Type != e.Type || m.RW != e.RW || (e.Type == mount.TypeVolume && m.Name != e.Name) || (e.Type == mount.TypeBind && m.Source != e.Source) {
+			diffs = append(diffs, plan.Difference{Field: "mount " + m.Destination, Actual: describeMount(m), Expected: describeMount(e)})
 		}
 	}
 
-	// run the final check on the environment variables
-	return checkEnvs(site, blackfire, container.Config.Env)
+	// check for mounts that were added to the config
+	for _, m := range site.Mounts {
+		if e, ok := expected[m.GetTarget()]; ok && !found[e.Destination] {
+			diffs = append(diffs, plan.Difference{Field: "mount " + e.Destination, Expected: describeMount(e)})
+		}
+	}
+
+	return diffs
+}
+
+// describeMount returns the type and source of a mount (e.g. volume nitro_demo.nitro_vendor (ro)).
+func describeMount(m types.MountPoint) string {
+	source := m.Source
+	if m.Type == mount.TypeVolume {
+		source = m.Name
+	}
+
+	desc := fmt.Sprintf("%s %s", m.Type, source)
+	if !m.RW {
+		desc += " (ro)"
+	}
+
+	return desc
 }
 
-func checkEnvs(site config.Site, blackfire config.Blackfire, envs []string) bool {
+// portDifferences checks the port bindings of a custom container using the ports
+// in the config (e.g. 9200:9200).
+func portDifferences(ports []string, details types.ContainerJSON) []plan.Difference {
+	expected := make(map[string]string)
+	for _, p := range ports {
+		parts := strings.Split(p, ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		expected[parts[1]+"/tcp"] = parts[0]
+	}
+
+	actual := make(map[string]string)
+	if details.ContainerJSONBase != nil && details.HostConfig != nil {
+		for port, bindings := range details.HostConfig.PortBindings {
+			for _, b := range bindings {
+				actual[string(port)] = b.HostPort
+			}
+		}
+	}
+
+	var diffs []plan.Difference
+	for _, p := range ports {
+		parts := strings.Split(p, ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		if port := parts[1] + "/tcp"; actual[port] != expected[port] {
+			diffs = append(diffs, plan.Difference{Field: "port " + port, Actual: actual[port], Expected: expected[port]})
+		}
+	}
+
+	for port, host := range actual {
+		if _, ok := expected[port]; !ok {
+			diffs = append(diffs, plan.Difference{Field: "port " + port, Actual: host})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+
+	return diffs
+}
+
+// EnvNames takes a list of environment variables (e.g. KEY=value) and returns the names separated by commas.
+func EnvNames(envs []string) string {
+	var names []string
+	for _, e := range envs {
+		names = append(names, strings.SplitN(e, "=", 2)[0])
+	}
+
+	return strings.Join(names, ",")
+}
+
+// customEnvDifferences verifies the containers environment has the sites custom environment
+// variables and, using the label with the variable names, that none were removed. The values
+// are not returned as they can contain secrets.
+func customEnvDifferences(custom []string, label string, envs []string) []plan.Difference {
+	var diffs []plan.Difference
+
+	expected := make(map[string]bool)
+	for _, e := range custom {
+		expected[strings.SplitN(e, "=", 2)[0]] = true
+	}
+
+	labeled := make(map[string]bool)
+	if label != "" {
+		for _, name := range strings.Split(label, ",") {
+			labeled[name] = true
+
+			if !expected[name] {
+				diffs = append(diffs, plan.Difference{Field: "env " + name, Actual: "set"})
+			}
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, e := range envs {
+		existing[e] = true
+	}
+
+	for _, e := range custom {
+		name := strings.SplitN(e, "=", 2)[0]
+
+		switch {
+		case !labeled[name]:
+			diffs = append(diffs, plan.Difference{Field: "env " + name, Expected: "set"})
+		case !existing[e]:
+			diffs = append(diffs, plan.Difference{Field: "env " + name})
+		}
+	}
+
+	// the names match but are in a different order
+	if len(diffs) == 0 && EnvNames(custom) != label {
+		diffs = append(diffs, plan.Difference{Field: "env", Actual: label, Expected: EnvNames(custom)})
+	}
+
+	return diffs
+}
+
+// envDifferences checks the php and xdebug environment variables against the site and
+// the blackfire credentials against the config.
+func envDifferences(site config.Site, blackfire config.Blackfire, envs []string) []plan.Difference {
+	var diffs []plan.Difference
+
 	// check the environment variables
 	for _, e := range envs {
 		sp := strings.Split(e, "=")
@@ -130,66 +361,89 @@ func checkEnvs(site config.Site, blackfire config.Blackfire, envs []string) bool
 
 		// TODO(jasonmccallister) consider adding checks for if blackfire is
 		// enabled for this site
-		if env == "BLACKFIRE_SERVER_ID" && blackfire.ServerID != val {
-			return false
-		}
-		if env == "BLACKFIRE_SERVER_TOKEN" && blackfire.ServerToken != val {
-			return false
+		if (env == "BLACKFIRE_SERVER_ID" && blackfire.ServerID != val) || (env == "BLACKFIRE_SERVER_TOKEN" && blackfire.ServerToken != val) {
+			// the credentials are not shown
+			diffs = append(diffs, plan.Difference{Field: "env " + env})
+			continue
 		}
 
 		// show only the environment variables we know about/support
-		if _, ok := config.DefaultEnvs[sp[0]]; ok {
-			// check the value of each environment variable we want to ensure the php config is not the "default" value and that the
-			// current value from the container match
-			switch env {
-			case "PHP_DISPLAY_ERRORS":
-				// if there is a custom value
-				if !site.PHP.DisplayErrors && val != config.DefaultEnvs[env] {
-					return false
-				}
-			case "PHP_MEMORY_LIMIT":
-				if (site.PHP.MemoryLimit == "" && val != config.DefaultEnvs[env]) || (site.PHP.MemoryLimit != "" && val != site.PHP.MemoryLimit) {
-					return false
-				}
-			case "PHP_MAX_EXECUTION_TIME":
-				if (site.PHP.MaxExecutionTime == 0 && val != config.DefaultEnvs[env]) || (site.PHP.MaxExecutionTime != 0 && val != strconv.Itoa(site.PHP.MaxExecutionTime)) {
-					return false
-				}
-			case "PHP_UPLOAD_MAX_FILESIZE":
-				if (site.PHP.MaxFileUpload == "" && val != config.DefaultEnvs[env]) || (site.PHP.MaxFileUpload != "" && val != site.PHP.MaxFileUpload) {
-					return false
-				}
-			case "PHP_MAX_INPUT_VARS":
-				if (site.PHP.MaxInputVars == 0 && val != config.DefaultEnvs[env]) || (site.PHP.MaxInputVars != 0 && val != strconv.Itoa(site.PHP.MaxInputVars)) {
-					return false
-				}
-			case "PHP_POST_MAX_SIZE":
-				if (site.PHP.PostMaxSize == "" && val != config.DefaultEnvs[env]) || (site.PHP.PostMaxSize != "" && val != site.PHP.PostMaxSize) {
-					return false
-				}
-			case "PHP_OPCACHE_ENABLE":
-				if (site.PHP.OpcacheEnable && val == config.DefaultEnvs[env]) || (!site.PHP.OpcacheEnable && val != config.DefaultEnvs[env]) {
-					return false
-				}
-			case "PHP_OPCACHE_REVALIDATE_FREQ":
-				if (site.PHP.OpcacheRevalidateFreq == 0 && val != config.DefaultEnvs[env]) || (site.PHP.OpcacheRevalidateFreq != 0 && val != strconv.Itoa(site.PHP.OpcacheRevalidateFreq)) {
-					return false
-				}
-			case "PHP_OPCACHE_VALIDATE_TIMESTAMPS":
-				if (site.PHP.OpcacheValidateTimestamps && val == config.DefaultEnvs[env]) || (!site.PHP.OpcacheValidateTimestamps && val != config.DefaultEnvs[env]) {
-					return false
-				}
-			case "XDEBUG_MODE":
-				if site.Xdebug && val == config.DefaultEnvs[env] {
-					return false
-				}
+		def, ok := config.DefaultEnvs[env]
+		if !ok {
+			continue
+		}
+
+		// check the value of each environment variable we want to ensure the php config is not the "default" value and that the
+		// current value from the container match
+		var mismatched bool
+		expected := def
+		switch env {
+		case "PHP_DISPLAY_ERRORS":
+			// if there is a custom value
+			mismatched = !site.PHP.DisplayErrors && val != def
+		case "PHP_MEMORY_LIMIT":
+			if site.PHP.MemoryLimit != "" {
+				expected = site.PHP.MemoryLimit
+			}
+
+			mismatched = val != expected
+		case "PHP_MAX_EXECUTION_TIME":
+			if site.PHP.MaxExecutionTime != 0 {
+				expected = strconv.Itoa(site.PHP.MaxExecutionTime)
+			}
 
-				if !site.Xdebug && val != config.DefaultEnvs[env] {
-					return false
+			mismatched = val != expected
+		case "PHP_UPLOAD_MAX_FILESIZE":
+			if site.PHP.MaxFileUpload != "" {
+				expected = site.PHP.MaxFileUpload
+			}
+
+			mismatched = val != expected
+		case "PHP_MAX_INPUT_VARS":
+			if site.PHP.MaxInputVars != 0 {
+				expected = strconv.Itoa(site.PHP.MaxInputVars)
+			}
+
+			mismatched = val != expected
+		case "PHP_POST_MAX_SIZE":
+			if site.PHP.PostMaxSize != "" {
+				expected = site.PHP.PostMaxSize
+			}
+
+			mismatched = val != expected
+		case "PHP_OPCACHE_ENABLE":
+			if site.PHP.OpcacheEnable {
+				expected = "1"
+			}
+
+			mismatched = (site.PHP.OpcacheEnable && val == def) || (!site.PHP.OpcacheEnable && val != def)
+		case "PHP_OPCACHE_REVALIDATE_FREQ":
+			if site.PHP.OpcacheRevalidateFreq != 0 {
+				expected = strconv.Itoa(site.PHP.OpcacheRevalidateFreq)
+			}
+
+			mismatched = val != expected
+		case "PHP_OPCACHE_VALIDATE_TIMESTAMPS":
+			if site.PHP.OpcacheValidateTimestamps {
+				expected = "1"
+			}
+
+			mismatched = (site.PHP.OpcacheValidateTimestamps && val == def) || (!site.PHP.OpcacheValidateTimestamps && val != def)
+		case "XDEBUG_MODE":
+			if site.Xdebug {
+				expected = "develop,debug"
+				if site.Version == "7.1" || site.Version == "7.0" {
+					expected = "xdebug2"
 				}
 			}
+
+			mismatched = (site.Xdebug && val == def) || (!site.Xdebug && val != def)
+		}
+
+		if mismatched {
+			diffs = append(diffs, plan.Difference{Field: "env " + env, Actual: val, Expected: expected})
 		}
 	}
 
-	return true
+	return diffs
 }
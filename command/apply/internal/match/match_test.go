@@ -3,15 +3,19 @@ package match
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
 )
 
-func Test_checkEnvs(t *testing.T) {
+func Test_envDifferences(t *testing.T) {
 	type args struct {
 		site      config.Site
 		blackfire config.Blackfire
@@ -231,8 +235,123 @@ func Test_checkEnvs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := checkEnvs(tt.args.site, tt.args.blackfire, tt.args.envs); got != tt.want {
-				t.Errorf("checkEnvs() = %v, want %v", got, tt.want)
+			if got := len(envDifferences(tt.args.site, tt.args.blackfire, tt.args.envs)) == 0; got != tt.want {
+				t.Errorf("envDifferences() matched = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mountDifferences(t *testing.T) {
+	site := config.Site{
+		Hostname: "demo.nitro",
+		Path:     "/home/nitro/dev/demo",
+		Mounts: []config.Mount{
+			{Source: "../plugin", Target: "/plugins/plugin", ReadOnly: true},
+			{Type: "volume", Target: "vendor"},
+		},
+	}
+
+	app := types.MountPoint{Type: mount.TypeBind, Source: "/home/nitro/dev/demo", Destination: "/app", RW: true}
+	plugin := types.MountPoint{Type: mount.TypeBind, Source: "/home/nitro/dev/plugin", Destination: "/plugins/plugin"}
+	vendor := types.MountPoint{Type: mount.TypeVolume, Name: "nitro_demo.nitro_vendor", Destination: "/app/vendor", RW: true}
+
+	tests := []struct {
+		name   string
+		site   config.Site
+		mounts []types.MountPoint
+		want   bool
+	}{
+		{
+			name:   "matching mounts return true",
+			site:   site,
+			mounts: []types.MountPoint{vendor, app, plugin},
+			want:   true,
+		},
+		{
+			name:   "missing mounts return false",
+			site:   site,
+			mounts: []types.MountPoint{app, plugin},
+			want:   false,
+		},
+		{
+			name:   "removed mounts return false",
+			site:   config.Site{Hostname: "demo.nitro", Path: "/home/nitro/dev/demo"},
+			mounts: []types.MountPoint{app, vendor},
+			want:   false,
+		},
+		{
+			name:   "changed read only mounts return false",
+			site:   site,
+			mounts: []types.MountPoint{app, vendor, {Type: mount.TypeBind, Source: "/home/nitro/dev/plugin", Destination: "/plugins/plugin", RW: true}},
+			want:   false,
+		},
+		{
+			name:   "changed site paths return false",
+			site:   config.Site{Hostname: "demo.nitro", Path: "/home/nitro/dev/other"},
+			mounts: []types.MountPoint{app},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := tt.site.GetAbsPath("/home/nitro")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(mountDifferences("/home/nitro", tt.site, path, tt.mounts)) == 0; got != tt.want {
+				t.Errorf("mountDifferences() matched = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_customEnvDifferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		custom []string
+		label  string
+		envs   []string
+		want   bool
+	}{
+		{
+			name: "containers without custom envs return true",
+			envs: []string{"PATH=/usr/local/bin", "PHP_MEMORY_LIMIT=512M"},
+			want: true,
+		},
+		{
+			name:   "matching custom envs return true",
+			custom: []string{"API_KEY=abc123", "CRAFT_ENVIRONMENT=dev"},
+			label:  "API_KEY,CRAFT_ENVIRONMENT",
+			envs:   []string{"PATH=/usr/local/bin", "API_KEY=abc123", "CRAFT_ENVIRONMENT=dev"},
+			want:   true,
+		},
+		{
+			name:   "changed values return false",
+			custom: []string{"CRAFT_ENVIRONMENT=staging"},
+			label:  "CRAFT_ENVIRONMENT",
+			envs:   []string{"CRAFT_ENVIRONMENT=dev"},
+			want:   false,
+		},
+		{
+			name:   "added envs return false",
+			custom: []string{"API_KEY=abc123", "CRAFT_ENVIRONMENT=dev"},
+			label:  "CRAFT_ENVIRONMENT",
+			envs:   []string{"CRAFT_ENVIRONMENT=dev"},
+			want:   false,
+		},
+		{
+			name:  "removed envs return false",
+			label: "CRAFT_ENVIRONMENT",
+			envs:  []string{"CRAFT_ENVIRONMENT=dev"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(customEnvDifferences(tt.custom, tt.label, tt.envs)) == 0; got != tt.want {
+				t.Errorf("customEnvDifferences() matched = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -274,7 +393,8 @@ func TestSite(t *testing.T) {
 					},
 					Mounts: []types.MountPoint{
 						{
-							Source: filepath.Join(wd, "testdata", "example-site"),
+							Source:      filepath.Join(wd, "testdata", "example-site"),
+							Destination: "/app",
 						},
 					},
 				},
@@ -301,7 +421,8 @@ func TestSite(t *testing.T) {
 					},
 					Mounts: []types.MountPoint{
 						{
-							Source: filepath.Join(wd, "testdata", "example-site"),
+							Source:      filepath.Join(wd, "testdata", "example-site"),
+							Destination: "/app",
 						},
 					},
 				},
@@ -326,7 +447,8 @@ func TestSite(t *testing.T) {
 					},
 					Mounts: []types.MountPoint{
 						{
-							Source: filepath.Join(wd, "testdata", "new-path"),
+							Source:      filepath.Join(wd, "testdata", "new-path"),
+							Destination: "/app",
 						},
 					},
 				},
@@ -369,6 +491,66 @@ func TestSite(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "mismatched php.ini settings return false",
+			args: args{
+				home: "testdata/example-site",
+				site: config.Site{
+					Hostname: "newname",
+					Path:     "testdata/example-site",
+					Version:  "7.4",
+					Webroot:  "web",
+					INI:      map[string]string{"date.timezone": "America/Chicago"},
+				},
+				container: types.ContainerJSON{
+					Config: &container.Config{
+						Image: "docker.io/craftcms/nginx:7.4-dev",
+						Labels: map[string]string{
+							containerlabels.Host:    "newname",
+							containerlabels.Webroot: "web",
+							containerlabels.PHPINI:  "; generated by nitro for newname\ndate.timezone = UTC\n",
+						},
+					},
+					Mounts: []types.MountPoint{
+						{
+							Source:      filepath.Join(wd, "testdata", "example-site"),
+							Destination: "/app",
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "matching php.ini settings return true",
+			args: args{
+				home: "testdata/example-site",
+				site: config.Site{
+					Hostname: "newname",
+					Path:     "testdata/example-site",
+					Version:  "7.4",
+					Webroot:  "web",
+					INI:      map[string]string{"date.timezone": "America/Chicago"},
+				},
+				container: types.ContainerJSON{
+					Config: &container.Config{
+						Image: "docker.io/craftcms/nginx:7.4-dev",
+						Labels: map[string]string{
+							containerlabels.Host:    "newname",
+							containerlabels.Webroot: "web",
+							containerlabels.PHPINI:  "; generated by nitro for newname\ndate.timezone = America/Chicago\n",
+						},
+					},
+					Mounts: []types.MountPoint{
+						{
+							Source:      filepath.Join(wd, "testdata", "example-site"),
+							Destination: "/app",
+						},
+					},
+				},
+			},
+			want: true,
+		},
 		{
 			name: "mismatched images return false",
 			args: args{
@@ -392,3 +574,101 @@ func TestSite(t *testing.T) {
 		})
 	}
 }
+
+func TestSiteDifferences(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	site := config.Site{
+		Hostname: "newname",
+		Path:     "testdata/example-site",
+		Version:  "7.4",
+		Webroot:  "web",
+		PHP:      config.PHP{MemoryLimit: "256M"},
+	}
+
+	details := func(image, memory string) types.ContainerJSON {
+		return types.ContainerJSON{
+			Config: &container.Config{
+				Image: image,
+				Labels: map[string]string{
+					containerlabels.Host:    "newname",
+					containerlabels.Webroot: "web",
+				},
+				Env: []string{"PHP_MEMORY_LIMIT=" + memory},
+			},
+			Mounts: []types.MountPoint{
+				{
+					Source:      filepath.Join(wd, "testdata", "example-site"),
+					Destination: "/app",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		container types.ContainerJSON
+		want      []plan.Difference
+	}{
+		{
+			name:      "matching containers have no differences",
+			container: details("docker.io/craftcms/nginx:7.4-dev", "256M"),
+		},
+		{
+			name:      "each difference is returned with the actual and expected values",
+			container: details("docker.io/craftcms/nginx:8.0-dev", "512M"),
+			want: []plan.Difference{
+				{Field: "image", Actual: "docker.io/craftcms/nginx:8.0-dev", Expected: "docker.io/craftcms/nginx:7.4-dev"},
+				{Field: "env PHP_MEMORY_LIMIT", Actual: "512M", Expected: "256M"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SiteDifferences("testdata/example-site", site, tt.container, config.Blackfire{}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SiteDifferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerDifferences(t *testing.T) {
+	c := config.Container{Name: "elasticsearch", Image: "elasticsearch", Tag: "7", Ports: []string{"9200:9200"}}
+
+	tests := []struct {
+		name     string
+		bindings nat.PortMap
+		want     []plan.Difference
+	}{
+		{
+			name:     "matching ports have no differences",
+			bindings: nat.PortMap{"9200/tcp": {{HostIP: "127.0.0.1", HostPort: "9200"}}},
+		},
+		{
+			name:     "changed and removed ports are returned",
+			bindings: nat.PortMap{"9200/tcp": {{HostIP: "127.0.0.1", HostPort: "9300"}}, "9300/tcp": {{HostIP: "127.0.0.1", HostPort: "9300"}}},
+			want: []plan.Difference{
+				{Field: "port 9200/tcp", Actual: "9300", Expected: "9200"},
+				{Field: "port 9300/tcp", Actual: "9300"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{PortBindings: tt.bindings}},
+				Config: &container.Config{
+					Image:  "elasticsearch:7",
+					Labels: map[string]string{containerlabels.NitroContainer: "elasticsearch"},
+				},
+			}
+
+			if got := ContainerDifferences("", c, details); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContainerDifferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,16 +10,20 @@ import (
 
 	"github.com/craftcms/nitro/command/apply/internal/match"
 	"github.com/craftcms/nitro/command/apply/internal/nginx"
+	"github.com/craftcms/nitro/command/apply/internal/siteimage"
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/craftcms/nitro/pkg/wsl"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
-	"github.com/docker/docker/pkg/stdcopy"
 )
 
 type command struct {
@@ -28,12 +32,16 @@ type command struct {
 }
 
 var (
-	// NginxImage is the image used for sites, with the PHP version
-	NginxImage = "docker.io/craftcms/nginx:%s-dev"
+	// PHPINIFile is the file in the container the sites php.ini directives are written to, it is
+	// prefixed so it loads after the other files in conf.d
+	PHPINIFile = "/usr/local/etc/php/conf.d/zz-nitro.ini"
 )
 
-// StartOrCreate is responsible for finding a sites existing container or creating a new one based on the values from the configuration file.
-func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config) (string, error) {
+// Plan finds a sites existing container and returns the change needed to match the
+// configuration file without making any changes.
+func Plan(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site, cfg *config.Config) (plan.Change, error) {
+	change := plan.Change{Action: plan.None, Kind: "site", Name: site.Hostname}
+
 	// set filters for the container
 	filter := filters.NewArgs()
 	filter.Add("label", containerlabels.Host+"="+site.Hostname)
@@ -41,65 +49,80 @@ func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, net
 	// look for a container for the site
 	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
 	if err != nil {
-		return "", fmt.Errorf("error getting a list of containers")
+		return change, fmt.Errorf("error getting a list of containers")
 	}
 
 	// if there are no containers we need to create one
 	if len(containers) == 0 {
-		return create(ctx, docker, home, networkID, site, cfg)
+		change.Action = plan.Create
+		return change, nil
 	}
 
 	// there is a container, so inspect it and make sure it matched
 	container := containers[0]
+	change.ID = container.ID
+
+	// get the containers details that include environment variables
+	details, err := docker.ContainerInspect(ctx, container.ID)
+	if err != nil {
+		return change, err
+	}
+
+	// if the container is out of date
+	if diffs := match.SiteDifferences(home, site, details, cfg.Blackfire); len(diffs) > 0 {
+		change.Action = plan.Recreate
+		change.Differences = diffs
+		return change, nil
+	}
 
 	if container.State != "running" {
-		if err := docker.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
-			return "", err
-		}
+		change.Action = plan.Start
 	}
 
-	// get the containers details that include environment variables
-	details, err := docker.ContainerInspect(ctx, container.ID)
+	return change, nil
+}
+
+// StartOrCreate is responsible for finding a sites existing container or creating a new one based on the values from the configuration file.
+func StartOrCreate(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config) (string, error) {
+	change, err := Plan(ctx, docker, home, site, cfg)
 	if err != nil {
 		return "", err
 	}
 
-	// if the container is out of date
-	if !match.Site(home, site, details, cfg.Blackfire) {
-		fmt.Print("- updating… ")
+	return Execute(ctx, docker, home, networkID, site, cfg, change)
+}
 
+// Execute makes the change from the plan for the site and returns the container ID.
+func Execute(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config, change plan.Change) (string, error) {
+	switch change.Action {
+	case plan.Create:
+		return create(ctx, docker, home, networkID, site, cfg)
+	case plan.Recreate:
 		// stop container
-		if err := docker.ContainerStop(ctx, container.ID, nil); err != nil {
+		if err := docker.ContainerStop(ctx, change.ID, nil); err != nil {
 			return "", err
 		}
 
 		// remove container
-		if err := docker.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{}); err != nil {
+		if err := docker.ContainerRemove(ctx, change.ID, types.ContainerRemoveOptions{}); err != nil {
 			return "", err
 		}
 
 		return create(ctx, docker, home, networkID, site, cfg)
+	case plan.Start:
+		if err := docker.ContainerStart(ctx, change.ID, types.ContainerStartOptions{}); err != nil {
+			return "", err
+		}
 	}
 
-	return container.ID, nil
+	return change.ID, nil
 }
 
 func create(ctx context.Context, docker client.CommonAPIClient, home, networkID string, site config.Site, cfg *config.Config) (string, error) {
-	// create the container
-	image := fmt.Sprintf(NginxImage, site.Version)
-
-	// pull the image if we are not in a development environment
-	_, dev := os.LookupEnv("NITRO_DEVELOPMENT")
-	if !dev {
-		rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
-		if err != nil {
-			return "", fmt.Errorf("unable to pull the image, %w", err)
-		}
-
-		buf := &bytes.Buffer{}
-		if _, err := buf.ReadFrom(rdr); err != nil {
-			return "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
-		}
+	// pull or build the image for the site
+	image, err := siteimage.Ensure(ctx, docker, home, site)
+	if err != nil {
+		return "", err
 	}
 
 	// get the sites path
@@ -131,8 +154,28 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		envs = append(envs, "BLACKFIRE_SERVER_TOKEN="+cfg.Blackfire.ServerToken)
 	}
 
+	// add the sites custom environment variables
+	custom, err := site.CustomEnvs(home)
+	if err != nil {
+		return "", err
+	}
+
+	envs = append(envs, custom...)
+
 	// set the labels
 	labels := containerlabels.ForSite(site)
+
+	// label the custom environment variables so removed variables are detected
+	if len(custom) > 0 {
+		labels[containerlabels.Env] = match.EnvNames(custom)
+	}
+
+	// create the additional mounts for the site
+	mounts, err := siteMounts(ctx, docker, home, site)
+	if err != nil {
+		return "", err
+	}
+
 	// create the container
 	resp, err := docker.ContainerCreate(
 		ctx,
@@ -143,17 +186,14 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		},
 		&container.HostConfig{
 			Binds:      []string{fmt.Sprintf("%s:/app:rw", path)},
+			Mounts:     mounts,
 			ExtraHosts: extraHosts,
 		},
 		&network.NetworkingConfig{
-			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
-					NetworkID: networkID,
-				},
-			},
+			EndpointsConfig: containerlabels.EndpointsConfig(networkID, site.Hostname),
 		},
 		nil,
-		site.Hostname,
+		containerlabels.ContainerName(site.Hostname),
 	)
 	if err != nil {
 		return "", fmt.Errorf("unable to create the container, %w", err)
@@ -187,71 +227,120 @@ func create(ctx context.Context, docker client.CommonAPIClient, home, networkID
 		commands = append(commands, command{Commands: []string{"chmod", "0644", "/etc/nginx/conf.d/default.conf"}})
 	}
 
-	// check if there are custom extensions
-	for _, ext := range site.Extensions {
-		commands = append(commands, command{Name: "installing-" + ext + "-extension", Commands: []string{"docker-php-ext-install", ext}})
+	// check for php.ini directives and copy the file to the container
+	if ini := site.PHPINI(); ini != "" {
+		tr, err := archive.Generate("zz-nitro.ini", ini)
+		if err != nil {
+			return "", err
+		}
+
+		// copy the file into the container
+		if err := docker.CopyToContainer(ctx, resp.ID, "/tmp", tr, types.CopyToContainerOptions{AllowOverwriteDirWithFile: false}); err != nil {
+			return "", err
+		}
+
+		commands = append(commands, command{Commands: []string{"cp", "/tmp/zz-nitro.ini", PHPINIFile}})
+		commands = append(commands, command{Commands: []string{"chmod", "0644", PHPINIFile}})
 	}
 
 	// run the commands
 	for _, c := range commands {
-		// create the exec
-		exec, err := docker.ContainerExecCreate(ctx, resp.ID, types.ExecConfig{
-			User:         "root",
-			AttachStdout: true,
-			AttachStderr: true,
-			Tty:          false,
-			Cmd:          c.Commands,
-		})
+		// keep the output so sites applied at the same time do not interleave
+		var out bytes.Buffer
+		exitCode, err := containerexec.Run(ctx, docker, resp.ID, types.ExecConfig{User: "root", Cmd: c.Commands}, &out)
 		if err != nil {
 			return "", err
 		}
 
-		// attach to the container
-		attach, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{
-			Tty: false,
-		})
-		if err != nil {
-			return "", err
+		// show the output when the command fails
+		if exitCode != 0 {
+			return "", fmt.Errorf("unable to run %s in the container, %s", strings.Join(c.Commands, " "), strings.TrimSpace(out.String()))
 		}
-		defer attach.Close()
 
-		// if the option is for a php extension, don't show output
-		if strings.Contains(c.Name, "-extension") {
-			// read the output to pull the image
-			fmt.Print("installing ", c.Commands[len(c.Commands)-1], "… ")
+		// start the container
+		if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+			return "", fmt.Errorf("unable to start the container, %w", err)
+		}
+	}
 
-			buf := &bytes.Buffer{}
-			if _, err := buf.ReadFrom(attach.Reader); err != nil {
-				return "", fmt.Errorf("unable to read output from container exec attach, %w", err)
-			}
-		} else {
-			// show the output to stdout and stderr
-			if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, attach.Reader); err != nil {
-				return "", fmt.Errorf("unable to copy the output of container, %w", err)
-			}
+	// restart the container so php loads the php.ini directives
+	if len(site.INI) > 0 {
+		if err := docker.ContainerRestart(ctx, resp.ID, nil); err != nil {
+			return "", fmt.Errorf("unable to restart the container, %w", err)
 		}
+	}
 
-		// start the exec
-		if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
-			return "", fmt.Errorf("unable to start the container, %w", err)
+	return resp.ID, nil
+}
+
+// siteMounts returns the additional bind mounts and volumes for a site,
+// volumes are created if they do not exist.
+func siteMounts(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site) ([]mount.Mount, error) {
+	var mounts []mount.Mount
+	for _, m := range site.Mounts {
+		if err := m.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid mount for %s, %w", site.Hostname, err)
 		}
 
-		// wait for the container exec to complete
-		waiting := true
-		for waiting {
-			resp, err := docker.ContainerExecInspect(ctx, exec.ID)
+		switch m.GetType() {
+		case config.MountTypeVolume:
+			name := containerlabels.SiteVolumeName(site.Hostname, m.GetVolumeName())
+
+			// check for an existing volume
+			filter := filters.NewArgs()
+			filter.Add("name", name)
+
+			resp, err := docker.VolumeList(ctx, filter)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 
-			waiting = resp.Running
-		}
+			// since the filter is fuzzy, do an exact match
+			exists := false
+			for _, v := range resp.Volumes {
+				if v.Name == name {
+					exists = true
+				}
+			}
 
-		// start the container
-		if err := docker.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
-			return "", fmt.Errorf("unable to start the container, %w", err)
+			if !exists {
+				if _, err := docker.VolumeCreate(ctx, volume.VolumeCreateBody{
+					Driver: "local",
+					Name:   name,
+					Labels: map[string]string{
+						containerlabels.Nitro:  "true",
+						containerlabels.Host:   site.Hostname,
+						containerlabels.Volume: name,
+					},
+				}); err != nil {
+					return nil, fmt.Errorf("unable to create the volume %s, %w", name, err)
+				}
+			}
+
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeVolume,
+				Source:   name,
+				Target:   m.GetTarget(),
+				ReadOnly: m.ReadOnly,
+			})
+		default:
+			source, err := m.GetAbsSource(home, site)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := os.Stat(source); err != nil {
+				return nil, fmt.Errorf("unable to find the mount source %s for %s", source, site.Hostname)
+			}
+
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   source,
+				Target:   m.GetTarget(),
+				ReadOnly: m.ReadOnly,
+			})
 		}
 	}
 
-	return resp.ID, nil
+	return mounts, nil
 }
@@ -0,0 +1,543 @@
+package siteimage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/archive"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/phpextensions"
+)
+
+var (
+	// NginxImage is the image used for sites, with the PHP version
+	NginxImage = "docker.io/craftcms/nginx:%s-dev"
+
+	// BuildRepository is the repository for images built from a sites Dockerfile (e.g. nitro-site/demo.nitro:<checksum>),
+	// named environments use their own repository (e.g. nitro-client-site)
+	BuildRepository = "nitro-site"
+
+	// ExtensionsRepository is the repository for images with PHP extensions installed, they are
+	// tagged with the PHP version and a checksum of the base image and extensions (e.g. nitro-extensions:7.4-<checksum>),
+	// named environments use their own repository (e.g. nitro-client-extensions)
+	ExtensionsRepository = "nitro-extensions"
+
+	// ErrImageAndBuild is returned when a site has both a custom image and a build
+	ErrImageAndBuild = fmt.Errorf("a site can only use an image or a build, not both")
+)
+
+// images holds a lock for each image so sites that are applied at the same
+// time do not pull or build the same image more than once.
+var images = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+// Name takes the users home directory and a site and returns the image for
+// the sites container. Sites with PHP extensions use an image derived from
+// the base image, which is shared by sites with the same PHP version and
+// extensions.
+func Name(home string, site config.Site) (string, error) {
+	base, err := baseName(home, site)
+	if err != nil {
+		return "", err
+	}
+
+	extensions, err := installable(site)
+	if err != nil {
+		return "", err
+	}
+
+	if len(extensions) == 0 {
+		return base, nil
+	}
+
+	return extensionsName(base, site.Version, extensions), nil
+}
+
+// installable returns the extensions to install for a site, extensions that
+// are bundled with the base image are skipped.
+func installable(site config.Site) ([]string, error) {
+	extensions, err := phpextensions.Resolve(site.Version, site.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extensions for %s, %w", site.Hostname, err)
+	}
+
+	return extensions, nil
+}
+
+// baseName returns the image for a site before extensions are installed. Sites
+// that build a Dockerfile are tagged with the checksum of the Dockerfile and
+// build args, so changing them results in a new image name.
+func baseName(home string, site config.Site) (string, error) {
+	switch {
+	case site.Image != "" && site.Build != nil:
+		return "", ErrImageAndBuild
+	case site.Image != "":
+		return site.Image, nil
+	case site.Build != nil:
+		sum, err := checksum(home, site)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s/%s:%s", containerlabels.RepositoryName(BuildRepository), site.Hostname, sum), nil
+	}
+
+	return fmt.Sprintf(NginxImage, site.Version), nil
+}
+
+// Ensure makes sure the image for a site exists and returns the name. The
+// default images are pulled, custom images are pulled if they do not exist,
+// and Dockerfiles are built if the image for the checksum does not exist.
+// Images with extensions are built once and reused.
+func Ensure(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site) (string, error) {
+	image, err := Name(home, site)
+	if err != nil {
+		return "", err
+	}
+
+	extensions, err := installable(site)
+	if err != nil {
+		return "", err
+	}
+
+	unlock := lock(image)
+	defer unlock()
+
+	// reuse existing images with extensions, unless the base image changed
+	if len(extensions) > 0 {
+		current, err := upToDate(ctx, docker, home, site, image)
+		if err != nil {
+			return "", err
+		}
+
+		if current {
+			return image, nil
+		}
+	}
+
+	base, err := ensureBase(ctx, docker, home, site, len(extensions) > 0)
+	if err != nil {
+		return "", err
+	}
+
+	if len(extensions) == 0 {
+		return base, nil
+	}
+
+	// the images with extensions are labeled with the base image they were built from
+	baseImage, _, err := docker.ImageInspectWithRaw(ctx, base)
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect the image %s, %w", base, err)
+	}
+
+	if err := buildExtensions(ctx, docker, base, baseImage.ID, image, site.Version, extensions); err != nil {
+		return "", err
+	}
+
+	return image, nil
+}
+
+// ensureBase makes sure the image for a site, without extensions, exists.
+// Sites with extensions already hold the lock for the derived image, so the
+// base image is locked separately.
+func ensureBase(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site, derived bool) (string, error) {
+	image, err := baseName(home, site)
+	if err != nil {
+		return "", err
+	}
+
+	if derived {
+		unlock := lock(image)
+		defer unlock()
+	}
+
+	// check for a local image
+	local, err := exists(ctx, docker, image)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case site.Build != nil:
+		if local {
+			return image, nil
+		}
+
+		if err := build(ctx, docker, home, site, image); err != nil {
+			return "", err
+		}
+
+		return image, nil
+	case site.Image != "" && local:
+		return image, nil
+	}
+
+	// pull the image if we are not in a development environment
+	if _, dev := os.LookupEnv("NITRO_DEVELOPMENT"); dev && site.Image == "" {
+		return image, nil
+	}
+
+	rdr, err := docker.ImagePull(ctx, image, types.ImagePullOptions{All: false})
+	if err != nil {
+		return "", fmt.Errorf("unable to pull the image, %w", err)
+	}
+	defer rdr.Close()
+
+	buf := &bytes.Buffer{}
+	if _, err := buf.ReadFrom(rdr); err != nil {
+		return "", fmt.Errorf("unable to read output from pulling image %s, %w", image, err)
+	}
+
+	return image, nil
+}
+
+// upToDate returns true if the image with extensions exists and was built from
+// the local base image. When the base image is updated (e.g. with nitro update)
+// the extensions are installed in the new base image.
+func upToDate(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site, image string) (bool, error) {
+	found, err := exists(ctx, docker, image)
+	if err != nil || !found {
+		return false, err
+	}
+
+	base, err := baseName(home, site)
+	if err != nil {
+		return false, err
+	}
+
+	// without a local base image there is nothing newer to build from
+	found, err = exists(ctx, docker, base)
+	if err != nil || !found {
+		return err == nil, err
+	}
+
+	derived, _, err := docker.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect the image %s, %w", image, err)
+	}
+
+	current, _, err := docker.ImageInspectWithRaw(ctx, base)
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect the image %s, %w", base, err)
+	}
+
+	if derived.Config == nil {
+		return false, nil
+	}
+
+	return derived.Config.Labels[containerlabels.ExtensionsBase] == current.ID, nil
+}
+
+// lock waits for any other site using the image and returns the func to unlock it.
+func lock(image string) func() {
+	images.Lock()
+	l, ok := images.locks[image]
+	if !ok {
+		l = &sync.Mutex{}
+		images.locks[image] = l
+	}
+	images.Unlock()
+
+	l.Lock()
+
+	return l.Unlock
+}
+
+// Prune takes the users home directory and the sites in the config and
+// removes the images with extensions that are no longer used by a site,
+// including the images replaced when the base image was updated. Images
+// that are used by a container are not removed.
+func Prune(ctx context.Context, docker client.CommonAPIClient, home string, sites []config.Site) error {
+	images, err := Unused(ctx, docker, home, sites)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range images {
+		docker.ImageRemove(ctx, i.ID, types.ImageRemoveOptions{PruneChildren: true})
+	}
+
+	return nil
+}
+
+// Unused takes the users home directory and the sites in the config and
+// returns the images with extensions that are no longer used by a site.
+// Images that lost their tag when they were rebuilt are always unused.
+func Unused(ctx context.Context, docker client.CommonAPIClient, home string, sites []config.Site) ([]types.ImageSummary, error) {
+	used := make(map[string]bool)
+	for _, s := range sites {
+		image, err := Name(home, s)
+		if err != nil {
+			continue
+		}
+
+		used[image] = true
+	}
+
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Extensions)
+
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get a list of images, %w", err)
+	}
+
+	var unused []types.ImageSummary
+	for _, i := range images {
+		stale := true
+		for _, t := range i.RepoTags {
+			if used[t] {
+				stale = false
+			}
+		}
+
+		if stale {
+			unused = append(unused, i)
+		}
+	}
+
+	return unused, nil
+}
+
+// exists returns true if the image exists locally.
+func exists(ctx context.Context, docker client.CommonAPIClient, image string) (bool, error) {
+	filter := filters.NewArgs()
+	filter.Add("reference", image)
+
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return false, fmt.Errorf("unable to get a list of images, %w", err)
+	}
+
+	return len(images) > 0, nil
+}
+
+// extensionsName returns the name of the image with extensions for the base image.
+func extensionsName(base, version string, extensions []string) string {
+	sorted := sortedExtensions(extensions)
+
+	sum := sha256.Sum256([]byte(base + "\n" + strings.Join(sorted, ",")))
+
+	return fmt.Sprintf("%s:%s-%x", containerlabels.RepositoryName(ExtensionsRepository), version, sum[:6])
+}
+
+// extensionsDockerfile returns the Dockerfile to install the extensions in the base image.
+func extensionsDockerfile(base, version string, extensions []string) (string, error) {
+	commands, err := phpextensions.Install(version, sortedExtensions(extensions))
+	if err != nil {
+		return "", err
+	}
+
+	dockerfile := fmt.Sprintf("FROM %s\nUSER root\n", base)
+	for _, c := range commands {
+		dockerfile += "RUN " + c + "\n"
+	}
+
+	return dockerfile + "USER www-data\n", nil
+}
+
+// buildExtensions builds an image from the base image with the extensions installed.
+func buildExtensions(ctx context.Context, docker client.CommonAPIClient, base, baseID, image, version string, extensions []string) error {
+	dockerfile, err := extensionsDockerfile(base, version, extensions)
+	if err != nil {
+		return err
+	}
+
+	tar, err := archive.Generate("Dockerfile", dockerfile)
+	if err != nil {
+		return err
+	}
+
+	resp, err := docker.ImageBuild(ctx, tar, types.ImageBuildOptions{
+		Tags:        []string{image},
+		Dockerfile:  "Dockerfile",
+		Remove:      true,
+		ForceRemove: true,
+		Labels: map[string]string{
+			containerlabels.Nitro:          "true",
+			containerlabels.Extensions:     strings.Join(sortedExtensions(extensions), ","),
+			containerlabels.ExtensionsBase: baseID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to build the image with the extensions %s, %w", strings.Join(extensions, ", "), err)
+	}
+	defer resp.Body.Close()
+
+	if err := readBuildOutput(resp.Body); err != nil {
+		return fmt.Errorf("unable to install the extensions %s, %w", strings.Join(extensions, ", "), err)
+	}
+
+	return nil
+}
+
+// sortedExtensions returns a sorted copy of the extensions without duplicates.
+func sortedExtensions(extensions []string) []string {
+	seen := make(map[string]bool)
+
+	var sorted []string
+	for _, e := range extensions {
+		if !seen[e] {
+			seen[e] = true
+			sorted = append(sorted, e)
+		}
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// build builds the sites Dockerfile and removes the images from previous builds.
+func build(ctx context.Context, docker client.CommonAPIClient, home string, site config.Site, image string) error {
+	dir, err := site.Build.GetAbsContext(home, site)
+	if err != nil {
+		return err
+	}
+
+	tar, err := archive.TarWithOptions(dir, &archive.TarOptions{ExcludePatterns: dockerignore(dir)})
+	if err != nil {
+		return fmt.Errorf("unable to create the build context for %s, %w", site.Hostname, err)
+	}
+	defer tar.Close()
+
+	args := make(map[string]*string)
+	for k, v := range site.Build.Args {
+		v := v
+		args[k] = &v
+	}
+
+	resp, err := docker.ImageBuild(ctx, tar, types.ImageBuildOptions{
+		Tags:        []string{image},
+		Dockerfile:  site.Build.GetDockerfile(),
+		BuildArgs:   args,
+		Remove:      true,
+		ForceRemove: true,
+		PullParent:  true,
+		Labels: map[string]string{
+			containerlabels.Nitro: "true",
+			containerlabels.Host:  site.Hostname,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to build the image for %s, %w", site.Hostname, err)
+	}
+	defer resp.Body.Close()
+
+	if err := readBuildOutput(resp.Body); err != nil {
+		return fmt.Errorf("unable to build the image for %s, %w", site.Hostname, err)
+	}
+
+	// remove images from previous builds, images used by a container are kept
+	filter := filters.NewArgs()
+	filter.Add("reference", fmt.Sprintf("%s/%s", containerlabels.RepositoryName(BuildRepository), site.Hostname))
+
+	images, err := docker.ImageList(ctx, types.ImageListOptions{Filters: filter})
+	if err != nil {
+		return nil
+	}
+
+	for _, i := range images {
+		stale := true
+		for _, t := range i.RepoTags {
+			if t == image {
+				stale = false
+			}
+		}
+
+		if stale {
+			docker.ImageRemove(ctx, i.ID, types.ImageRemoveOptions{PruneChildren: true})
+		}
+	}
+
+	return nil
+}
+
+// checksum returns a short checksum of the sites Dockerfile and build args.
+func checksum(home string, site config.Site) (string, error) {
+	dir, err := site.Build.GetAbsContext(home, site)
+	if err != nil {
+		return "", err
+	}
+
+	dockerfile, err := ioutil.ReadFile(filepath.Join(dir, site.Build.GetDockerfile()))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the Dockerfile for %s, %w", site.Hostname, err)
+	}
+
+	h := sha256.New()
+	h.Write(dockerfile)
+
+	var args []string
+	for k, v := range site.Build.Args {
+		args = append(args, k+"="+v)
+	}
+
+	sort.Strings(args)
+
+	h.Write([]byte(strings.Join(args, "\n")))
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:12], nil
+}
+
+// dockerignore returns the patterns in the contexts .dockerignore file.
+func dockerignore(dir string) []string {
+	f, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		patterns = append(patterns, l)
+	}
+
+	return patterns
+}
+
+// readBuildOutput reads the output of a build and returns the first error.
+func readBuildOutput(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if msg.Error != "" {
+			return fmt.Errorf("%s", strings.TrimSpace(msg.Error))
+		}
+	}
+}
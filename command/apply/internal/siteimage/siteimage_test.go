@@ -0,0 +1,332 @@
+package siteimage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func TestName(t *testing.T) {
+	home := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(home, "dev", "demo", "docker"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dockerfile := filepath.Join(home, "dev", "demo", "docker", "Dockerfile")
+	if err := ioutil.WriteFile(dockerfile, []byte("FROM craftcms/nginx:7.4-dev\nRUN apk add ghostscript\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	build := config.Site{
+		Hostname: "demo.nitro",
+		Path:     "~/dev/demo",
+		Version:  "7.4",
+		Build:    &config.Build{Context: "docker"},
+	}
+
+	tests := []struct {
+		name       string
+		site       config.Site
+		want       string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{
+			name: "sites use the nginx image by default",
+			site: config.Site{Hostname: "demo.nitro", Version: "7.4"},
+			want: "docker.io/craftcms/nginx:7.4-dev",
+		},
+		{
+			name: "sites can use a custom image",
+			site: config.Site{Hostname: "demo.nitro", Version: "7.4", Image: "example/nginx:7.4-ghostscript"},
+			want: "example/nginx:7.4-ghostscript",
+		},
+		{
+			name:       "sites with a build are tagged using the checksum",
+			site:       build,
+			wantPrefix: "nitro-site/demo.nitro:",
+		},
+		{
+			name:    "sites with an image and build return an error",
+			site:    config.Site{Hostname: "demo.nitro", Image: "example/nginx", Build: &config.Build{}},
+			wantErr: true,
+		},
+		{
+			name: "sites with bundled extensions use the base image",
+			site: config.Site{Hostname: "demo.nitro", Version: "7.4", Extensions: []string{"intl", "pdo_mysql"}},
+			want: "docker.io/craftcms/nginx:7.4-dev",
+		},
+		{
+			name:    "sites with unknown extensions return an error",
+			site:    config.Site{Hostname: "demo.nitro", Version: "7.4", Extensions: []string{"redsi"}},
+			wantErr: true,
+		},
+		{
+			name:    "sites with extensions not available for the version return an error",
+			site:    config.Site{Hostname: "demo.nitro", Version: "8.0", Extensions: []string{"xmlrpc"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing Dockerfiles return an error",
+			site:    config.Site{Hostname: "demo.nitro", Path: "~/dev/demo", Build: &config.Build{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Name(home, tt.site)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Name() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.want != "" && got != tt.want {
+				t.Errorf("Name() = %v, want %v", got, tt.want)
+			}
+
+			if tt.wantPrefix != "" && !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("Name() = %v, want prefix %v", got, tt.wantPrefix)
+			}
+		})
+	}
+
+	// changing the Dockerfile should change the image
+	before, err := Name(home, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(dockerfile, []byte("FROM craftcms/nginx:7.4-dev\nRUN apk add ghostscript imagemagick\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := Name(home, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before == after {
+		t.Errorf("expected the image to change when the Dockerfile changes, got %s", after)
+	}
+}
+
+func Test_extensionsName(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		version    string
+		extensions []string
+		other      []string
+		wantPrefix string
+		wantSame   bool
+	}{
+		{
+			name:       "images are tagged with the php version",
+			base:       "docker.io/craftcms/nginx:7.4-dev",
+			version:    "7.4",
+			extensions: []string{"pdo_dblib"},
+			other:      []string{"pdo_dblib"},
+			wantPrefix: "nitro-extensions:7.4-",
+			wantSame:   true,
+		},
+		{
+			name:       "the order of extensions does not change the image",
+			base:       "docker.io/craftcms/nginx:7.4-dev",
+			version:    "7.4",
+			extensions: []string{"pdo_dblib", "calendar"},
+			other:      []string{"calendar", "pdo_dblib", "calendar"},
+			wantPrefix: "nitro-extensions:7.4-",
+			wantSame:   true,
+		},
+		{
+			name:       "different extensions change the image",
+			base:       "docker.io/craftcms/nginx:8.0-dev",
+			version:    "8.0",
+			extensions: []string{"pdo_dblib"},
+			other:      []string{"calendar"},
+			wantPrefix: "nitro-extensions:8.0-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extensionsName(tt.base, tt.version, tt.extensions)
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("extensionsName() = %v, want prefix %v", got, tt.wantPrefix)
+			}
+
+			if other := extensionsName(tt.base, tt.version, tt.other); (other == got) != tt.wantSame {
+				t.Errorf("extensionsName() = %v and %v, want same %v", got, other, tt.wantSame)
+			}
+		})
+	}
+
+	// sites with the same version and extensions share the image
+	first, err := Name("", config.Site{Hostname: "first.nitro", Version: "7.4", Extensions: []string{"calendar"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := Name("", config.Site{Hostname: "second.nitro", Version: "7.4", Extensions: []string{"calendar"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Errorf("expected sites to share the image, got %s and %s", first, second)
+	}
+}
+
+func Test_extensionsDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		extensions []string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "core extensions are installed",
+			version:    "7.4",
+			extensions: []string{"pdo_dblib", "calendar"},
+			want:       "FROM docker.io/craftcms/nginx:7.4-dev\nUSER root\nRUN apk add --no-cache freetds-dev\nRUN apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS && docker-php-ext-install calendar pdo_dblib && apk del .nitro-build-deps\nUSER www-data\n",
+		},
+		{
+			name:       "pecl extensions are installed and enabled",
+			version:    "7.4",
+			extensions: []string{"redis"},
+			want:       "FROM docker.io/craftcms/nginx:7.4-dev\nUSER root\nRUN apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS && pecl install redis-5.3.7 && docker-php-ext-enable redis && apk del .nitro-build-deps\nUSER www-data\n",
+		},
+		{
+			name:       "extensions not available for the version return an error",
+			version:    "7.4",
+			extensions: []string{"recode"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extensionsDockerfile("docker.io/craftcms/nginx:"+tt.version+"-dev", tt.version, tt.extensions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("extensionsDockerfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("extensionsDockerfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readBuildOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{
+			name:   "successful builds return nil",
+			output: `{"stream":"Step 1/2 : FROM craftcms/nginx:7.4-dev"}` + "\n" + `{"stream":"Successfully built 1234"}`,
+		},
+		{
+			name:    "errors in the output are returned",
+			output:  `{"stream":"Step 2/2 : RUN apk add missing"}` + "\n" + `{"errorDetail":{"message":"failed"},"error":"failed"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := readBuildOutput(strings.NewReader(tt.output)); (err != nil) != tt.wantErr {
+				t.Errorf("readBuildOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// imageClient is a docker client with local images, keyed by the image name.
+type imageClient struct {
+	client.CommonAPIClient
+
+	images map[string]types.ImageInspect
+}
+
+func (c *imageClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	var list []types.ImageSummary
+	for _, name := range options.Filters.Get("reference") {
+		if i, ok := c.images[name]; ok {
+			list = append(list, types.ImageSummary{ID: i.ID, RepoTags: []string{name}})
+		}
+	}
+
+	return list, nil
+}
+
+func (c *imageClient) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	i, ok := c.images[image]
+	if !ok {
+		return types.ImageInspect{}, nil, fmt.Errorf("no such image %s", image)
+	}
+
+	return i, nil, nil
+}
+
+func Test_upToDate(t *testing.T) {
+	site := config.Site{Hostname: "demo.nitro", Version: "7.4", Extensions: []string{"calendar"}}
+
+	image, err := Name("", site)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := "docker.io/craftcms/nginx:7.4-dev"
+	derived := types.ImageInspect{ID: "sha256:derived", Config: &container.Config{Labels: map[string]string{containerlabels.ExtensionsBase: "sha256:old"}}}
+
+	tests := []struct {
+		name   string
+		images map[string]types.ImageInspect
+		want   bool
+	}{
+		{
+			name:   "missing images are not up to date",
+			images: map[string]types.ImageInspect{base: {ID: "sha256:old"}},
+		},
+		{
+			name:   "images built from the local base image are up to date",
+			images: map[string]types.ImageInspect{base: {ID: "sha256:old"}, image: derived},
+			want:   true,
+		},
+		{
+			name:   "images built from an older base image are not up to date",
+			images: map[string]types.ImageInspect{base: {ID: "sha256:new"}, image: derived},
+		},
+		{
+			name:   "images without a local base image are reused",
+			images: map[string]types.ImageInspect{image: derived},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upToDate(context.Background(), &imageClient{images: tt.images}, "", site, image)
+			if err != nil {
+				t.Fatalf("upToDate() unexpected error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("upToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
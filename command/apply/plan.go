@@ -0,0 +1,424 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/apply/internal/customcontainer"
+	"github.com/craftcms/nitro/command/apply/internal/databasecontainer"
+	"github.com/craftcms/nitro/command/apply/internal/sitecontainer"
+	"github.com/craftcms/nitro/command/apply/internal/siteimage"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/hostedit"
+	"github.com/craftcms/nitro/pkg/plan"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
+	"github.com/craftcms/nitro/pkg/svc/dynamodb"
+	"github.com/craftcms/nitro/pkg/svc/mailhog"
+	"github.com/craftcms/nitro/pkg/svc/minio"
+	"github.com/craftcms/nitro/pkg/svc/redis"
+	"github.com/craftcms/nitro/protob"
+)
+
+// buildPlan reads the environment and returns the changes apply would make to
+// match the config, including the cleanup from PostRunE. It does not make any
+// changes to containers, images, the proxy, or the hosts file.
+func buildPlan(ctx context.Context, cmd *cobra.Command, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, cfg *config.Config) (*plan.Plan, error) {
+	p := &plan.Plan{Changes: []plan.Change{}}
+
+	// the network is created by init, so apply cannot continue without it
+	if _, err := findNetwork(ctx, docker); err != nil {
+		return nil, err
+	}
+
+	// check the proxy
+	change, err := proxycontainer.Plan(ctx, docker)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Add(change)
+
+	// check the databases
+	for _, db := range cfg.Databases {
+		change, err := databasecontainer.Plan(ctx, docker, db)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Add(change)
+	}
+
+	// check the services
+	services := []struct {
+		enabled bool
+		plan    func(context.Context, client.CommonAPIClient, bool) (plan.Change, error)
+	}{
+		{enabled: cfg.Services.DynamoDB, plan: dynamodb.Plan},
+		{enabled: cfg.Services.Mailhog, plan: mailhog.Plan},
+		{enabled: cfg.Services.Minio, plan: minio.Plan},
+		{enabled: cfg.Services.Redis, plan: redis.Plan},
+	}
+
+	for _, s := range services {
+		change, err := s.plan(ctx, docker, s.enabled)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Add(change)
+	}
+
+	// check the custom containers
+	for _, c := range cfg.Containers {
+		change, err := customcontainer.Plan(ctx, docker, home, c)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Add(change)
+	}
+
+	// check the sites
+	for _, site := range cfg.Sites {
+		change, err := sitecontainer.Plan(ctx, docker, home, site, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Add(change)
+	}
+
+	// check the routes for the proxy
+	p.Add(planRoutes(ctx, nitrod, cfg))
+
+	// check the hosts file
+	if skip, _ := cmd.Flags().GetBool("skip-hosts"); !skip && !skipHosts() {
+		change, err := planHosts(hostsFile(), expectedHostnames(cfg))
+		if err != nil {
+			return nil, err
+		}
+
+		p.Add(change)
+	}
+
+	// check for containers that are no longer in the config
+	if err := planCleanup(ctx, home, docker, cfg, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// planCleanup adds the containers and images that are removed after apply to the plan.
+func planCleanup(ctx context.Context, home string, docker client.CommonAPIClient, cfg *config.Config, p *plan.Plan) error {
+	// containers that are already being changed (e.g. disabled services) are skipped
+	planned := make(map[string]bool)
+	for _, c := range p.Changes {
+		if c.ID != "" {
+			planned[c.ID] = true
+		}
+	}
+
+	names := knownContainers(cfg)
+
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"=true")
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("error getting a list of containers")
+	}
+
+	for _, c := range containers {
+		// skip the proxy container
+		if c.Labels[containerlabels.Proxy] != "" || planned[c.ID] {
+			continue
+		}
+
+		name := containerlabels.Hostname(c.Names[0])
+		if names[name] {
+			continue
+		}
+
+		change := plan.Change{Action: plan.Remove, Kind: "container", Name: name, ID: c.ID}
+
+		// databases are backed up before they are removed
+		if c.Labels[containerlabels.DatabaseEngine] != "" {
+			change.Kind = "database"
+			change.Details = append(change.Details, "databases will be backed up to "+filepath.Join(home, config.DirectoryName, name))
+		}
+
+		p.Add(change)
+	}
+
+	// check for images with extensions that are no longer used
+	images, err := siteimage.Unused(ctx, docker, home, cfg.Sites)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range images {
+		// images replaced by a rebuild no longer have a tag
+		name := i.ID
+		if len(i.RepoTags) > 0 && i.RepoTags[0] != "<none>:<none>" {
+			name = i.RepoTags[0]
+		}
+
+		p.Add(plan.Change{Action: plan.Remove, Kind: "image", Name: name, ID: i.ID})
+	}
+
+	return nil
+}
+
+// planRoutes compares the routes the proxy serves with the sites in the config. When
+// the routes cannot be read from the API (e.g. the proxy is not running), every
+// route is shown as added.
+func planRoutes(ctx context.Context, nitrod protob.NitroClient, cfg *config.Config) plan.Change {
+	change := plan.Change{Action: plan.None, Kind: "proxy", Name: "routes"}
+
+	// apply does not update the proxy when there are no sites
+	sites := proxySites(cfg)
+	if len(sites) == 0 {
+		return change
+	}
+
+	// the upstream for each hostname and alias, the same as the API creates them
+	expected := make(map[string]string)
+	ports := make(map[string]int32)
+	for k, s := range sites {
+		hosts := []string{s.GetHostname()}
+		if s.GetAliases() != "" {
+			hosts = append(hosts, strings.Split(s.GetAliases(), ",")...)
+		}
+
+		for _, h := range hosts {
+			expected[h] = fmt.Sprintf("%s:%d", k, s.GetPort())
+			ports[h] = s.GetPort()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// the http server has the same routes, so only the https server is compared
+	live := make(map[string]string)
+	if resp, err := nitrod.Status(ctx, &protob.StatusRequest{}); err == nil {
+		for _, r := range resp.GetRoutes() {
+			if r.GetServer() == "https" {
+				live[r.GetHostname()] = r.GetUpstream()
+			}
+		}
+	}
+
+	for h, upstream := range expected {
+		current, ok := live[h]
+		switch {
+		case !ok:
+			change.Details = append(change.Details, fmt.Sprintf("+ %s → %d", h, ports[h]))
+		case current != upstream:
+			change.Details = append(change.Details, fmt.Sprintf("~ %s → %d", h, ports[h]))
+		}
+	}
+
+	for h := range live {
+		if _, ok := expected[h]; !ok {
+			change.Details = append(change.Details, "- "+h)
+		}
+	}
+
+	if len(change.Details) > 0 {
+		change.Action = plan.Update
+
+		// sort by the hostname
+		sort.Slice(change.Details, func(i, j int) bool {
+			return change.Details[i][2:] < change.Details[j][2:]
+		})
+	}
+
+	return change
+}
+
+// planHosts compares the hostnames in the hosts file with the expected hostnames.
+func planHosts(file string, hostnames []string) (plan.Change, error) {
+	change := plan.Change{Action: plan.None, Kind: "hosts", Name: file}
+
+	if len(hostnames) == 0 {
+		return change, nil
+	}
+
+	updated, err := hostedit.IsUpdated(file, "127.0.0.1", hostnames...)
+	if err != nil {
+		return change, err
+	}
+
+	if updated {
+		return change, nil
+	}
+
+	existing, err := hostedit.Hosts(file)
+	if err != nil {
+		return change, err
+	}
+
+	change.Action = plan.Update
+
+	current := make(map[string]bool)
+	for _, h := range existing {
+		current[h] = true
+	}
+
+	expected := make(map[string]bool)
+	for _, h := range hostnames {
+		expected[h] = true
+
+		if !current[h] {
+			change.Details = append(change.Details, "+ "+h)
+		}
+	}
+
+	for _, h := range existing {
+		if !expected[h] {
+			change.Details = append(change.Details, "- "+h)
+		}
+	}
+
+	return change, nil
+}
+
+// findNetwork returns the network for the environment.
+func findNetwork(ctx context.Context, docker client.CommonAPIClient) (types.NetworkResource, error) {
+	// create a filter for the environment
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"=true")
+
+	// add the filter for the network name
+	filter.Add("name", containerlabels.NetworkName())
+
+	networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
+	if err != nil {
+		return types.NetworkResource{}, fmt.Errorf("unable to list docker networks\n%w", err)
+	}
+
+	// get the network for the environment
+	for _, n := range networks {
+		if n.Name == containerlabels.NetworkName() {
+			return n, nil
+		}
+	}
+
+	return types.NetworkResource{}, fmt.Errorf("No network was found…\nrun `nitro init` to get started")
+}
+
+// knownContainers returns the hostnames of the containers in the config.
+func knownContainers(cfg *config.Config) map[string]bool {
+	names := map[string]bool{}
+
+	// get all of the sites as hostnames
+	for _, s := range cfg.Sites {
+		names[s.Hostname] = true
+	}
+
+	// get the containers as hostnames
+	for _, c := range cfg.Containers {
+		names[fmt.Sprintf("%s%s", c.Name, customcontainer.Suffix)] = true
+	}
+
+	// get all of the databases
+	for _, d := range cfg.Databases {
+		h, _ := d.GetHostname()
+		names[h] = true
+	}
+
+	// is dynamodb enabled
+	if cfg.Services.DynamoDB {
+		names[dynamodb.Host] = true
+	}
+
+	// is mailhog enabled
+	if cfg.Services.Mailhog {
+		names[mailhog.Host] = true
+	}
+
+	// is minio enabled
+	if cfg.Services.Minio {
+		names[minio.Host] = true
+	}
+
+	// is redis enabled
+	if cfg.Services.Redis {
+		names[redis.Host] = true
+	}
+
+	return names
+}
+
+// siteHostnames returns the hostnames and aliases for the sites and custom containers.
+func siteHostnames(cfg *config.Config) []string {
+	var hostnames []string
+	for _, s := range cfg.Sites {
+		hostnames = append(hostnames, s.Hostname)
+		hostnames = append(hostnames, s.Aliases...)
+	}
+
+	// get custom container hostnames
+	for _, c := range cfg.Containers {
+		hostnames = append(hostnames, fmt.Sprintf("%s.containers.nitro", c.Name))
+	}
+
+	return hostnames
+}
+
+// expectedHostnames returns the hostnames apply adds to the hosts file, in the same order.
+func expectedHostnames(cfg *config.Config) []string {
+	var hostnames []string
+	for _, d := range cfg.Databases {
+		h, err := d.GetHostname()
+		if err == nil {
+			hostnames = append(hostnames, h)
+		}
+	}
+
+	if cfg.Services.DynamoDB {
+		hostnames = append(hostnames, dynamodb.Host)
+	}
+
+	if cfg.Services.Mailhog {
+		hostnames = append(hostnames, mailhog.Host)
+	}
+
+	if cfg.Services.Minio {
+		hostnames = append(hostnames, minio.Host)
+	}
+
+	if cfg.Services.Redis {
+		hostnames = append(hostnames, redis.Host)
+	}
+
+	return append(hostnames, siteHostnames(cfg)...)
+}
+
+// hostsFile returns the path to the hosts file based on the OS.
+func hostsFile() string {
+	if runtime.GOOS == "windows" {
+		return `C:\Windows\System32\Drivers\etc\hosts`
+	}
+
+	return defaultFile
+}
+
+// skipHosts returns true if the hosts file should not be edited.
+func skipHosts() bool {
+	return os.Getenv("NITRO_EDIT_HOSTS") == "false"
+}
@@ -0,0 +1,99 @@
+package apply
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/plan"
+	"github.com/craftcms/nitro/protob"
+)
+
+func Test_planHosts(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "hosts")
+	if err := ioutil.WriteFile(file, []byte("127.0.0.1\tlocalhost\n# <nitro>\n127.0.0.1\tdemo.nitro old.nitro\n# </nitro>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		hostnames []string
+		want      plan.Change
+	}{
+		{
+			name:      "up to date hosts are not changed",
+			hostnames: []string{"demo.nitro", "old.nitro"},
+			want:      plan.Change{Action: plan.None, Kind: "hosts", Name: file},
+		},
+		{
+			name:      "added and removed hostnames are shown",
+			hostnames: []string{"demo.nitro", "new.nitro"},
+			want:      plan.Change{Action: plan.Update, Kind: "hosts", Name: file, Details: []string{"+ new.nitro", "- old.nitro"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planHosts(file, tt.hostnames)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planHosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type statusClient struct {
+	protob.NitroClient
+	routes []*protob.ProxyRoute
+}
+
+func (c *statusClient) Status(ctx context.Context, in *protob.StatusRequest, opts ...grpc.CallOption) (*protob.StatusResponse, error) {
+	return &protob.StatusResponse{Routes: c.routes}, nil
+}
+
+func Test_planRoutes(t *testing.T) {
+	cfg := &config.Config{Sites: []config.Site{{Hostname: "demo.nitro", Aliases: []string{"demo.test"}}}}
+
+	tests := []struct {
+		name   string
+		routes []*protob.ProxyRoute
+		want   plan.Change
+	}{
+		{
+			name: "routes that match the sites are not changed",
+			routes: []*protob.ProxyRoute{
+				{Hostname: "demo.nitro", Server: "https", Upstream: "demo.nitro:8080"},
+				{Hostname: "demo.test", Server: "https", Upstream: "demo.nitro:8080"},
+				{Hostname: "demo.nitro", Server: "node", Upstream: "demo.nitro:3000"},
+			},
+			want: plan.Change{Action: plan.None, Kind: "proxy", Name: "routes"},
+		},
+		{
+			name: "added, changed, and removed routes are shown",
+			routes: []*protob.ProxyRoute{
+				{Hostname: "demo.nitro", Server: "https", Upstream: "demo.nitro:9000"},
+				{Hostname: "old.nitro", Server: "https", Upstream: "old.nitro:8080"},
+			},
+			want: plan.Change{Action: plan.Update, Kind: "proxy", Name: "routes", Details: []string{"~ demo.nitro → 8080", "+ demo.test → 8080", "- old.nitro"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planRoutes(context.Background(), &statusClient{routes: tt.routes}, cfg)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
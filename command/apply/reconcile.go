@@ -0,0 +1,84 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Concurrency is the default number of resources that are reconciled at the same time.
+var Concurrency = 4
+
+// ErrSkipped is used for resources that were not reconciled because another resource failed.
+var ErrSkipped = fmt.Errorf("skipped because of a previous error")
+
+// job is a single resource to reconcile (e.g. a site or database). The output
+// passed to run only shows the status for the job, and the details returned
+// are shown once it finishes (e.g. why the container was recreated).
+type job struct {
+	name string
+	run  func(ctx context.Context, output terminal.Outputer) ([]string, error)
+}
+
+// reconcile runs the jobs with at most limit running at the same time and
+// returns the first error. Jobs that are already running are allowed to
+// finish, so containers are not left half created, but jobs that have not
+// started are skipped.
+func reconcile(ctx context.Context, progress *terminal.Progress, limit int, jobs []job) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	// add every job first so they are shown in order
+	tasks := make([]*terminal.Task, len(jobs))
+	for i, j := range jobs {
+		tasks[i] = progress.Add(j.name)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+	)
+
+	sem := make(chan struct{}, limit)
+
+	for i, j := range jobs {
+		wg.Add(1)
+
+		// wait for a slot so the jobs are started in order
+		sem <- struct{}{}
+
+		go func(j job, task *terminal.Task) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// check if another job failed before this one started
+			mu.Lock()
+			failed := first != nil
+			mu.Unlock()
+
+			if failed {
+				task.Finish(ErrSkipped)
+				return
+			}
+
+			details, err := j.run(ctx, task)
+			task.Finish(err, details...)
+
+			if err != nil {
+				mu.Lock()
+				if first == nil {
+					first = fmt.Errorf("unable to apply %s, %w", j.name, err)
+				}
+				mu.Unlock()
+			}
+		}(j, tasks[i])
+	}
+
+	wg.Wait()
+
+	return first
+}
@@ -0,0 +1,88 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+type nopOutput struct {
+	terminal.Outputer
+	mu    sync.Mutex
+	lines []string
+}
+
+func (o *nopOutput) Pending(s ...string) {}
+func (o *nopOutput) Done()               {}
+func (o *nopOutput) Warning()            {}
+func (o *nopOutput) Info(s ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.lines = append(o.lines, s...)
+}
+
+func Test_reconcile(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		running int
+		max     int
+	)
+
+	run := func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+		mu.Lock()
+		running++
+		if running > max {
+			max = running
+		}
+		mu.Unlock()
+
+		output.Pending("working")
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil, nil
+	}
+
+	var jobs []job
+	for i := 0; i < 10; i++ {
+		jobs = append(jobs, job{name: "site", run: run})
+	}
+
+	if err := reconcile(context.Background(), terminal.NewProgress(&nopOutput{}), 2, jobs); err != nil {
+		t.Fatalf("reconcile() unexpected error = %v", err)
+	}
+
+	if max > 2 {
+		t.Errorf("reconcile() ran %d jobs at the same time, want at most 2", max)
+	}
+}
+
+func Test_reconcileStopsAfterAnError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	var ran []string
+	jobs := []job{
+		{name: "mysql-8.0-3306.database.nitro", run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+			ran = append(ran, "mysql-8.0-3306.database.nitro")
+			return nil, errFailed
+		}},
+		{name: "demo.nitro", run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+			ran = append(ran, "demo.nitro")
+			return nil, nil
+		}},
+	}
+
+	err := reconcile(context.Background(), terminal.NewProgress(&nopOutput{}), 1, jobs)
+	if !errors.Is(err, errFailed) {
+		t.Errorf("reconcile() error = %v, want %v", err, errFailed)
+	}
+
+	if len(ran) != 1 {
+		t.Errorf("reconcile() expected the second job to be skipped, ran %v", ran)
+	}
+}
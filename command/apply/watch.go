@@ -0,0 +1,340 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/command/apply/internal/customcontainer"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/hooks"
+	"github.com/craftcms/nitro/pkg/hostedit"
+	"github.com/craftcms/nitro/pkg/plan"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/pkg/watch"
+	"github.com/craftcms/nitro/protob"
+)
+
+// changes are the sites and custom containers affected by a change to the
+// config, or to the env files they use.
+type changes struct {
+	sites             []config.Site
+	containers        []config.Container
+	removedSites      []string
+	removedContainers []string
+}
+
+// empty returns true if there are no sites or containers to reconcile.
+func (c changes) empty() bool {
+	return len(c.sites) == 0 && len(c.containers) == 0 && len(c.removedSites) == 0 && len(c.removedContainers) == 0
+}
+
+// watchConfig watches the config file, and the env files used by sites and
+// custom containers, and reconciles the affected sites and containers when
+// they change. It keeps running until interrupted and shows errors without
+// exiting.
+func watchConfig(cmd *cobra.Command, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// stop watching on ctrl+c
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	current, err := config.Load(home)
+	if err != nil {
+		return err
+	}
+
+	w := watch.New(watchedFiles(home, current)...)
+
+	output.Info("Watching", current.File, "for changes (press ctrl+c to stop)…")
+
+	for files := range w.Watch(ctx) {
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(f))
+		}
+
+		output.Info("Detected changes to", strings.Join(names, ", ")+"…")
+
+		next, err := config.Load(home)
+		if err != nil {
+			output.Info("Unable to load the config,", err.Error())
+			continue
+		}
+
+		// the env files can change with the config
+		w.Set(watchedFiles(home, next)...)
+
+		// keep the current config when there is an error so the changes are tried again
+		if err := applyChanges(ctx, cmd, home, docker, nitrod, output, current, next, files); err != nil {
+			output.Info("Unable to apply the changes,", err.Error())
+			continue
+		}
+
+		current = next
+
+		output.Info("Watching for changes…")
+	}
+
+	output.Info("Stopped watching for changes")
+
+	return nil
+}
+
+// applyChanges reconciles the sites and custom containers that changed between the configs
+// and updates the proxy routes.
+func applyChanges(ctx context.Context, cmd *cobra.Command, home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer, current, next *config.Config, files []string) error {
+	c := affected(home, current, next, files)
+
+	// check the php extensions before making any changes
+	for _, s := range c.sites {
+		if err := checkExtensions(s); err != nil {
+			return err
+		}
+	}
+
+	if err := validateHooks(next); err != nil {
+		return err
+	}
+
+	// databases and services have backups and hosts to manage, so they are left to apply
+	if !reflect.DeepEqual(current.Databases, next.Databases) || current.Services != next.Services {
+		output.Info("Run `nitro apply` to apply the changes to databases and services")
+	}
+
+	routes := !reflect.DeepEqual(proxySites(current), proxySites(next))
+
+	if c.empty() && !routes {
+		output.Info("No changes to sites or containers")
+		return nil
+	}
+
+	// make sure another apply is not changing containers
+	lock, err := lockApply(home, output)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	network, err := findNetwork(ctx, docker)
+	if err != nil {
+		return err
+	}
+
+	if runHooks(cmd) {
+		if err := hooks.Apply(ctx, docker, next, config.HookPreApply, output); err != nil {
+			return err
+		}
+
+		// the removed sites are only in the current config
+		for _, s := range current.Sites {
+			for _, h := range c.removedSites {
+				if s.Hostname != h {
+					continue
+				}
+
+				if err := hooks.Site(ctx, docker, home, s, config.HookPreDestroy, output); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var jobs []job
+	for _, ctr := range c.containers {
+		jobs = append(jobs, containerJob(cmd, home, docker, network.ID, ctr))
+	}
+
+	sites := make([]plan.Change, len(c.sites))
+	for i, s := range c.sites {
+		jobs = append(jobs, siteJob(cmd, home, docker, network.ID, s, next, &sites[i]))
+	}
+
+	for _, h := range c.removedSites {
+		filter := filters.NewArgs()
+		filter.Add("label", containerlabels.Nitro+"=true")
+		filter.Add("label", containerlabels.Host+"="+h)
+
+		jobs = append(jobs, removeJob(docker, h, filter))
+	}
+
+	for _, name := range c.removedContainers {
+		filter := filters.NewArgs()
+		filter.Add("label", containerlabels.Nitro+"=true")
+		filter.Add("label", containerlabels.NitroContainer+"="+name)
+
+		jobs = append(jobs, removeJob(docker, name+customcontainer.Suffix, filter))
+	}
+
+	limit, _ := cmd.Flags().GetInt("concurrency")
+
+	if err := reconcile(ctx, terminal.NewProgress(output), limit, jobs); err != nil {
+		return err
+	}
+
+	if runHooks(cmd) {
+		if err := siteHooks(ctx, home, docker, c.sites, sites, output); err != nil {
+			return err
+		}
+	}
+
+	if routes {
+		output.Pending("updating proxy")
+
+		if err := updateProxy(ctx, docker, nitrod, next); err != nil {
+			output.Warning()
+			return err
+		}
+
+		output.Done()
+	}
+
+	if runHooks(cmd) {
+		if err := hooks.Apply(ctx, docker, next, config.HookPostApply, output); err != nil {
+			return err
+		}
+	}
+
+	// editing the hosts file can prompt for a password, so leave it to apply
+	if skip, _ := cmd.Flags().GetBool("skip-hosts"); !skip && !skipHosts() {
+		if hostnames := expectedHostnames(next); len(hostnames) > 0 {
+			if updated, err := hostedit.IsUpdated(hostsFile(), "127.0.0.1", hostnames...); err == nil && !updated {
+				output.Info("Run `nitro apply` to add the new hostnames to the hosts file")
+			}
+		}
+	}
+
+	return nil
+}
+
+// affected compares the configs and returns the sites and custom containers
+// that were added, changed, or use one of the changed files, and the ones
+// that were removed. Changes to blackfire affect every site.
+func affected(home string, current, next *config.Config, files []string) changes {
+	var c changes
+
+	changed := make(map[string]bool)
+	for _, f := range files {
+		changed[f] = true
+	}
+
+	blackfire := current.Blackfire != next.Blackfire
+
+	// check the sites
+	sites := make(map[string]config.Site)
+	for _, s := range current.Sites {
+		sites[s.Hostname] = s
+	}
+
+	for _, s := range next.Sites {
+		prev, ok := sites[s.Hostname]
+		delete(sites, s.Hostname)
+
+		switch {
+		case !ok, blackfire, !reflect.DeepEqual(prev, s):
+			c.sites = append(c.sites, s)
+		case s.EnvFile != "":
+			if file, err := s.EnvFilePath(home); err == nil && changed[file] {
+				c.sites = append(c.sites, s)
+			}
+		}
+	}
+
+	for _, s := range current.Sites {
+		if _, ok := sites[s.Hostname]; ok {
+			c.removedSites = append(c.removedSites, s.Hostname)
+		}
+	}
+
+	// check the custom containers
+	containers := make(map[string]config.Container)
+	for _, ctr := range current.Containers {
+		containers[ctr.Name] = ctr
+	}
+
+	for _, ctr := range next.Containers {
+		prev, ok := containers[ctr.Name]
+		delete(containers, ctr.Name)
+
+		switch {
+		case !ok, !reflect.DeepEqual(prev, ctr):
+			c.containers = append(c.containers, ctr)
+		case ctr.EnvFile != "" && changed[containerEnvFile(home, ctr)]:
+			c.containers = append(c.containers, ctr)
+		}
+	}
+
+	for _, ctr := range current.Containers {
+		if _, ok := containers[ctr.Name]; ok {
+			c.removedContainers = append(c.removedContainers, ctr.Name)
+		}
+	}
+
+	return c
+}
+
+// watchedFiles returns the config file and the env files used by the sites and custom containers.
+func watchedFiles(home string, cfg *config.Config) []string {
+	files := []string{cfg.File}
+
+	for _, s := range cfg.Sites {
+		if s.EnvFile == "" {
+			continue
+		}
+
+		if file, err := s.EnvFilePath(home); err == nil {
+			files = append(files, file)
+		}
+	}
+
+	for _, c := range cfg.Containers {
+		if c.EnvFile != "" {
+			files = append(files, containerEnvFile(home, c))
+		}
+	}
+
+	return files
+}
+
+// containerEnvFile returns the path to the env file for a custom container (e.g. ~/.nitro/.elasticsearch).
+func containerEnvFile(home string, c config.Container) string {
+	return filepath.Join(home, config.DirectoryName, "."+c.Name)
+}
+
+// removeJob returns the job that stops and removes the containers matching the filter.
+func removeJob(docker client.CommonAPIClient, name string, filter filters.Args) job {
+	return job{name: name, run: func(ctx context.Context, output terminal.Outputer) ([]string, error) {
+		containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+		if err != nil {
+			return nil, fmt.Errorf("error getting a list of containers")
+		}
+
+		for _, c := range containers {
+			output.Pending("removing…")
+
+			if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+				return nil, err
+			}
+
+			if err := docker.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}}
+}
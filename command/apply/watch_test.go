@@ -0,0 +1,66 @@
+package apply
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func Test_affected(t *testing.T) {
+	home := filepath.Join("/", "home", "nitro")
+	site := config.Site{Hostname: "demo.nitro", Path: "~/dev/demo", Version: "8.0"}
+	envSite := config.Site{Hostname: "env.nitro", Path: "~/dev/env", Version: "8.0", EnvFile: ".env.nitro"}
+	container := config.Container{Name: "elasticsearch", Image: "elasticsearch", Tag: "7", EnvFile: ".elasticsearch"}
+
+	tests := []struct {
+		name    string
+		current *config.Config
+		next    *config.Config
+		files   []string
+		want    changes
+	}{
+		{
+			name:    "unchanged sites and containers are not affected",
+			current: &config.Config{Sites: []config.Site{site}, Containers: []config.Container{container}},
+			next:    &config.Config{Sites: []config.Site{site}, Containers: []config.Container{container}},
+			files:   []string{filepath.Join(home, config.DirectoryName, config.FileName)},
+		},
+		{
+			name:    "added and changed sites are affected",
+			current: &config.Config{Sites: []config.Site{site}},
+			next:    &config.Config{Sites: []config.Site{{Hostname: "demo.nitro", Path: "~/dev/demo", Version: "8.1"}, envSite}},
+			want:    changes{sites: []config.Site{{Hostname: "demo.nitro", Path: "~/dev/demo", Version: "8.1"}, envSite}},
+		},
+		{
+			name:    "changes to env files affect the sites and containers using them",
+			current: &config.Config{Sites: []config.Site{site, envSite}, Containers: []config.Container{container}},
+			next:    &config.Config{Sites: []config.Site{site, envSite}, Containers: []config.Container{container}},
+			files: []string{
+				filepath.Join(home, "dev", "env", ".env.nitro"),
+				filepath.Join(home, config.DirectoryName, ".elasticsearch"),
+			},
+			want: changes{sites: []config.Site{envSite}, containers: []config.Container{container}},
+		},
+		{
+			name:    "removed sites and containers are returned",
+			current: &config.Config{Sites: []config.Site{site, envSite}, Containers: []config.Container{container}},
+			next:    &config.Config{Sites: []config.Site{envSite}},
+			want:    changes{removedSites: []string{"demo.nitro"}, removedContainers: []string{"elasticsearch"}},
+		},
+		{
+			name:    "changes to blackfire affect every site",
+			current: &config.Config{Sites: []config.Site{site}},
+			next:    &config.Config{Sites: []config.Site{site}, Blackfire: config.Blackfire{ServerID: "id", ServerToken: "token"}},
+			want:    changes{sites: []config.Site{site}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := affected(home, tt.current, tt.next, tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("affected() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -82,7 +83,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for which interface to use
-			selected, err := output.Select(cmd.InOrStdin(), "Which IP address should we use for the bridge? ", interfaces)
+			selected, err := prompt.Select(cmd, output, "ip", "Which IP address should we use for the bridge? ", interfaces)
 			if err != nil {
 				return err
 			}
@@ -114,6 +115,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create the options for the sites
 			var options []string
 			for _, s := range sites {
@@ -137,7 +144,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(sites) {
 				case 0:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -161,7 +168,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					}
 				default:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -230,6 +237,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	}
 
 	cmd.Flags().String("port", "8000", "which port to use for the bridge")
+	cmd.Flags().String("ip", "", "which IP address to use for the bridge")
+
+	prompt.SiteFlag(cmd)
 
 	return cmd
 }
@@ -116,7 +116,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			// find the network
 			networkFilter := filters.NewArgs()
-			networkFilter.Add("name", "nitro-network")
+			networkFilter.Add("name", containerlabels.NetworkName())
 
 			// check if the network needs to be created
 			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
@@ -126,7 +126,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			var networkID string
 			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
+				if n.Name == containerlabels.NetworkName() || strings.TrimLeft(n.Name, "/") == containerlabels.NetworkName() {
 					networkID = n.ID
 				}
 			}
@@ -178,7 +178,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 				Path:   path,
 				NetworkConfig: &network.NetworkingConfig{
 					EndpointsConfig: map[string]*network.EndpointSettings{
-						"nitro-network": {
+						containerlabels.NetworkName(): {
 							NetworkID: networkID,
 						},
 					},
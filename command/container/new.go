@@ -26,7 +26,10 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
   nitro container new
 
   # expand the number of images from the search
-  nitro container new --limit 50`,
+  nitro container new --limit 50
+
+  # add a container without prompting (e.g. in scripts)
+  nitro container new --no-interaction --yes --image elasticsearch --tag 7.10.1 --ports 9200:9200 --ui-port 0 --volumes "" --name elasticsearch`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return prompt.VerifyInit(cmd, args, home, output)
 		},
@@ -46,39 +49,43 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
-			// ask for the image
-			resp, err := output.Ask("What image are you trying to add", "", "?", &validate.HostnameValidator{})
-			if err != nil {
-				return err
-			}
+			// use the image from the flag or search for one
+			image, _ := cmd.Flags().GetString("image")
+			if image == "" {
+				// ask for the image
+				resp, err := terminal.Flag(output, "image").Ask("What image are you trying to add", "", "?", &validate.HostnameValidator{})
+				if err != nil {
+					return err
+				}
 
-			// search for an image with the name
-			images, err := docker.ImageSearch(cmd.Context(), resp, types.ImageSearchOptions{Limit: limit})
-			if err != nil {
-				return err
-			}
+				// search for an image with the name
+				images, err := docker.ImageSearch(cmd.Context(), resp, types.ImageSearchOptions{Limit: limit})
+				if err != nil {
+					return err
+				}
 
-			// if there are no images return
-			if len(images) == 0 {
-				return fmt.Errorf("no images found matching %q", resp)
-			}
+				// if there are no images return
+				if len(images) == 0 {
+					return fmt.Errorf("no images found matching %q", resp)
+				}
 
-			// show the found images as a selection
-			options := []string{}
-			for _, i := range images {
-				options = append(options, i.Name)
-			}
+				// show the found images as a selection
+				options := []string{}
+				for _, i := range images {
+					options = append(options, i.Name)
+				}
 
-			// prompt for the image we found
-			selection, err := output.Select(cmd.InOrStdin(), "Which image should we use?", options)
-			if err != nil {
-				return err
-			}
+				// prompt for the image we found
+				selection, err := terminal.Flag(output, "image").Select(cmd.InOrStdin(), "Which image should we use?", options)
+				if err != nil {
+					return err
+				}
 
-			image := images[selection].Name
+				image = images[selection].Name
+			}
 
 			// ask for the tag (default to latest)
-			tag, err := output.Ask("What tag should we use?", "latest", "", nil)
+			tag, err := prompt.Ask(cmd, output, "tag", "What tag should we use?", "latest", "", nil)
 			if err != nil {
 				return err
 			}
@@ -116,87 +123,94 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return err
 			}
 
-			// get the ports for the image
-			ports := []string{}
-			for port := range imageSpecs.ContainerConfig.ExposedPorts {
-				// find the first available port
-				p, err := portavail.FindNext("", port.Port())
-				if err != nil {
-					return err
-				}
-
-				// should we prompt for the port to be exposed?
-				add, err := output.Confirm(fmt.Sprintf("Expose port `%s` on host?", p), true, "")
-				if err != nil {
-					return err
-				}
-
-				if add {
-					ports = append(ports, fmt.Sprintf("%s:%s", p, port.Port()))
-				}
-			}
-
-			exposesUI, err := output.Confirm("Does the image contain a web-based UI?", true, "")
-			if err != nil {
-				return err
-			}
-
-			var uiPort int
-			if exposesUI {
-				// format the ports to grab only the right side (container port)
-				opts := []string{}
-				for _, p := range ports {
-					p := strings.Split(p, ":")
-					opts = append(opts, p[len(p)-1])
-				}
-
-				switch len(opts) == 0 {
-				case false:
-					// prompt the user for the port
-					selected, err := output.Select(cmd.InOrStdin(), "Which port should we use for the UI?", opts)
-					if err != nil {
-						return err
-					}
-
-					clean := strings.Split(ports[selected], ":")
-
-					// get the container port not the host port
-					p, err := strconv.Atoi(clean[1])
+			// get the ports for the image, unless they are set with the flag
+			ports, _ := cmd.Flags().GetStringSlice("ports")
+			if !cmd.Flags().Changed("ports") {
+				for port := range imageSpecs.ContainerConfig.ExposedPorts {
+					// find the first available port
+					p, err := portavail.FindNext("", port.Port())
 					if err != nil {
 						return err
 					}
 
-					// assign the port
-					uiPort = p
-				default:
-					// ask for the tag (default to latest)
-					ask, err := output.Ask("Which port should we use for the UI", "", "?", &validate.IntegerValidator{})
+					// should we prompt for the port to be exposed?
+					add, err := terminal.Flag(output, "ports").Confirm(fmt.Sprintf("Expose port `%s` on host?", p), true, "")
 					if err != nil {
 						return err
 					}
 
-					// convert the port to an int
-					p, err := strconv.Atoi(ask)
-					if err != nil {
-						return err
+					if add {
+						ports = append(ports, fmt.Sprintf("%s:%s", p, port.Port()))
 					}
-
-					// assign the ui port
-					uiPort = p
 				}
 			}
 
-			// inspect the images volumes
-			var volumes []string
-			for v := range imageSpecs.ContainerConfig.Volumes {
-				// should we create a volume for the volume?
-				add, err := output.Confirm(fmt.Sprintf("Create volume `%q` for container?", v), true, "")
+			// get the port for the ui, unless it is set with the flag
+			uiPort, _ := cmd.Flags().GetInt("ui-port")
+			if !cmd.Flags().Changed("ui-port") {
+				exposesUI, err := terminal.Flag(output, "ui-port").Confirm("Does the image contain a web-based UI?", true, "")
 				if err != nil {
 					return err
 				}
 
-				if add {
-					volumes = append(volumes, v)
+				if exposesUI {
+					// format the ports to grab only the right side (container port)
+					opts := []string{}
+					for _, p := range ports {
+						p := strings.Split(p, ":")
+						opts = append(opts, p[len(p)-1])
+					}
+
+					switch len(opts) == 0 {
+					case false:
+						// prompt the user for the port
+						selected, err := terminal.Flag(output, "ui-port").Select(cmd.InOrStdin(), "Which port should we use for the UI?", opts)
+						if err != nil {
+							return err
+						}
+
+						clean := strings.Split(ports[selected], ":")
+
+						// get the container port not the host port
+						p, err := strconv.Atoi(clean[1])
+						if err != nil {
+							return err
+						}
+
+						// assign the port
+						uiPort = p
+					default:
+						// ask for the tag (default to latest)
+						ask, err := terminal.Flag(output, "ui-port").Ask("Which port should we use for the UI", "", "?", &validate.IntegerValidator{})
+						if err != nil {
+							return err
+						}
+
+						// convert the port to an int
+						p, err := strconv.Atoi(ask)
+						if err != nil {
+							return err
+						}
+
+						// assign the ui port
+						uiPort = p
+					}
+				}
+			}
+
+			// inspect the images volumes, unless they are set with the flag
+			volumes, _ := cmd.Flags().GetStringSlice("volumes")
+			if !cmd.Flags().Changed("volumes") {
+				for v := range imageSpecs.ContainerConfig.Volumes {
+					// should we create a volume for the volume?
+					add, err := terminal.Flag(output, "volumes").Confirm(fmt.Sprintf("Create volume `%q` for container?", v), true, "")
+					if err != nil {
+						return err
+					}
+
+					if add {
+						volumes = append(volumes, v)
+					}
 				}
 			}
 
@@ -207,7 +221,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for the container name
-			name, err := output.Ask("What is the name of the container?", suggested, "", &validate.HostnameValidator{})
+			name, err := prompt.Ask(cmd, output, "name", "What is the name of the container?", suggested, "", &validate.HostnameValidator{})
 			if err != nil {
 				return err
 			}
@@ -223,7 +237,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// setup a custom env file?
-			createEnvfile, err := output.Confirm("Create a file to add environment variables?", true, "")
+			createEnvfile, err := prompt.Confirm(cmd, output, "env-file", "Create a file to add environment variables?", true)
 			if err != nil {
 				return err
 			}
@@ -262,6 +276,14 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	}
 
 	cmd.Flags().Int("limit", 10, "number of images to return from the registry")
+	cmd.Flags().String("image", "", "the image to use instead of searching (e.g. elasticsearch)")
+	cmd.Flags().String("tag", "", "the tag for the image (e.g. latest)")
+	cmd.Flags().StringSlice("ports", nil, "the ports to expose on the host as <host>:<container> (e.g. 9200:9200)")
+	cmd.Flags().Int("ui-port", 0, "the container port for the web-based UI, 0 when there is no UI")
+	cmd.Flags().StringSlice("volumes", nil, "the paths in the container to create volumes for")
+	cmd.Flags().String("name", "", "the name of the container")
+	cmd.Flags().Bool("env-file", true, "create a file to add environment variables")
+	cmd.Flags().Bool("skip-apply", false, "skip applying changes")
 
 	return cmd
 }
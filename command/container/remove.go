@@ -41,7 +41,7 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 			}
 
 			// prompt for the container to remove
-			selected, err := output.Select(cmd.InOrStdin(), "Select the custom container to remove: ", options)
+			selected, err := prompt.Select(cmd, output, "name", "Select the custom container to remove: ", options)
 			if err != nil {
 				return err
 			}
@@ -65,5 +65,7 @@ func removeCommand(home string, docker client.CommonAPIClient, output terminal.O
 		},
 	}
 
+	cmd.Flags().String("name", "", "the name of the custom container to remove")
+
 	return cmd
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -16,7 +15,10 @@ const exampleText = `  # view all resources for the environment
   nitro context
 
   # show only the config file
-  nitro context --yaml`
+  nitro context --yaml
+
+  # show the environment as json
+  nitro context --output json`
 
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -35,6 +37,11 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return yamlFmt(cfg)
 			}
 
+			// show the details as json for scripts
+			if j, ok := terminal.JSONOutput(output); ok {
+				return j.Result(details(cmd.Root().Version, cfg))
+			}
+
 			output.Info("Craft Nitro", cmd.Root().Version)
 			output.Info("")
 			output.Info("Configuration:\t", cfg.File)
@@ -70,17 +77,62 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	return cmd
 }
 
-func yamlFmt(cfg *config.Config) error {
-	// redact blackfire credentials
-	if cfg.Blackfire.ServerID != "" {
-		cfg.Blackfire.ServerID = "****************"
+// environment is the json output for the context.
+type environment struct {
+	Version   string     `json:"version"`
+	Config    string     `json:"config"`
+	Sites     []site     `json:"sites"`
+	Databases []database `json:"databases"`
+}
+
+type site struct {
+	Hostname string   `json:"hostname"`
+	Aliases  []string `json:"aliases"`
+	PHP      string   `json:"php"`
+	Webroot  string   `json:"webroot"`
+	Path     string   `json:"path"`
+}
+
+type database struct {
+	Engine   string `json:"engine"`
+	Version  string `json:"version"`
+	Hostname string `json:"hostname"`
+	Port     string `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// details returns the sites and databases in the config for the json output.
+func details(version string, cfg *config.Config) environment {
+	env := environment{Version: version, Config: cfg.File, Sites: []site{}, Databases: []database{}}
+
+	for _, s := range cfg.Sites {
+		aliases := s.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+
+		env.Sites = append(env.Sites, site{Hostname: s.Hostname, Aliases: aliases, PHP: s.Version, Webroot: s.Webroot, Path: s.Path})
 	}
-	if cfg.Blackfire.ServerToken != "" {
-		cfg.Blackfire.ServerToken = "********************************"
+
+	for _, db := range cfg.Databases {
+		hostname, _ := db.GetHostname()
+		env.Databases = append(env.Databases, database{
+			Engine:   db.Engine,
+			Version:  db.Version,
+			Hostname: hostname,
+			Port:     db.Port,
+			Username: "nitro",
+			Password: "nitro",
+		})
 	}
 
-	// marshal into the struct version so we can remove the blackfire credentials
-	data, err := yaml.Marshal(cfg)
+	return env
+}
+
+func yamlFmt(cfg *config.Config) error {
+	// show references instead of secrets and redact the blackfire credentials
+	data, err := cfg.Redacted()
 	if err != nil {
 		return err
 	}
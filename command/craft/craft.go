@@ -14,6 +14,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -54,6 +55,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create the options for the sites
 			var options []string
 			for _, s := range sites {
@@ -64,7 +71,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch len(sites) {
 			case 0:
 				// prompt for the site
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -84,7 +91,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -161,6 +168,8 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
 
@@ -27,7 +27,10 @@ const exampleText = `  # create a new default craft project (similar to "compose
   nitro create https://github.com/craftcms/demo my-project
 
   # you can also provide shorthand urls for github
-  nitro create craftcms/demo my-project`
+  nitro create craftcms/demo my-project
+
+  # create a project without prompting (e.g. in scripts)
+  nitro create my-project --no-interaction --yes --php 8.0 --database-engine mysql-8.0-3306.database.nitro --database-name my_project`
 
 // NewCommand returns the create command to automate the process of setting up a new Craft project.
 // It also allows you to pass an option argument that is a URL to your own github repo.
@@ -112,7 +115,7 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 			}
 
 			// walk the user through the site
-			_, err := prompt.CreateSite(home, dir, output)
+			_, err := prompt.CreateSite(cmd, home, dir, output)
 			if err != nil {
 				return err
 			}
@@ -128,7 +131,7 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 			// if the wanted a new database edit the env
 			if database && pathexists.IsFile(envFilePath) {
 				// ask the user if we should update the .env?
-				updateEnv, err := output.Confirm("Should we update the env file?", true, "")
+				updateEnv, err := prompt.Confirm(cmd, output, "update-env", "Should we update the env file?", true)
 				if err != nil {
 					return err
 				}
@@ -195,5 +198,9 @@ func NewCommand(home string, docker client.CommonAPIClient, getter downloader.Ge
 		},
 	}
 
+	prompt.SiteFlags(cmd)
+	prompt.DatabaseFlags(cmd)
+	cmd.Flags().Bool("update-env", true, "update the env file with the database settings")
+
 	return cmd
 }
@@ -2,7 +2,6 @@ package database
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
@@ -14,13 +13,17 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/protob"
 )
 
 var addExampleTest = `  # add a new database
-  nitro db add`
+  nitro db add
+
+  # add a new database without prompts
+  nitro db add --engine mysql-8.0-3306.database.nitro --name my-project`
 
 func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -47,11 +50,11 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 			// get all of the containers as a list
 			var engineOpts []string
 			for _, c := range containers {
-				engineOpts = append(engineOpts, strings.TrimLeft(c.Names[0], "/"))
+				engineOpts = append(engineOpts, containerlabels.Hostname(c.Names[0]))
 			}
 
 			// prompt the user for the engine to add the database
-			selected, err := output.Select(os.Stdin, "Select the database engine: ", engineOpts)
+			selected, err := prompt.Select(cmd, output, "engine", "Select the database engine: ", engineOpts)
 			if err != nil {
 				return err
 			}
@@ -63,7 +66,7 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 			}
 
 			// ask the user for the database to create
-			db, err := output.Ask("Enter the new database name", "", ":", &validate.DatabaseName{})
+			db, err := prompt.Ask(cmd, output, "name", "Enter the new database name", "", ":", &validate.DatabaseName{})
 			if err != nil {
 				return err
 			}
@@ -107,7 +110,7 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 				output.Warning()
 
 				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
+				confirm, err := prompt.Confirm(cmd, output, "update", "The API does not appear to be updated. Run `nitro update` now?", true)
 				if err != nil {
 					return err
 				}
@@ -140,5 +143,9 @@ func addCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, output
 		},
 	}
 
+	cmd.Flags().String("engine", "", "The database engine to add the database to (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().String("name", "", "The name of the new database")
+	cmd.Flags().Bool("update", true, "Run the update command if the API is out of date")
+
 	return cmd
 }
@@ -2,6 +2,8 @@ package database
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,26 +14,53 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/craftcms/nitro/pkg/backup"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/datetime"
 	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
 )
 
 var backupExampleText = `  # backup a database
-  nitro db backup`
+  nitro db backup
 
-// backupCommand is the command for backing up an individual database or
-func backupCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
+  # backup a database to a file
+  nitro db backup ~/Desktop/backup.sql.gz
+
+  # write the backup to stdout
+  nitro db backup --engine mysql-8.0-3306.database.nitro --name my_project > backup.sql.gz
+
+  # backup a database without prompting (e.g. in scripts)
+  nitro db backup --no-interaction --engine mysql-8.0-3306.database.nitro --name my_project`
+
+// backupCommand is the command for backing up an individual database. The backup is compressed
+// with gzip and is written to the file, to stdout when the file is - or stdout is redirected, or
+// to the backups directory.
+func backupCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "backup",
+		Use:     "backup [file]",
 		Short:   "Backs up a database.",
+		Args:    cobra.MaximumNArgs(1),
 		Example: backupExampleText,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			var file string
+			if len(args) > 0 {
+				file = args[0]
+			}
+
+			// stdout is used for the backup, so there are no messages or prompts
+			stdout := file == "-" || (file == "" && !terminal.IsTTY(os.Stdout))
+			out := output
+			if stdout {
+				out = terminal.Discard()
+			}
+
 			// add filters to show only the environment and database containers
 			filter := filters.NewArgs()
 			filter.Add("label", containerlabels.Nitro)
@@ -51,52 +80,133 @@ func backupCommand(home string, docker client.CommonAPIClient, output terminal.O
 			// generate a list of engines for the prompt
 			var containerList []string
 			for _, c := range containers {
-				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
+				containerList = append(containerList, containerlabels.Hostname(c.Names[0]))
 			}
 
-			output.Info("Getting ready to backup…")
+			out.Info("Getting ready to backup…")
 
 			// get the container id, name, and database from the user
-			containerID, containerName, compatibility, db, err := backup.Prompt(ctx, os.Stdin, docker, output, containers, containerList)
+			engine, _ := cmd.Flags().GetString("engine")
+			name, _ := cmd.Flags().GetString("name")
+
+			containerID, containerName, compatibility, db, err := backup.Prompt(ctx, cmd.InOrStdin(), docker, out, containers, containerList, engine, name)
+			if err != nil {
+				return err
+			}
+
+			out.Info("Preparing backup…")
+
+			// get the containers info
+			info, err := docker.ContainerInspect(ctx, containerID)
+			if err != nil {
+				return err
+			}
+
+			// use the backups directory when there is no file
+			if file == "" && !stdout {
+				dir := filepath.Join(home, config.DirectoryName, "backups", containerName)
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("unable to create the backups directory, %w", err)
+				}
+
+				file = filepath.Join(dir, fmt.Sprintf("%s-%s.sql.gz", db, datetime.Parse(time.Now())))
+			}
+
+			stream, err := nitrod.ExportDatabase(ctx, &protob.ExportDatabaseRequest{
+				Database: &protob.DatabaseInfo{
+					Database: db,
+					Engine:   compatibility,
+					Hostname: strings.TrimLeft(info.Name, "/"),
+					Port:     databasePort(info),
+					Version:  info.Config.Labels[containerlabels.DatabaseVersion],
+				},
+			})
 			if err != nil {
 				return err
 			}
 
-			output.Info("Preparing backup…")
+			out.Pending("creating backup of", db)
+
+			if stdout {
+				if err := receiveBackup(stream, os.Stdout); err != nil {
+					return err
+				}
+
+				return nil
+			}
+
+			// write to a temp file so a failed backup does not leave a partial file
+			temp, err := ioutil.TempFile(filepath.Dir(file), ".nitro-backup")
+			if err != nil {
+				out.Warning()
 
-			// create the options for the backup
-			opts := &backup.Options{
-				BackupName:    fmt.Sprintf("%s-%s.sql", db, datetime.Parse(time.Now())),
-				ContainerID:   containerID,
-				ContainerName: containerName,
-				Database:      db,
-				Home:          home,
+				return fmt.Errorf("unable to create the backup file, %w", err)
 			}
+			defer os.Remove(temp.Name())
+
+			if err := receiveBackup(stream, temp); err != nil {
+				temp.Close()
+				out.Warning()
 
-			// create the backup command based on the compatibility type
-			switch compatibility {
-			case "postgres":
-				opts.Commands = []string{"pg_dump", "--username=nitro", db, "-f", "/tmp/" + opts.BackupName}
-			default:
-				opts.Commands = []string{"mysqldump", "--user=nitro", "-pnitro", db, "--result-file=" + "/tmp/" + opts.BackupName}
+				return err
 			}
 
-			output.Pending("creating backup", opts.BackupName)
+			if err := temp.Close(); err != nil {
+				out.Warning()
+
+				return fmt.Errorf("unable to save the backup, %w", err)
+			}
 
-			// perform the backup
-			if err := backup.Perform(ctx, docker, opts); err != nil {
-				output.Warning()
+			if err := os.Rename(temp.Name(), file); err != nil {
+				out.Warning()
 
-				return fmt.Errorf("unable to backup the database, %w", err)
+				return fmt.Errorf("unable to save the backup, %w", err)
 			}
 
-			output.Done()
+			out.Done()
 
-			output.Info("Backup saved in", filepath.Join(opts.Home, config.DirectoryName, "backups", opts.ContainerName), "💾")
+			out.Info("Backup saved to", file, "💾")
 
 			return nil
 		},
 	}
 
+	cmd.Flags().String("engine", "", "The database engine to backup (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().String("name", "", "The database to backup")
+
 	return cmd
 }
+
+// receiveBackup writes the chunks of the backup from the API to w.
+func receiveBackup(stream protob.Nitro_ExportDatabaseClient, w io.Writer) error {
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if code := status.Code(err); code == codes.Unimplemented {
+			return fmt.Errorf("the API does not support backups, run `nitro update` and try again")
+		}
+		if err != nil {
+			return fmt.Errorf("unable to backup the database, %s", status.Convert(err).Message())
+		}
+
+		if _, err := w.Write(resp.GetData()); err != nil {
+			return fmt.Errorf("unable to write the backup, %w", err)
+		}
+	}
+}
+
+// databasePort returns the port the database container uses (e.g. 3306).
+func databasePort(info types.ContainerJSON) string {
+	var port string
+	for p, bind := range info.HostConfig.PortBindings {
+		for _, v := range bind {
+			if v.HostPort != "" {
+				port = p.Port()
+			}
+		}
+	}
+
+	return port
+}
@@ -50,7 +50,7 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 			}
 
 			// prompt for the database
-			selected, err := output.Select(cmd.InOrStdin(), "Select database to destroy: ", options)
+			selected, err := prompt.Select(cmd, output, "engine", "Select database to destroy: ", options)
 			if err != nil {
 				return err
 			}
@@ -74,5 +74,7 @@ func destroyCommand(home string, docker client.CommonAPIClient, output terminal.
 		},
 	}
 
+	cmd.Flags().String("engine", "", "The database engine to destroy (e.g. mysql-8.0-3306.database.nitro)")
+
 	return cmd
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/craftcms/nitro/pkg/database"
 	"github.com/craftcms/nitro/pkg/filetype"
 	"github.com/craftcms/nitro/pkg/pathexists"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 	"github.com/craftcms/nitro/protob"
@@ -33,9 +34,10 @@ var importExampleText = `  # import a sql file into a database
   nitro db import ~/Desktop/backup.sql
 
   # use an absolute path
-  nitro db import /Users/oli/Desktop/backup.sql`
+  nitro db import /Users/oli/Desktop/backup.sql
 
-var nameFlag string
+  # import without prompting (e.g. in scripts)
+  nitro db import backup.sql --no-interaction --engine mysql-8.0-3306.database.nitro --name my_project`
 
 // importCommand is the command for creating new development environments
 func importCommand(home string, docker client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -141,12 +143,12 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 					}
 				}
 
-				options = append(options, strings.TrimLeft(c.Names[0], "/"))
+				options = append(options, containerlabels.Hostname(c.Names[0]))
 			}
 
 			// prompt the user for the engine to import the backup into
 			var containerID string
-			selected, err := output.Select(os.Stdin, "Select a database engine: ", options)
+			selected, err := prompt.Select(cmd, output, "engine", "Select a database engine: ", options)
 			if err != nil {
 				return err
 			}
@@ -157,34 +159,10 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				return fmt.Errorf("unable to get the container")
 			}
 
-			validator := &validate.DatabaseName{}
-
-			var db string
-			switch nameFlag == "" {
-			case false:
-				// validate the flag value
-				err := validator.Validate(nameFlag)
-				if err != nil {
-					// ask the user for the database to import because the flag was not valid
-					input, err := output.Ask("Enter the database name", "", ":", validator)
-					if err != nil {
-						return err
-					}
-
-					db = input
-					break
-				}
-
-				// the flag value is valid, so assign it
-				db = nameFlag
-			default:
-				// ask the user for the database to import
-				input, err := output.Ask("Enter the database name", "", ":", validator)
-				if err != nil {
-					return err
-				}
-
-				db = input
+			// ask the user for the database to import, unless the flag is set
+			db, err := prompt.Ask(cmd, output, "name", "Enter the database name", "", ":", &validate.DatabaseName{})
+			if err != nil {
+				return err
 			}
 
 			output.Info("Preparing import…")
@@ -200,15 +178,8 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 			hostname := strings.TrimLeft(info.Name, "/")
 			version := info.Config.Labels[containerlabels.DatabaseVersion]
 
-			var port string
 			// get the port from the container info
-			for p, bind := range info.HostConfig.PortBindings {
-				for _, v := range bind {
-					if v.HostPort != "" {
-						port = p.Port()
-					}
-				}
-			}
+			port := databasePort(info)
 
 			stream, err := nitrod.ImportDatabase(cmd.Context())
 			// check if the error code is unimplemented
@@ -216,7 +187,7 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				output.Warning()
 
 				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
+				confirm, err := prompt.Confirm(cmd, output, "update", "The API does not appear to be updated. Run `nitro update` now?", true)
 				if err != nil {
 					return err
 				}
@@ -260,7 +231,7 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 				output.Warning()
 
 				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
+				confirm, err := prompt.Confirm(cmd, output, "update", "The API does not appear to be updated. Run `nitro update` now?", true)
 				if err != nil {
 					return err
 				}
@@ -293,23 +264,60 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 			if err != nil {
 				return err
 			}
+			defer file.Close()
+
+			// get the size of the file to show the upload progress
+			stat, err := file.Stat()
+			if err != nil {
+				return err
+			}
+
+			task := terminal.NewProgress(output).Add(fmt.Sprintf("importing database %q into %q", db, hostname))
+
+			// receive the progress of the import while the file is sent
+			var reply string
+			var details []string
+			done := make(chan error, 1)
+			go func() {
+				for {
+					resp, err := stream.Recv()
+					if err == io.EOF {
+						done <- nil
+						return
+					}
+					if err != nil {
+						done <- err
+						return
+					}
+
+					// show the output from the import tool under the task
+					if resp.GetStderr() != "" {
+						details = append(details, "    "+resp.GetStderr())
+					}
+
+					if resp.GetMessage() != "" {
+						reply = resp.GetMessage()
+					}
+
+					task.Info(importStatus(resp, stat.Size()))
+				}
+			}()
 
 			// create a buffer to handle large files more gracefully
 			buffer := make([]byte, 1024*20)
 			reader := bufio.NewReader(file)
 
-			output.Pending(fmt.Sprintf("importing database %q into %q", db, hostname))
-
-			// stream to backup file to the api
+			// stream to backup file to the api, when the api returns an error sending
+			// fails and the error is returned from receiving
 			for {
 				n, err := reader.Read(buffer)
 				if err == io.EOF {
 					break
 				}
 				if err != nil {
-					output.Warning()
+					task.Finish(err)
 
-					return stream.RecvMsg(nil)
+					return err
 				}
 
 				// send the chunked file data in pieces
@@ -318,29 +326,65 @@ func importCommand(home string, docker client.CommonAPIClient, nitrod protob.Nit
 						Data: buffer[:n],
 					},
 				}); err != nil {
-					output.Warning()
-
-					return err
+					break
 				}
 			}
 
-			// handle the response
-			reply, err := stream.CloseAndRecv()
-			if err != nil {
-				output.Warning()
+			if err := stream.CloseSend(); err != nil {
+				task.Finish(err)
 
-				return stream.RecvMsg(nil)
+				return err
 			}
 
-			output.Done()
+			// wait for the import to complete
+			err = <-done
 
-			output.Info(fmt.Sprintf("%s in %.2f seconds 💪", reply.Message, time.Since(start).Seconds()))
+			task.Finish(err, details...)
+
+			if err != nil {
+				return fmt.Errorf("unable to import the database, %s", status.Convert(err).Message())
+			}
+
+			output.Info(fmt.Sprintf("%s in %.2f seconds 💪", reply, time.Since(start).Seconds()))
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&nameFlag, "name", "", "The database name to import into")
+	cmd.Flags().String("name", "", "The database name to import into")
+	cmd.Flags().String("engine", "", "The database engine to import into (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().Bool("update", true, "Run the update command if the API is out of date")
 
 	return cmd
 }
+
+// importStatus returns the status of the import to show next to the task, size is
+// the size of the file being uploaded.
+func importStatus(resp *protob.ImportDatabaseResponse, size int64) string {
+	switch resp.GetStage() {
+	case "receiving":
+		return fmt.Sprintf("uploading %s %s of %s", terminal.Bar(resp.GetBytes(), size), byteSize(resp.GetBytes()), byteSize(size))
+	case "decompressing":
+		return "decompressing…"
+	case "importing":
+		return fmt.Sprintf("importing %s %d statements", terminal.Bar(resp.GetBytes(), resp.GetTotal()), resp.GetStatements())
+	}
+
+	return ""
+}
+
+// byteSize returns the size in a readable format (e.g. 1.5 MB).
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for i := n / unit; i >= unit; i /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
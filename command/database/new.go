@@ -43,7 +43,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt for the engine
-			selection, err := output.Select(cmd.InOrStdin(), "Which database engine should we use?", options)
+			selection, err := prompt.Select(cmd, output, "engine", "Which database engine should we use?", options)
 			if err != nil {
 				return err
 			}
@@ -52,7 +52,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			engine := options[selection]
 
 			// ask for the version
-			version, err := output.Ask("Which version should we use?", "", "", nil)
+			version, err := prompt.Ask(cmd, output, "version", "Which version should we use?", "", "", nil)
 			if err != nil {
 				return err
 			}
@@ -73,7 +73,7 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// confirm the port to use
-			port, err := output.Ask("Which port should we use for "+engine+"?", p, "", nil)
+			port, err := prompt.Ask(cmd, output, "port", "Which port should we use for "+engine+"?", p, "", nil)
 			if err != nil {
 				return err
 			}
@@ -94,5 +94,9 @@ func newCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().String("engine", "", "The database engine to add (e.g. mysql)")
+	cmd.Flags().String("version", "", "The version of the database engine (e.g. 8.0)")
+	cmd.Flags().String("port", "", "The port for the database engine (e.g. 3306)")
+
 	return cmd
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/backup"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/protob"
 )
@@ -47,11 +48,11 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 			// generate a list of engines for the prompt
 			var containerList []string
 			for _, c := range containers {
-				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
+				containerList = append(containerList, containerlabels.Hostname(c.Names[0]))
 			}
 
 			// prompt the user for which database to backup
-			selectedEngine, err := output.Select(cmd.InOrStdin(), "Which database engine? ", containerList)
+			selectedEngine, err := prompt.Select(cmd, output, "engine", "Which database engine? ", containerList)
 			if err != nil {
 				return err
 			}
@@ -83,7 +84,7 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 			}
 
 			// ask the user which database
-			selected, err := output.Select(cmd.InOrStdin(), "Which database should we remove? ", databases)
+			selected, err := prompt.Select(cmd, output, "name", "Which database should we remove? ", databases)
 			if err != nil {
 				return err
 			}
@@ -115,7 +116,7 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 				output.Warning()
 
 				// ask if the update command should run
-				confirm, err := output.Confirm("The API does not appear to be updated. Run `nitro update` now?", true, "")
+				confirm, err := prompt.Confirm(cmd, output, "update", "The API does not appear to be updated. Run `nitro update` now?", true)
 				if err != nil {
 					return err
 				}
@@ -148,5 +149,9 @@ func removeCommand(docker client.CommonAPIClient, nitrod protob.NitroClient, out
 		},
 	}
 
+	cmd.Flags().String("engine", "", "The database engine to remove the database from (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().String("name", "", "The name of the database to remove")
+	cmd.Flags().Bool("update", true, "Run the update command if the API is out of date")
+
 	return cmd
 }
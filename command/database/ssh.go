@@ -4,7 +4,6 @@ import (
 	"os"
 	"os/exec"
 	"sort"
-	"strings"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/terminal"
@@ -53,7 +52,7 @@ func sshCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					}
 				}
 
-				containerList = append(containerList, strings.TrimLeft(c.Names[0], "/"))
+				containerList = append(containerList, containerlabels.Hostname(c.Names[0]))
 			}
 
 			// prompt for the container to ssh into
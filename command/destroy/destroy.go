@@ -6,7 +6,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -18,6 +17,7 @@ import (
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/datetime"
+	"github.com/craftcms/nitro/pkg/hooks"
 	"github.com/craftcms/nitro/pkg/sudo"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -52,7 +52,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// prompt the user for confirmation
-			confirm, err := output.Confirm("Are you sure? (This will remove all containers, volumes, and networks.)", false, "")
+			confirm, err := terminal.Flag(output, "yes").Confirm("Are you sure? (This will remove all containers, volumes, and networks.)", false, "")
 			if err != nil {
 				return err
 			}
@@ -109,7 +109,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("Removing Containers…")
 
 				for _, c := range containers {
-					name := strings.TrimLeft(c.Names[0], "/")
+					name := containerlabels.Hostname(c.Names[0])
 
 					// only perform a backup if the container is for databases
 					if c.Labels[containerlabels.DatabaseEngine] != "" {
@@ -165,6 +165,15 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 						output.Info("Backups saved in", filepath.Join(home, config.DirectoryName, name), "💾")
 					}
 
+					// run the hooks before a sites container is removed, failures do not stop the destroy
+					if skip, _ := cmd.Flags().GetBool("skip-hooks"); !skip {
+						if s, err := cfg.FindSiteByHostName(c.Labels[containerlabels.Host]); err == nil {
+							if err := hooks.Site(ctx, docker, home, *s, config.HookPreDestroy, output); err != nil {
+								output.Info(err.Error())
+							}
+						}
+					}
+
 					// stop the container
 					output.Pending("removing", name)
 
@@ -233,7 +242,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch runtime.GOOS {
 			case "windows":
 				// windows users should be running as admin, so just execute the hosts command as is
-				c := exec.Command(nitro, "hosts", "remove")
+				c := exec.Command(nitro, "hosts", "remove", "--env="+config.Environment)
 
 				c.Stdout = os.Stdout
 				c.Stderr = os.Stderr
@@ -245,7 +254,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Info("Updating hosts file (you might be prompted for your password)")
 
 				// add the hosts
-				if err := sudo.Run(nitro, "nitro", "hosts", "remove"); err != nil {
+				if err := sudo.Run(nitro, "nitro", "hosts", "remove", "--env="+config.Environment); err != nil {
 					return err
 				}
 			}
@@ -259,5 +268,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	// add flags to the command
 	cmd.Flags().Bool("clean", false, "remove configuration file")
 
+	cmd.Flags().Bool("skip-hooks", false, "skip running the pre_destroy hooks for sites")
+
 	return cmd
 }
@@ -3,7 +3,6 @@ package extensions
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/phpextensions"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -55,6 +55,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create the options for the sites
 			var options []string
 			for _, s := range sites {
@@ -66,7 +72,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch len(sites) {
 			case 0:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -80,7 +86,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
 				// prompt for the site to ssh into
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -112,45 +118,18 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// set the hostname of the site based on the container name
-			hostname := strings.TrimLeft(containers[0].Names[0], "/")
-
-			extensions := []string{
-				"bcmath",
-				"bz2",
-				"calendar",
-				"dba",
-				"enchant",
-				"exif",
-				"gettext",
-				"gmp",
-				"imap",
-				"interbase",
-				"ldap",
-				"mysqli",
-				"oci8",
-				"odbc",
-				"pcntl",
-				"pdo_dblib",
-				"pdo_firebird",
-				"pdo_oci",
-				"pdo_odbc",
-				"pdo_sqlite",
-				"recode",
-				"shmop",
-				"snmp",
-				"sockets",
-				"sysvmsg",
-				"sysvsem",
-				"sysvshm",
-				"tidy",
-				"wddx",
-				"xmlrpc",
-				"xsl",
-				"zend_test",
+			hostname := containerlabels.Hostname(containers[0].Names[0])
+
+			site, err := cfg.FindSiteByHostName(hostname)
+			if err != nil {
+				return err
 			}
 
+			// get the extensions available for the sites php version
+			extensions := phpextensions.Available(site.Version)
+
 			// which extensions to add
-			selected, err := output.Select(cmd.InOrStdin(), "Which PHP extension would you like to enable for "+hostname+"? ", extensions)
+			selected, err := prompt.Select(cmd, output, "extension", "Which PHP extension would you like to enable for "+hostname+"? ", extensions)
 			if err != nil {
 				return err
 			}
@@ -172,5 +151,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().String("extension", "", "the PHP extension to enable (e.g. redis)")
+
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
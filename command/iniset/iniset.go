@@ -3,7 +3,6 @@ package iniset
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -18,13 +17,12 @@ import (
 	"github.com/craftcms/nitro/pkg/validate"
 )
 
-var (
-	// ErrUnknownSetting is used when an unknown service is requested
-	ErrUnknownSetting = fmt.Errorf("unknown setting requested")
-)
-
 const exampleText = `  # change PHP settings for a site
-  nitro iniset`
+  nitro iniset
+
+  # change any php.ini setting for a site
+  nitro iniset tutorial.nitro date.timezone America/Chicago
+  nitro iniset tutorial.nitro session.gc_maxlifetime 86400`
 
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -64,6 +62,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create a filter for the environment
 			filter := filters.NewArgs()
 			filter.Add("label", containerlabels.Nitro)
@@ -94,7 +98,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				switch len(sites) {
 				case 0:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -108,7 +112,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 				default:
 					// prompt for the site to ssh into
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -141,183 +145,42 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			}
 
 			// set the hostname of the site based on the container name
-			hostname := strings.TrimLeft(containers[0].Names[0], "/")
+			hostname := containerlabels.Hostname(containers[0].Names[0])
 
-			settings := []string{
-				"display_errors",
-				"max_execution_time",
-				"max_input_vars",
-				"max_input_time",
-				"max_file_upload",
-				"memory_limit",
-				"opcache_enable",
-				"opcache_revalidate_freq",
-				"opcache_validate_timestamps",
-				"post_max_size",
-				"upload_max_file_size",
-			}
-
-			// which setting to change
-			selected, err := output.Select(cmd.InOrStdin(), "Which PHP setting would you like to change for "+hostname+"?", settings)
+			// get the current settings for the site
+			current, err := cfg.FindSiteByHostName(hostname)
 			if err != nil {
 				return err
 			}
 
-			// get the specific setting to change
-			setting := settings[selected]
-
-			// prompt the user for the setting to change
-			switch setting {
-			case "display_errors":
-				value, err := output.Ask("Should we display PHP errors?", "true", "", &validate.IsBoolean{})
-				if err != nil {
-					return err
-				}
-
-				// convert to bool
-				display, err := strconv.ParseBool(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPBoolSetting(hostname, setting, display); err != nil {
-					return err
-				}
-			case "max_execution_time":
-				value, err := output.Ask("What should the max execution time be?", config.DefaultEnvs["PHP_MAX_EXECUTION_TIME"], "", &validate.MaxExecutionTime{})
-				if err != nil {
-					return err
-				}
-
-				v, err := strconv.Atoi(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPIntSetting(hostname, setting, v); err != nil {
-					return err
-				}
-			case "max_input_vars":
-				value, err := output.Ask("What should the max input vars be?", config.DefaultEnvs["PHP_MAX_INPUT_VARS"], "", &validate.MaxExecutionTime{})
-				if err != nil {
-					return err
-				}
-
-				v, err := strconv.Atoi(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPIntSetting(hostname, setting, v); err != nil {
-					return err
-				}
-			case "max_input_time":
-				value, err := output.Ask("What should the max input time be?", config.DefaultEnvs["PHP_MAX_INPUT_TIME"], "", &validate.MaxExecutionTime{})
-				if err != nil {
-					return err
-				}
-
-				v, err := strconv.Atoi(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPIntSetting(hostname, setting, v); err != nil {
-					return err
-				}
-			case "max_file_upload":
-				value, err := output.Ask("What should the new max file upload be?", config.DefaultEnvs["PHP_UPLOAD_MAX_FILESIZE"], "", &validate.IsMegabyte{})
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPStrSetting(hostname, setting, value); err != nil {
-					return err
-				}
-			case "memory_limit":
-				value, err := output.Ask("What should the new memory limit be?", config.DefaultEnvs["PHP_MEMORY_LIMIT"], "", &validate.IsMegabyte{})
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPStrSetting(hostname, setting, value); err != nil {
-					return err
-				}
-			case "opcache_enable":
-				value, err := output.Ask("Should we enable OPcache?", "false", "", &validate.IsBoolean{})
-				if err != nil {
-					return err
-				}
-
-				// convert to bool
-				display, err := strconv.ParseBool(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPBoolSetting(hostname, setting, display); err != nil {
-					return err
-				}
-			case "opcache_validate_timestamps":
-				value, err := output.Ask("Should we validate timestamps with OPcache?", "false", "", &validate.IsBoolean{})
-				if err != nil {
-					return err
-				}
-
-				// convert to bool
-				display, err := strconv.ParseBool(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPBoolSetting(hostname, setting, display); err != nil {
-					return err
-				}
-			case "opcache_revalidate_freq":
-				value, err := output.Ask("What should the OPcache revalidate frequency be?", config.DefaultEnvs["PHP_OPCACHE_REVALIDATE_FREQ"], "", &validate.MaxExecutionTime{})
-				if err != nil {
-					return err
-				}
-
-				v, err := strconv.Atoi(value)
-				if err != nil {
-					return err
-				}
-
-				// change the value because its validated
-				if err := cfg.SetPHPIntSetting(hostname, setting, v); err != nil {
+			// which setting to change
+			var directive string
+			if len(args) > 1 {
+				directive = strings.TrimSpace(args[1])
+				if err := (&validate.PHPINIDirective{}).Validate(directive); err != nil {
 					return err
 				}
-			case "post_max_size":
-				value, err := output.Ask("What should post max size be?", config.DefaultEnvs["PHP_POST_MAX_SIZE"], "", &validate.IsMegabyte{})
+			} else {
+				directive, err = output.Ask("Which PHP setting would you like to change for "+hostname+" (e.g. memory_limit)?", "", "", &validate.PHPINIDirective{})
 				if err != nil {
 					return err
 				}
+			}
 
-				// change the value because its validated
-				if err := cfg.SetPHPStrSetting(hostname, setting, value); err != nil {
-					return err
-				}
-			case "upload_max_file_size":
-				value, err := output.Ask("What should upload maximum file size be?", config.DefaultEnvs["PHP_UPLOAD_MAX_FILESIZE"], "", &validate.IsMegabyte{})
+			// get the new value for the setting
+			var value string
+			if len(args) > 2 {
+				value = strings.TrimSpace(args[2])
+			} else {
+				value, err = output.Ask("What should "+directive+" be?", current.INI[directive], "", &validate.PHPINI{Directive: directive})
 				if err != nil {
 					return err
 				}
+			}
 
-				// change the value because its validated
-				if err := cfg.SetPHPStrSetting(hostname, setting, value); err != nil {
-					return err
-				}
-			default:
-				return ErrUnknownSetting
+			// change the value, it is validated by type if the setting is known
+			if err := cfg.SetPHPINISetting(hostname, directive, value); err != nil {
+				return err
 			}
 
 			// save the config file
@@ -329,5 +192,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
 
+	"github.com/craftcms/nitro/pkg/apiauth"
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/proxycontainer"
@@ -19,7 +20,10 @@ import (
 )
 
 const exampleText = `  # setup nitro
-  nitro init`
+  nitro init
+
+  # setup nitro without prompts
+  nitro init --no-interaction --mysql 8.0 --postgres none --redis=false`
 
 var skipApply, skipTrust bool
 
@@ -48,7 +52,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			_, err := config.Load(home)
 			if errors.Is(err, config.ErrNoConfigFile) {
 				// walk the user through the first time setup
-				if err := setup.FirstTime(home, cmd.InOrStdin(), output); err != nil {
+				if err := setup.FirstTime(cmd, home, output); err != nil {
 					return err
 				}
 			}
@@ -57,7 +61,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// create filters for the development environment
 			filter := filters.NewArgs()
-			filter.Add("name", "nitro-network")
+			filter.Add("name", containerlabels.NetworkName())
 
 			// check if the network needs to be created
 			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: filter})
@@ -70,7 +74,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			var skipNetwork bool
 			var networkID string
 			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
+				if n.Name == containerlabels.NetworkName() || strings.TrimLeft(n.Name, "/") == containerlabels.NetworkName() {
 					skipNetwork = true
 					networkID = n.ID
 				}
@@ -83,7 +87,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			default:
 				output.Pending("creating network")
 
-				resp, err := docker.NetworkCreate(ctx, "nitro-network", types.NetworkCreate{
+				resp, err := docker.NetworkCreate(ctx, containerlabels.NetworkName(), types.NetworkCreate{
 					Driver:     "bridge",
 					Attachable: true,
 					Labels: map[string]string{
@@ -101,8 +105,14 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				output.Done()
 			}
 
+			// create the token and certificate that secure the nitrod API
+			creds, err := apiauth.Ensure(home)
+			if err != nil {
+				return err
+			}
+
 			// create the proxy container
-			if err := proxycontainer.Create(cmd.Context(), docker, output, networkID); err != nil {
+			if err := proxycontainer.Create(cmd.Context(), docker, output, networkID, creds); err != nil {
 				return err
 			}
 
@@ -137,5 +147,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	cmd.Flags().BoolVar(&skipApply, "skip-apply", false, "skip applying changes")
 	cmd.Flags().BoolVar(&skipTrust, "skip-trust", false, "skip trusting the root certificate")
 
+	setup.Flags(cmd)
+
 	return cmd
 }
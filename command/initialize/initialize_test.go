@@ -1,11 +1,13 @@
 package initialize
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/craftcms/nitro/pkg/apiauth"
 	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -28,8 +30,20 @@ func TestInitFromFreshCreatesNewResources(t *testing.T) {
 	mock.containerCreateResponse = container.ContainerCreateCreatedBody{
 		ID: "testingid",
 	}
-	home, _ := os.Getwd()
-	home = filepath.Join(home, "testdata")
+	// copy the config so the credentials are created in a temp directory
+	home := t.TempDir()
+	cfg, err := ioutil.ReadFile(filepath.Join("testdata", ".nitro", "nitro.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".nitro"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(home, ".nitro", "nitro.yaml"), cfg, 0644); err != nil {
+		t.Fatal(err)
+	}
 
 	// Expected
 	// set the network create request
@@ -128,13 +142,21 @@ func TestInitFromFreshCreatesNewResources(t *testing.T) {
 
 	// Act
 	cmd := NewCommand(home, mock, spyOutputer{})
-	err := cmd.RunE(cmd, os.Args)
+	err = cmd.RunE(cmd, os.Args)
 
 	// Assert
 	if err != nil {
 		t.Errorf("expected the error to be nil, got %v", err)
 	}
 
+	// the proxy is given the credentials created for the nitrod API
+	creds, err := apiauth.Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	containerCreateReq.Config.Env = append(containerCreateReq.Config.Env, creds.Env()...)
+
 	// make sure the network create matches the expected
 	if !reflect.DeepEqual(mock.networkCreateRequests[0], networkReq) {
 		t.Errorf(
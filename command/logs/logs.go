@@ -12,6 +12,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -53,6 +54,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create the options for the sites
 			var options []string
 			for _, s := range sites {
@@ -61,7 +68,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			switch len(sites) {
 			case 0:
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -72,7 +79,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 				filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 			default:
-				selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+				selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 				if err != nil {
 					return err
 				}
@@ -127,5 +134,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	cmd.Flags().Bool("timestamps", false, "show timestamps")
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
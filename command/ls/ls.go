@@ -22,12 +22,24 @@ const exampleText = `  # view information about your nitro environment
   nitro ls --databases
 
   # show only sites
-  nitro ls --sites`
+  nitro ls --sites
+
+  # show the containers as json
+  nitro ls --output json`
 
 var (
 	flagCustom, flagDatabases, flagProxy, flagServices, flagSites bool
 )
 
+// item is a container shown in the list, it is used for the table and json output.
+type item struct {
+	Hostname      string   `json:"hostname"`
+	Type          string   `json:"type"`
+	InternalPorts []string `json:"internal_ports"`
+	ExternalPorts []string `json:"external_ports"`
+	Status        string   `json:"status"`
+}
+
 func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outputer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "ls",
@@ -50,9 +62,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return containers[i].Names[0] < containers[j].Names[0]
 			})
 
-			// define the table headers
-			tbl := table.New("Hostname", "Type", "Internal Ports", "External Ports", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
-
+			items := []item{}
 			for _, c := range containers {
 				status := "running"
 				if c.State == "exited" {
@@ -92,7 +102,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 
 				// get the ports
-				var intPorts, extPorts []string
+				intPorts, extPorts := []string{}, []string{}
 
 				// get ports for the non-site containers
 				switch c.Labels[containerlabels.Host] == "" {
@@ -124,10 +134,25 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					return extPorts[i] < extPorts[j]
 				})
 
-				internalPorts := strings.Join(intPorts, ",")
-				externalPorts := strings.Join(extPorts, ",")
+				items = append(items, item{
+					Hostname:      containerlabels.Hostname(c.Names[0]),
+					Type:          containerlabels.Identify(c),
+					InternalPorts: intPorts,
+					ExternalPorts: extPorts,
+					Status:        status,
+				})
+			}
+
+			// show the containers as json for scripts
+			if j, ok := terminal.JSONOutput(output); ok {
+				return j.Result(items)
+			}
+
+			// define the table headers
+			tbl := table.New("Hostname", "Type", "Internal Ports", "External Ports", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
 
-				tbl.AddRow(strings.TrimLeft(c.Names[0], "/"), containerlabels.Identify(c), internalPorts, externalPorts, status)
+			for _, i := range items {
+				tbl.AddRow(i.Hostname, i.Type, strings.Join(i.InternalPorts, ","), strings.Join(i.ExternalPorts, ","), i.Status)
 			}
 
 			tbl.Print()
@@ -1,6 +1,8 @@
 package nitro
 
 import (
+	gocontext "context"
+	"fmt"
 	"log"
 	"os"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/craftcms/nitro/command/npm"
 	"github.com/craftcms/nitro/command/php"
 	"github.com/craftcms/nitro/command/portcheck"
+	"github.com/craftcms/nitro/command/proxy"
 	"github.com/craftcms/nitro/command/queue"
 	"github.com/craftcms/nitro/command/remove"
 	"github.com/craftcms/nitro/command/restart"
@@ -44,7 +47,10 @@ import (
 	"github.com/craftcms/nitro/command/version"
 	"github.com/craftcms/nitro/command/xoff"
 	"github.com/craftcms/nitro/command/xon"
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerlabels"
 	"github.com/craftcms/nitro/pkg/downloader"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/client"
 	"github.com/mitchellh/go-homedir"
@@ -57,16 +63,65 @@ var rootCommand = &cobra.Command{
 	Long: `Nitro is a console-based tool that manages Docker for local PHP development.
 
 Version: ` + version.Version,
-	RunE:         rootMain,
-	SilenceUsage: true,
-	Version:      version.Version,
+	RunE:          rootMain,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Version:       version.Version,
 }
 
 func rootMain(command *cobra.Command, _ []string) error {
 	return command.Help()
 }
 
+// migrateConfig loads the config, which upgrades files from older versions
+// of nitro, and shows the changes that were made. Commands are responsible
+// for handling a missing or invalid config, so those errors are ignored.
+func migrateConfig(home string, output terminal.Outputer) error {
+	cfg, err := config.Load(home)
+	if err != nil {
+		return nil
+	}
+
+	if len(cfg.Migrations) == 0 {
+		return nil
+	}
+
+	output.Info(fmt.Sprintf("Upgraded the config to version %d, the original was saved to %s", cfg.Version, cfg.Backup))
+	for _, m := range cfg.Migrations {
+		output.Info("  -", m)
+	}
+
+	return nil
+}
+
+// Execute runs the nitro command and shows the error, errors are written as an
+// error event when using the json output so scripts can read them.
+func Execute() error {
+	cmd, term := newCommand()
+
+	err := cmd.Execute()
+	if err == nil {
+		return nil
+	}
+
+	if j, ok := terminal.JSONOutput(term); ok {
+		j.Error(err)
+	} else {
+		cmd.PrintErrln("Error:", err.Error())
+	}
+
+	return err
+}
+
+// NewCommand returns the nitro command with all of the commands and plugins.
 func NewCommand() *cobra.Command {
+	cmd, _ := newCommand()
+
+	return cmd
+}
+
+// newCommand returns the nitro command and the output used by the commands.
+func newCommand() (*cobra.Command, terminal.Outputer) {
 	// get the users home directory
 	home, err := homedir.Dir()
 	if err != nil {
@@ -79,21 +134,17 @@ func NewCommand() *cobra.Command {
 		log.Fatal(err)
 	}
 
-	// get the port for the nitrod API
-	apiPort := "5000"
-	if os.Getenv("NITRO_API_PORT") != "" {
-		apiPort = os.Getenv("NITRO_API_PORT")
-	}
+	// create the "terminal" for capturing output
+	term := terminal.New()
 
-	// create the nitrod gRPC API
-	nitrod, err := nitroclient.NewClient("127.0.0.1", apiPort)
+	// create the nitrod gRPC API, the address is the API port of the proxy for the selected environment
+	nitrod, err := nitroclient.NewClient(func(ctx gocontext.Context) (string, error) {
+		return proxycontainer.APIAddr(ctx, docker)
+	}, home)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// create the "terminal" for capturing output
-	term := terminal.New()
-
 	// create the downloaded for creating projects
 	downloader := downloader.NewDownloader()
 
@@ -124,6 +175,7 @@ func NewCommand() *cobra.Command {
 		npm.NewCommand(docker, term),
 		php.NewCommand(home, docker, term),
 		portcheck.NewCommand(term),
+		proxy.NewCommand(home, nitrod, term),
 		queue.NewCommand(home, docker, term),
 		remove.NewCommand(home, docker, term),
 		restart.NewCommand(home, docker, term),
@@ -143,5 +195,61 @@ func NewCommand() *cobra.Command {
 	// add the commands
 	rootCommand.AddCommand(commands...)
 
-	return rootCommand
+	// add the nitro-<name> executables on the PATH as commands, listed after the nitro commands in the help
+	rootCommand.AddCommand(pluginCommands(rootCommand, home, docker)...)
+	rootCommand.SetUsageTemplate(usageTemplate)
+
+	// select the environment, each environment has its own config, network, proxy, volumes, and hosts section.
+	// the environments use the same ports, so starting one environment stops the containers for the others
+	rootCommand.PersistentFlags().String("env", os.Getenv("NITRO_ENV"), "the environment to use (e.g. client-a), defaults to $NITRO_ENV or nitro")
+
+	// show machine readable output for scripts and editor plugins
+	rootCommand.PersistentFlags().String("output", terminal.FormatText, "the output format, text or json")
+
+	// never prompt for input, prompts use the default or return an error naming the flag to use
+	rootCommand.PersistentFlags().Bool("no-interaction", os.Getenv("NITRO_NO_INTERACTION") == "true", "do not prompt for input, defaults to $NITRO_NO_INTERACTION")
+
+	// answer the confirmations (e.g. apply changes now?) for scripts
+	rootCommand.PersistentFlags().Bool("yes", false, "answer yes to the confirmations without prompting")
+
+	// upgrade older config files before running any command
+	rootCommand.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		env, err := cmd.Flags().GetString("env")
+		if err != nil {
+			return err
+		}
+
+		if err := config.SetEnvironment(env); err != nil {
+			return err
+		}
+
+		containerlabels.UseEnvironment(config.Environment)
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		if err := term.SetFormat(format); err != nil {
+			return err
+		}
+
+		noInteraction, err := cmd.Flags().GetBool("no-interaction")
+		if err != nil {
+			return err
+		}
+
+		term.SetNoInteraction(noInteraction)
+
+		yes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return err
+		}
+
+		term.SetYes(yes)
+
+		return migrateConfig(home, term)
+	}
+
+	return rootCommand, term
 }
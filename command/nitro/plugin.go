@@ -0,0 +1,227 @@
+package nitro
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/plugin"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
+)
+
+// pluginAnnotation marks the commands that run a plugin, the value is the path to the executable.
+const pluginAnnotation = "nitro-plugin"
+
+// usageTemplate is the cobra usage template with the plugins listed after the nitro commands.
+const usageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}
+
+Available Commands:{{range .Commands}}{{if (and (or .IsAvailableCommand (eq .Name "help")) (not (isPlugin .)))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if hasPlugins .}}
+
+Plugins:{{range .Commands}}{{if isPlugin .}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+`
+
+func init() {
+	cobra.AddTemplateFunc("isPlugin", isPlugin)
+	cobra.AddTemplateFunc("hasPlugins", func(cmd *cobra.Command) bool {
+		for _, c := range cmd.Commands() {
+			if isPlugin(c) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+func isPlugin(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[pluginAnnotation]
+	return ok
+}
+
+// globalFlags are the root flags passed to plugins as environment variables.
+var globalFlags = []string{"output", "no-interaction", "yes"}
+
+// pluginCommands returns a command for each plugin found on the PATH, plugins cannot
+// replace the nitro commands so plugins with the same name are skipped.
+func pluginCommands(root *cobra.Command, home string, docker client.CommonAPIClient) []*cobra.Command {
+	existing := map[string]bool{"help": true}
+	for _, c := range root.Commands() {
+		existing[c.Name()] = true
+
+		for _, a := range c.Aliases {
+			existing[a] = true
+		}
+	}
+
+	var commands []*cobra.Command
+	for _, p := range plugin.Find(os.Getenv("PATH")) {
+		if existing[p.Name] {
+			continue
+		}
+
+		p := p
+
+		// the args for the plugin, without the nitro flags
+		var pluginArgs []string
+
+		commands = append(commands, &cobra.Command{
+			Use:                p.Name,
+			Short:              fmt.Sprintf("Runs the %s plugin.", filepath.Base(p.Path)),
+			Annotations:        map[string]string{pluginAnnotation: p.Path},
+			DisableFlagParsing: true,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveDefault
+			},
+			// flag parsing is disabled so the plugin gets its own flags, the nitro
+			// flags (e.g. --env) are set before the root command selects the environment
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				var err error
+				pluginArgs, err = setPersistentFlags(cmd.Root().PersistentFlags(), args)
+				if err != nil {
+					return err
+				}
+
+				return cmd.Root().PersistentPreRunE(cmd, pluginArgs)
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+
+				// the API is only available when the proxy for the environment is running
+				var apiPort string
+				if addr, err := proxycontainer.APIAddr(cmd.Context(), docker); err == nil {
+					_, apiPort, _ = net.SplitHostPort(addr)
+				}
+
+				env := pluginEnv(home, wd, docker.DaemonHost(), apiPort)
+				for _, name := range globalFlags {
+					env = append(env, "NITRO_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_"))+"="+cmd.Root().PersistentFlags().Lookup(name).Value.String())
+				}
+
+				c := exec.Command(p.Path, pluginArgs...)
+				c.Stdin = os.Stdin
+				c.Stdout = os.Stdout
+				c.Stderr = os.Stderr
+				c.Env = append(os.Environ(), env...)
+
+				if err := c.Run(); err != nil {
+					// exit with the plugins exit code, it has already shown the error
+					var exit *exec.ExitError
+					if errors.As(err, &exit) {
+						os.Exit(exit.ExitCode())
+					}
+
+					return fmt.Errorf("unable to run the %s plugin, %w", p.Name, err)
+				}
+
+				return nil
+			},
+		})
+	}
+
+	return commands
+}
+
+// setPersistentFlags sets the nitro flags (e.g. --env client) found in the args for a
+// plugin and returns the remaining args. Args after -- are passed to the plugin as is.
+func setPersistentFlags(flags *pflag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return append(rest, args[i+1:]...), nil
+		}
+
+		if !strings.HasPrefix(args[i], "--") {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		name, value := strings.TrimPrefix(args[i], "--"), ""
+		hasValue := false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+
+		f := flags.Lookup(name)
+		if f == nil {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		switch {
+		case hasValue:
+		case f.Value.Type() == "bool":
+			value = "true"
+		case i+1 < len(args):
+			i++
+			value = args[i]
+		default:
+			return nil, fmt.Errorf("the flag --%s needs a value", name)
+		}
+
+		if err := flags.Set(name, value); err != nil {
+			return nil, fmt.Errorf("unable to set the flag --%s, %w", name, err)
+		}
+	}
+
+	return rest, nil
+}
+
+// pluginEnv returns the environment variables that give plugins the context for
+// the environment. The site is only set when the working directory is in a single
+// site, and the API port when the proxy for the environment is running.
+func pluginEnv(home, wd, dockerHost, apiPort string) []string {
+	env := []string{
+		"NITRO_HOME=" + home,
+		"NITRO_ENV=" + config.Environment,
+		"NITRO_CONFIG=" + filepath.Join(home, config.DirectoryName, config.FileName),
+		"DOCKER_HOST=" + dockerHost,
+	}
+
+	if apiPort != "" {
+		env = append(env, "NITRO_API_PORT="+apiPort)
+	}
+
+	cfg, err := config.Load(home)
+	if err != nil {
+		return env
+	}
+
+	if sites := cfg.ListOfSitesByDirectory(home, wd); len(sites) == 1 {
+		env = append(env, "NITRO_SITE="+sites[0].Hostname)
+	}
+
+	return env
+}
@@ -0,0 +1,78 @@
+package nitro
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func Test_setPersistentFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantEnv string
+		wantYes bool
+		wantErr bool
+	}{
+		{
+			name:    "flags for nitro are removed from the args",
+			args:    []string{"--env", "client-a", "x", "--yes"},
+			want:    []string{"x"},
+			wantEnv: "client-a",
+			wantYes: true,
+		},
+		{
+			name:    "flags with a value after an equals sign are set",
+			args:    []string{"x", "--env=client-a", "--yes=false"},
+			want:    []string{"x"},
+			wantEnv: "client-a",
+		},
+		{
+			name:    "flags for the plugin are kept",
+			args:    []string{"--name", "demo", "-v"},
+			want:    []string{"--name", "demo", "-v"},
+			wantEnv: "nitro",
+		},
+		{
+			name:    "args after the separator are passed as is",
+			args:    []string{"x", "--", "--env", "client-a"},
+			want:    []string{"x", "--env", "client-a"},
+			wantEnv: "nitro",
+		},
+		{
+			name:    "a flag without a value returns an error",
+			args:    []string{"--env"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("nitro", pflag.ContinueOnError)
+			flags.String("env", "nitro", "")
+			flags.Bool("yes", false, "")
+
+			got, err := setPersistentFlags(flags, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("setPersistentFlags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setPersistentFlags() = %v, want %v", got, tt.want)
+			}
+
+			if env, _ := flags.GetString("env"); env != tt.wantEnv {
+				t.Errorf("expected the env flag to be %q, got %q", tt.wantEnv, env)
+			}
+
+			if yes, _ := flags.GetBool("yes"); yes != tt.wantYes {
+				t.Errorf("expected the yes flag to be %v, got %v", tt.wantYes, yes)
+			}
+		})
+	}
+}
@@ -86,7 +86,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			// find the network
 			networkFilter := filters.NewArgs()
-			networkFilter.Add("name", "nitro-network")
+			networkFilter.Add("name", containerlabels.NetworkName())
 
 			// check if the network needs to be created
 			networks, err := docker.NetworkList(ctx, types.NetworkListOptions{Filters: networkFilter})
@@ -96,7 +96,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 
 			var networkID string
 			for _, n := range networks {
-				if n.Name == "nitro-network" || strings.TrimLeft(n.Name, "/") == "nitro-network" {
+				if n.Name == containerlabels.NetworkName() || strings.TrimLeft(n.Name, "/") == containerlabels.NetworkName() {
 					networkID = n.ID
 				}
 			}
@@ -173,7 +173,7 @@ func NewCommand(docker client.CommonAPIClient, output terminal.Outputer) *cobra.
 			if networkID != "" {
 				networkConfig = &network.NetworkingConfig{
 					EndpointsConfig: map[string]*network.EndpointSettings{
-						"nitro-network": {
+						containerlabels.NetworkName(): {
 							NetworkID: networkID,
 						},
 					},
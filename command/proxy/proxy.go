@@ -0,0 +1,29 @@
+package proxy
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+const exampleText = `  # show the routes and upstreams for the proxy
+  nitro proxy status`
+
+// NewCommand returns the proxy commands for inspecting the proxy container
+func NewCommand(home string, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "proxy",
+		Short:   "Manages the proxy.",
+		Example: exampleText,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		statusCommand(home, nitrod, output),
+	)
+
+	return cmd
+}
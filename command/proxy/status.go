@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/craftcms/nitro/pkg/caddy"
+	"github.com/craftcms/nitro/pkg/prompt"
+	"github.com/craftcms/nitro/pkg/terminal"
+	"github.com/craftcms/nitro/protob"
+)
+
+var statusExampleText = `  # show the hostnames the proxy serves and if each upstream is up
+  nitro proxy status
+
+  # show the routes as json
+  nitro proxy status --output json`
+
+// route is a hostname served by the proxy, it is used for the table and json output.
+type route struct {
+	Hostname string   `json:"hostname"`
+	Server   string   `json:"server"`
+	Listen   []string `json:"listen"`
+	TLS      bool     `json:"tls"`
+	Upstream string   `json:"upstream"`
+	Healthy  bool     `json:"healthy"`
+	Optional bool     `json:"optional"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// statusCommand shows the routes from the live Caddy config in the proxy and if each
+// upstream accepts connections, it is used to debug 502 responses from the proxy.
+func statusCommand(home string, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Displays the proxy routes and upstreams.",
+		Example: statusExampleText,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return prompt.VerifyInit(cmd, args, home, output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := nitrod.Status(cmd.Context(), &protob.StatusRequest{})
+			if code := status.Code(err); code == codes.Unimplemented {
+				return fmt.Errorf("the API does not support the proxy status, run `nitro update` and try again")
+			}
+			if err != nil {
+				return fmt.Errorf("unable to get the proxy status, %s", status.Convert(err).Message())
+			}
+
+			routes := []route{}
+			for _, r := range resp.GetRoutes() {
+				routes = append(routes, route{
+					Hostname: r.GetHostname(),
+					Server:   r.GetServer(),
+					Listen:   r.GetListen(),
+					TLS:      r.GetTls(),
+					Upstream: r.GetUpstream(),
+					Healthy:  r.GetHealthy(),
+					Optional: caddy.Optional(r.GetServer()),
+					Error:    r.GetError(),
+				})
+			}
+
+			// show the routes as json for scripts
+			if j, ok := terminal.JSONOutput(output); ok {
+				return j.Result(routes)
+			}
+
+			if len(routes) == 0 {
+				output.Info("The proxy does not have any routes, run `nitro apply` to add the sites")
+
+				return nil
+			}
+
+			tbl := table.New("Hostname", "Listen", "TLS", "Upstream", "Status").WithWriter(cmd.OutOrStdout()).WithPadding(2)
+
+			unhealthy := 0
+			for _, r := range routes {
+				tls := "no"
+				if r.TLS {
+					tls = "yes"
+				}
+
+				// node dev servers are only up while they are started in the site container
+				state := "up"
+				switch {
+				case !r.Healthy && r.Optional:
+					state = "not running"
+				case !r.Healthy:
+					state = "down: " + r.Error
+					unhealthy++
+				}
+
+				tbl.AddRow(r.Hostname, strings.Join(r.Listen, ","), tls, r.Upstream, state)
+			}
+
+			tbl.Print()
+
+			if unhealthy > 0 {
+				output.Info("")
+				output.Info("Requests to upstreams that are down return a 502 from the proxy, check the containers with `nitro ls`")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/hooks"
 	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
@@ -53,6 +54,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			// get a context aware list of sites
 			sites := cfg.ListOfSitesByDirectory(home, wd)
 
+			// the site flag is used instead of the prompt
+			sites, err = prompt.FlagSites(cmd, cfg, sites)
+			if err != nil {
+				return err
+			}
+
 			// create the options for the sites
 			var options []string
 			for _, s := range sites {
@@ -69,7 +76,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			case true:
 				switch len(sites) {
 				case 0:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -78,7 +85,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				case 1:
 					site = &sites[0]
 				default:
-					selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+					selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 					if err != nil {
 						return err
 					}
@@ -94,6 +101,13 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			output.Info("Removing", site.Hostname)
 
+			// the container is removed on the next apply, so run the hooks while it exists
+			if skip, _ := cmd.Flags().GetBool("skip-hooks"); !skip {
+				if err := hooks.Site(cmd.Context(), docker, home, *site, config.HookPreDestroy, output); err != nil {
+					return err
+				}
+			}
+
 			// remove the site
 			if err := cfg.RemoveSite(site); err != nil {
 				return err
@@ -108,5 +122,9 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().Bool("skip-hooks", false, "skip running the pre_destroy hooks for the site")
+
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
@@ -81,7 +81,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// restart each container for the environment
 			for _, c := range containers {
-				n := strings.TrimLeft(c.Names[0], "/")
+				n := containerlabels.Hostname(c.Names[0])
 
 				output.Pending("restarting", n)
 
@@ -16,7 +16,7 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
-	"github.com/craftcms/nitro/pkg/proxycontainer"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -74,7 +74,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 			switch ProxyContainer {
 			case true:
 				// file by the container name
-				filter.Add("name", proxycontainer.ProxyName)
+				filter.Add("name", containerlabels.ProxyName())
 
 				// find the containers but limited to the site label
 				containers, err := docker.ContainerList(cmd.Context(), types.ContainerListOptions{Filters: filter, All: true})
@@ -102,6 +102,12 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				// get a context aware list of sites
 				sites := cfg.ListOfSitesByDirectory(home, wd)
 
+				// the site flag is used instead of the prompt
+				sites, err = prompt.FlagSites(cmd, cfg, sites)
+				if err != nil {
+					return err
+				}
+
 				// create the options for the sites
 				var options []string
 				for _, s := range sites {
@@ -123,7 +129,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					switch len(sites) {
 					case 0:
 						// prompt for the site to ssh into
-						selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+						selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 						if err != nil {
 							return err
 						}
@@ -137,7 +143,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 						filter.Add("label", containerlabels.Host+"="+sites[0].Hostname)
 					default:
 						// prompt for the site to ssh into
-						selected, err := output.Select(cmd.InOrStdin(), "Select a site: ", options)
+						selected, err := terminal.Flag(output, "site").Select(cmd.InOrStdin(), "Select a site: ", options)
 						if err != nil {
 							return err
 						}
@@ -209,5 +215,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 	cmd.Flags().BoolVar(&RootUser, "root", false, "connect as root user")
 	cmd.Flags().BoolVar(&ProxyContainer, "proxy", false, "connect to proxy container")
 
+	prompt.SiteFlag(cmd)
+
 	return cmd
 }
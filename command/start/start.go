@@ -2,7 +2,6 @@ package start
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -11,6 +10,8 @@ import (
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/hooks"
+	"github.com/craftcms/nitro/pkg/proxycontainer"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -73,8 +74,19 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				return ErrNoContainers
 			}
 
+			// the config is only needed for the hooks, so the containers are started without it
+			cfg, err := config.Load(home)
+			if err != nil {
+				cfg = &config.Config{}
+			}
+
 			output.Info("Starting Nitro…")
 
+			// every environment uses the same ports, so stop the other environments
+			if err := proxycontainer.StopOthers(ctx, docker, output); err != nil {
+				return err
+			}
+
 			// start each environment container
 			for _, c := range containers {
 				// don't start composer or npm containers
@@ -85,7 +97,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				// identify the type of container
 				containerType := containerlabels.Identify(c)
 
-				hostname := strings.TrimLeft(c.Names[0], "/")
+				hostname := containerlabels.Hostname(c.Names[0])
 
 				// if the user wants a single site only, skip all of the other sites
 				if site != "" && hostname != site && containerType == "site" {
@@ -106,6 +118,17 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 
 				output.Done()
+
+				if skip, _ := cmd.Flags().GetBool("skip-hooks"); skip || containerType != "site" {
+					continue
+				}
+
+				// run the post_start hooks for the site
+				if s, err := cfg.FindSiteByHostName(hostname); err == nil {
+					if err := hooks.Site(ctx, docker, home, *s, config.HookPostStart, output); err != nil {
+						return err
+					}
+				}
 			}
 
 			output.Info("Nitro started 👍")
@@ -114,5 +137,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 		},
 	}
 
+	cmd.Flags().Bool("skip-hooks", false, "skip running the post_start hooks for sites")
+
 	return cmd
 }
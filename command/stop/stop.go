@@ -2,7 +2,6 @@ package stop
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -69,7 +68,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 			// stop each environment container
 			for _, c := range containers {
-				hostname := strings.TrimLeft(c.Names[0], "/")
+				hostname := containerlabels.Hostname(c.Names[0])
 
 				// if the user wants a single site only, skip all of the other sites
 				if site != "" && hostname != site {
@@ -130,7 +130,7 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					continue
 				}
 
-				output.Pending(strings.TrimLeft(container.Names[0], "/"), "is out of date, replacing...")
+				output.Pending(containerlabels.Hostname(container.Names[0]), "is out of date, replacing...")
 
 				// if we are dubugging, don't actually remove or apply changes
 				if !debug {
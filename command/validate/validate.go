@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/phpextensions"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/craftcms/nitro/pkg/validate"
 )
@@ -67,6 +68,28 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 					if err := phpvalidator.Validate(s.Version); err != nil {
 						siteErrs = append(siteErrs, fmt.Errorf("invalid php version %s", s.Version))
 					}
+
+					// validate the php extensions are available for the php version
+					if err := phpextensions.Validate(s.Version, s.Extensions); err != nil {
+						siteErrs = append(siteErrs, fmt.Errorf("invalid extensions for %s, %w", s.Hostname, err))
+					}
+
+					// validate the php.ini settings
+					for directive, value := range s.INI {
+						if err := validate.PHPINISetting(directive, value); err != nil {
+							siteErrs = append(siteErrs, fmt.Errorf("invalid php.ini setting for %s, %w", s.Hostname, err))
+						}
+					}
+
+					// validate the hooks
+					if err := s.Hooks.Validate(); err != nil {
+						siteErrs = append(siteErrs, fmt.Errorf("invalid hooks for %s, %w", s.Hostname, err))
+					}
+				}
+
+				// validate the apply hooks, which can run in the site containers
+				if err := cfg.Hooks.Validate(sites); err != nil {
+					siteErrs = append(siteErrs, fmt.Errorf("invalid hooks, %w", err))
 				}
 
 				if len(siteErrs) > 0 {
@@ -76,6 +99,15 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 				}
 			}
 
+			// show the errors as json for scripts
+			if j, ok := terminal.JSONOutput(output); ok {
+				return j.Result(result{
+					Valid:     len(siteErrs) == 0 && len(dbErrs) == 0,
+					Sites:     messages(siteErrs),
+					Databases: messages(dbErrs),
+				})
+			}
+
 			// show any errors
 			if len(siteErrs) > 0 {
 				output.Info("Site Errors:")
@@ -90,3 +122,20 @@ func NewCommand(home string, docker client.CommonAPIClient, output terminal.Outp
 
 	return cmd
 }
+
+// result is the json output for validating the config.
+type result struct {
+	Valid     bool     `json:"valid"`
+	Sites     []string `json:"sites"`
+	Databases []string `json:"databases"`
+}
+
+// messages returns the message for each error.
+func messages(errs []error) []string {
+	list := []string{}
+	for _, e := range errs {
+		list = append(list, e.Error())
+	}
+
+	return list
+}
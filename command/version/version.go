@@ -17,7 +17,21 @@ import (
 // container to use to verify the gRPC API is in sync.
 var Version = "develop"
 
-var exampleText = `nitro version`
+var exampleText = `  # show the versions
+  nitro version
+
+  # show the versions as json
+  nitro version --output json`
+
+// versions is the json output for the version command.
+type versions struct {
+	CLI           string `json:"cli"`
+	GRPC          string `json:"grpc"`
+	DockerAPI     string `json:"docker_api"`
+	DockerMinAPI  string `json:"docker_min_api"`
+	DockerCLI     string `json:"docker_cli"`
+	VersionsMatch bool   `json:"versions_match"`
+}
 
 // NewCommand is used to show the cli and gRPC API client version
 func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroClient, output terminal.Outputer) *cobra.Command {
@@ -40,7 +54,7 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 			// make sure the version is not empty
 			if vers == "" {
 				// look up the version from the container label
-				details, err := client.ContainerInspect(cmd.Context(), "nitro-proxy")
+				details, err := client.ContainerInspect(cmd.Context(), containerlabels.ProxyName())
 				if err != nil {
 					return err
 				}
@@ -53,6 +67,18 @@ func NewCommand(home string, client client.CommonAPIClient, nitrod protob.NitroC
 				return fmt.Errorf("unable to get docker server version, %w", err)
 			}
 
+			// show the versions as json for scripts
+			if j, ok := terminal.JSONOutput(output); ok {
+				return j.Result(versions{
+					CLI:           Version,
+					GRPC:          vers,
+					DockerAPI:     ver.APIVersion,
+					DockerMinAPI:  ver.MinAPIVersion,
+					DockerCLI:     client.ClientVersion(),
+					VersionsMatch: Version == vers,
+				})
+			}
+
 			output.Info(fmt.Sprintf("View the changelog at https://github.com/craftcms/nitro/blob/%s/CHANGELOG.md\n", Version))
 
 			output.Info("Nitro CLI: \t", Version)
@@ -2,19 +2,21 @@ package api
 
 import (
 	"archive/zip"
-	"bytes"
+	"bufio"
 	"compress/gzip"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/craftcms/nitro/pkg/caddy"
 	"github.com/craftcms/nitro/pkg/database"
@@ -231,37 +233,72 @@ func (svc *Service) Apply(ctx context.Context, request *protob.ApplyRequest) (*p
 		Routes: siteRoutes,
 	}
 
-	content, err := json.Marshal(&update)
-	if err != nil {
-		return nil, err
-	}
-
-	// send the update
-	res, err := svc.HTTP.Post(svc.Addr+"/config/apps/http/servers", "application/json", bytes.NewReader(content))
+	// only send the routes that changed, the previous config is restored if a change fails
+	changes, err := caddy.NewClient(svc.Addr, svc.HTTP).Update(ctx, update.Servers())
 	if err != nil {
 		return &protob.ApplyResponse{
 			Message: fmt.Sprintf("Error updating Caddy API, err: %s", err.Error()),
 			Error:   true,
-		}, err
-	}
-
-	// check the status code
-	if res.StatusCode != http.StatusOK {
-		return &protob.ApplyResponse{
-			Message: fmt.Sprintf("Received %d response from Caddy API", res.StatusCode),
-			Error:   true,
 		}, nil
 	}
 
 	// set the message and error to false
 	return &protob.ApplyResponse{
-		Message: fmt.Sprintf("Successfully applied changes, sites: %d", len(request.GetSites())),
+		Message: fmt.Sprintf("Successfully applied changes, sites: %d, changes: %d", len(request.GetSites()), len(changes)),
 		Error:   false,
 	}, nil
 }
 
+// ExportDatabase runs the dump tool for a database and streams the dump, compressed
+// with gzip, to the client in chunks.
+func (svc *Service) ExportDatabase(req *protob.ExportDatabaseRequest, stream protob.Nitro_ExportDatabaseServer) error {
+	opts := &database.ExportOptions{
+		Engine:       req.GetDatabase().GetEngine(),
+		Version:      req.GetDatabase().GetVersion(),
+		Hostname:     req.GetDatabase().GetHostname(),
+		Port:         req.GetDatabase().GetPort(),
+		DatabaseName: req.GetDatabase().GetDatabase(),
+	}
+
+	// verify we can connect to the database hostname - no error means its reachable
+	if err := portavail.Check(opts.Hostname, opts.Port); err == nil {
+		return status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", opts.Hostname, opts.Port, err)
+	}
+
+	// buffer the compressed dump so each message is a reasonable size
+	w := bufio.NewWriterSize(&exportWriter{stream: stream}, exportChunkSize)
+
+	// the dump tool is stopped if the client goes away
+	if err := database.Export(stream.Context(), opts, w, database.DefaultExportToolFinder); err != nil {
+		return status.Errorf(codes.Internal, "error exporting the database %v", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return status.Errorf(codes.Internal, "unable to send the export %v", err)
+	}
+
+	return nil
+}
+
+// exportChunkSize is the size of each chunk of a database export sent to the client.
+const exportChunkSize = 32 * 1024
+
+// exportWriter sends each write to the client as a chunk of the export.
+type exportWriter struct {
+	stream protob.Nitro_ExportDatabaseServer
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&protob.ExportDatabaseResponse{Data: p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
 // ImportDatabase is used to handle streaming requests from the client and import a
-// database from a backup into the remote database container.
+// database from a backup into the remote database container. The progress of the
+// import and the output from the import tool are streamed back to the client.
 func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) error {
 	// verify the importer is declared
 	if svc.Importer == nil {
@@ -284,6 +321,8 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 	// set the temporary file
 	opts.File = tempFile.Name()
 
+	progress := &importProgress{stream: stream}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return status.Errorf(codes.Internal, "unable to receive from stream: %s", err.Error())
@@ -325,6 +364,7 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 	}
 
 	// handle the streaming request
+	var received int64
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -335,18 +375,27 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 		}
 
 		// write the streamed content into the temp file
-		_, err = tempFile.Write(req.GetData())
+		n, err := tempFile.Write(req.GetData())
 		if err != nil && !errors.Is(err, io.EOF) {
 			return status.Errorf(codes.Internal, "unable to write content to the temp file")
 		}
+
+		received += int64(n)
+
+		progress.send(&protob.ImportDatabaseResponse{Stage: "receiving", Bytes: received})
 	}
 
+	// make sure the client has the total received
+	progress.flush()
+
 	// verify we can connect to the database hostname - no error means its reachable
 	if err := portavail.Check(opts.Hostname, opts.Port); err == nil {
 		return status.Errorf(codes.Internal, "it does not appear the database is available on host %s using port %s: %v", opts.Hostname, opts.Port, err)
 	}
 
 	if opts.Compressed {
+		progress.send(&protob.ImportDatabaseResponse{Stage: "decompressing"})
+
 		// create the temp file to store the data
 		temp, err := ioutil.TempFile(os.TempDir(), "nitro-db-compressed")
 		if err != nil {
@@ -407,19 +456,80 @@ func (svc *Service) ImportDatabase(stream protob.Nitro_ImportDatabaseServer) err
 		}
 	}
 
+	// send the progress of the import and each line from the import tool
+	opts.Progress = func(p database.Progress) {
+		progress.send(&protob.ImportDatabaseResponse{
+			Stage:      "importing",
+			Bytes:      p.Bytes,
+			Total:      p.Total,
+			Statements: p.Statements,
+			Stderr:     p.Stderr,
+		})
+	}
+
 	// import the database
-	if err := database.NewImporter().Import(&opts, database.DefaultImportToolFinder); err != nil {
+	if err := svc.Importer.Import(&opts, database.DefaultImportToolFinder); err != nil {
+		progress.flush()
+
 		return status.Errorf(codes.Internal, "error importing the database %v", err)
 	}
 
-	// send and close the stream
-	return stream.SendAndClose(
+	progress.flush()
+
+	// send the final message
+	return stream.Send(
 		&protob.ImportDatabaseResponse{
+			Stage:   "done",
 			Message: fmt.Sprintf("Imported database %q", opts.DatabaseName),
 		},
 	)
 }
 
+// importProgressInterval is how often the progress of an import is sent to the client.
+var importProgressInterval = 250 * time.Millisecond
+
+// importProgress sends the progress of an import to the client. To avoid sending a
+// message for every chunk, progress is only sent once per interval and the latest
+// progress is held until then. Lines from the import tool and changes to the stage
+// are always sent.
+type importProgress struct {
+	mu      sync.Mutex
+	stream  protob.Nitro_ImportDatabaseServer
+	last    time.Time
+	stage   string
+	pending *protob.ImportDatabaseResponse
+}
+
+func (p *importProgress) send(resp *protob.ImportDatabaseResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if resp.Stderr == "" && resp.Stage == p.stage && time.Since(p.last) < importProgressInterval {
+		p.pending = resp
+		return
+	}
+
+	p.write(resp)
+}
+
+// flush sends the progress that is being held.
+func (p *importProgress) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending != nil {
+		p.write(p.pending)
+	}
+}
+
+// write sends the progress, the caller must hold the lock. Errors are ignored as
+// the import continues when the client is no longer listening.
+func (p *importProgress) write(resp *protob.ImportDatabaseResponse) {
+	p.stream.Send(resp)
+
+	p.stage, p.last, p.pending = resp.Stage, time.Now(), nil
+}
+
 // Ping returns a simple response "pong" from the gRPC API to verify connectivity.
 func (svc *Service) Ping(ctx context.Context, request *protob.PingRequest) (*protob.PingResponse, error) {
 	return &protob.PingResponse{Pong: "pong"}, nil
@@ -466,6 +576,81 @@ func (svc *Service) RemoveDatabase(ctx context.Context, req *protob.RemoveDataba
 	}, nil
 }
 
+// probeTimeout is how long to wait for an upstream to accept a connection.
+var probeTimeout = 2 * time.Second
+
+// Status reads the servers from the Caddy API and returns a route for each hostname with the
+// upstream, listener, and TLS status. Each upstream is probed to check if it accepts connections,
+// which is the most common cause of a 502 from the proxy.
+func (svc *Service) Status(ctx context.Context, req *protob.StatusRequest) (*protob.StatusResponse, error) {
+	cfg, err := caddy.NewClient(svc.Addr, svc.HTTP).Servers(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+
+	routes := caddy.Routes(cfg.Servers)
+
+	// probe each upstream once, the hostname and aliases share an upstream
+	probes := make(map[string]error)
+	var upstreams []string
+	for _, r := range routes {
+		if _, ok := probes[r.Upstream]; !ok {
+			probes[r.Upstream] = nil
+			upstreams = append(upstreams, r.Upstream)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, upstream := range upstreams {
+		wg.Add(1)
+		go func(upstream string) {
+			defer wg.Done()
+
+			err := probe(ctx, upstream)
+
+			mu.Lock()
+			probes[upstream] = err
+			mu.Unlock()
+		}(upstream)
+	}
+
+	wg.Wait()
+
+	resp := &protob.StatusResponse{}
+	for _, r := range routes {
+		route := &protob.ProxyRoute{
+			Hostname: r.Hostname,
+			Server:   r.Server,
+			Listen:   r.Listen,
+			Tls:      r.TLS,
+			Upstream: r.Upstream,
+			Healthy:  true,
+		}
+
+		if err := probes[r.Upstream]; err != nil {
+			route.Healthy = false
+			route.Error = err.Error()
+		}
+
+		resp.Routes = append(resp.Routes, route)
+	}
+
+	return resp, nil
+}
+
+// probe returns an error if the upstream does not accept connections.
+func probe(ctx context.Context, upstream string) error {
+	d := net.Dialer{Timeout: probeTimeout}
+
+	conn, err := d.DialContext(ctx, "tcp", upstream)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
 // Version is used to check the container image version with the CLI version
 func (svc *Service) Version(ctx context.Context, request *protob.VersionRequest) (*protob.VersionResponse, error) {
 	return &protob.VersionResponse{Version: Version}, nil
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -92,3 +95,100 @@ func TestService_Version(t *testing.T) {
 		})
 	}
 }
+
+type spyImportStream struct {
+	protob.Nitro_ImportDatabaseServer
+	sent []*protob.ImportDatabaseResponse
+}
+
+func (s *spyImportStream) Send(resp *protob.ImportDatabaseResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestImportProgress_Send(t *testing.T) {
+	stream := &spyImportStream{}
+	p := &importProgress{stream: stream}
+
+	// only the first progress for the stage is sent until the interval passes
+	p.send(&protob.ImportDatabaseResponse{Stage: "receiving", Bytes: 1})
+	p.send(&protob.ImportDatabaseResponse{Stage: "receiving", Bytes: 2})
+	p.send(&protob.ImportDatabaseResponse{Stage: "receiving", Bytes: 3})
+	p.flush()
+
+	// stage changes and lines from the import tool are always sent
+	p.send(&protob.ImportDatabaseResponse{Stage: "importing", Bytes: 1})
+	p.send(&protob.ImportDatabaseResponse{Stage: "importing", Bytes: 1, Stderr: "ERROR 1064"})
+	p.flush()
+
+	var got []string
+	for _, resp := range stream.sent {
+		got = append(got, resp.Stage+" "+resp.Stderr)
+	}
+
+	want := []string{"receiving ", "receiving ", "importing ", "importing ERROR 1064"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the progress %v, got %v", want, got)
+	}
+
+	if b := stream.sent[1].Bytes; b != 3 {
+		t.Errorf("expected flush to send the latest progress, got %d bytes", b)
+	}
+}
+
+func TestService_Status(t *testing.T) {
+	// an upstream that accepts connections and one that does not
+	up, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer up.Close()
+
+	down, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down.Close()
+
+	servers := fmt.Sprintf(`{
+		"https": {
+			"listen": [":443"],
+			"routes": [
+				{"handle": [{"handler": "reverse_proxy", "upstreams": [{"dial": %q}]}], "match": [{"host": ["up.nitro"]}]},
+				{"handle": [{"handler": "reverse_proxy", "upstreams": [{"dial": %q}]}], "match": [{"host": ["down.nitro"]}]},
+				{"handle": [{"handler": "file_server", "root": "/var/www/html"}]}
+			]
+		}
+	}`, up.Addr().String(), down.Addr().String())
+
+	caddy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/config/apps/http/servers" {
+			http.NotFound(w, r)
+			return
+		}
+
+		fmt.Fprint(w, servers)
+	}))
+	defer caddy.Close()
+
+	svc := &Service{Addr: caddy.URL, HTTP: caddy.Client()}
+
+	resp, err := svc.Status(context.Background(), &protob.StatusRequest{})
+	if err != nil {
+		t.Fatalf("Service.Status() error = %v", err)
+	}
+
+	var got []string
+	for _, r := range resp.GetRoutes() {
+		got = append(got, fmt.Sprintf("%s %s %v %v %v", r.GetHostname(), r.GetUpstream(), r.GetTls(), r.GetHealthy(), r.GetError() != ""))
+	}
+
+	want := []string{
+		fmt.Sprintf("down.nitro %s true false true", down.Addr()),
+		fmt.Sprintf("up.nitro %s true true false", up.Addr()),
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.Status() = %v, want %v", got, want)
+	}
+}
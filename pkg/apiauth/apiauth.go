@@ -0,0 +1,161 @@
+package apiauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+const (
+	// EnvToken is the environment variable for the token nitrod requires on each request
+	EnvToken = "NITROD_TOKEN"
+
+	// EnvCert is the environment variable for the PEM encoded TLS certificate nitrod uses
+	EnvCert = "NITROD_TLS_CERT"
+
+	// EnvKey is the environment variable for the PEM encoded TLS key nitrod uses
+	EnvKey = "NITROD_TLS_KEY"
+
+	// DirectoryName is the directory in the nitro directory the credentials are stored in
+	DirectoryName = "nitrod"
+
+	tokenFile = "token"
+	certFile  = "cert.pem"
+	keyFile   = "key.pem"
+)
+
+// Credentials are the token and TLS certificate used to secure the nitrod API. They
+// are created once for each install and shared by the CLI and the proxy container.
+type Credentials struct {
+	Token string
+	Cert  []byte
+	Key   []byte
+}
+
+// Env returns the environment variables used to pass the credentials to nitrod.
+func (c *Credentials) Env() []string {
+	return []string{
+		EnvToken + "=" + c.Token,
+		EnvCert + "=" + string(c.Cert),
+		EnvKey + "=" + string(c.Key),
+	}
+}
+
+// Dir returns the directory the credentials are stored in (e.g. ~/.nitro/nitrod).
+func Dir(home string) string {
+	return filepath.Join(home, config.DirectoryName, DirectoryName)
+}
+
+// Load reads the credentials from the users nitro directory.
+func Load(home string) (*Credentials, error) {
+	dir := Dir(home)
+
+	token, err := ioutil.ReadFile(filepath.Join(dir, tokenFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the nitrod token, run `nitro init` to create it, %w", err)
+	}
+
+	cert, err := ioutil.ReadFile(filepath.Join(dir, certFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the nitrod certificate, run `nitro init` to create it, %w", err)
+	}
+
+	key, err := ioutil.ReadFile(filepath.Join(dir, keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the nitrod key, run `nitro init` to create it, %w", err)
+	}
+
+	return &Credentials{Token: strings.TrimSpace(string(token)), Cert: cert, Key: key}, nil
+}
+
+// Ensure loads the credentials, or creates them if they do not exist. The files are
+// only readable by the user.
+func Ensure(home string) (*Credentials, error) {
+	if c, err := Load(home); err == nil {
+		return c, nil
+	}
+
+	c, err := Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := Dir(home)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create the directory for the nitrod credentials, %w", err)
+	}
+
+	files := map[string][]byte{
+		tokenFile: []byte(c.Token + "\n"),
+		certFile:  c.Cert,
+		keyFile:   c.Key,
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return nil, fmt.Errorf("unable to save the nitrod credentials, %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Generate creates a random token and a self-signed certificate for 127.0.0.1 and localhost.
+func Generate() (*Credentials, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("unable to generate the nitrod token, %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the nitrod key, %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the certificate serial number, %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Craft Nitro"}, CommonName: "nitrod"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the nitrod certificate, %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the nitrod key, %w", err)
+	}
+
+	return &Credentials{
+		Token: hex.EncodeToString(b),
+		Cert:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:   pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,155 @@
+package apiauth
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/craftcms/nitro/protob"
+)
+
+type pingServer struct {
+	protob.UnimplementedNitroServer
+}
+
+func (s *pingServer) Ping(ctx context.Context, request *protob.PingRequest) (*protob.PingResponse, error) {
+	return &protob.PingResponse{Pong: "pong"}, nil
+}
+
+func TestEnsure(t *testing.T) {
+	home := t.TempDir()
+
+	created, err := Ensure(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{tokenFile, certFile, keyFile} {
+		info, err := os.Stat(filepath.Join(Dir(home), f))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected %s to only be readable by the user, got %v", f, info.Mode().Perm())
+		}
+	}
+
+	// the existing credentials are used
+	loaded, err := Ensure(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Token != created.Token || string(loaded.Cert) != string(created.Cert) {
+		t.Errorf("expected the existing credentials to be used")
+	}
+}
+
+func TestServerOptions(t *testing.T) {
+	home := t.TempDir()
+
+	creds, err := Ensure(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := ServerOptions(creds.Token, creds.Cert, creds.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := grpc.NewServer(opts...)
+	protob.RegisterNitroServer(s, &pingServer{})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	// a home with the same certificate and a different token
+	wrongToken := t.TempDir()
+	if err := os.MkdirAll(Dir(wrongToken), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{tokenFile: []byte("invalid"), certFile: creds.Cert, keyFile: creds.Key}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(Dir(wrongToken), name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a home with a different certificate
+	wrongCert := t.TempDir()
+	if _, err := Ensure(wrongCert); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		home     string
+		ping     codes.Code
+		database codes.Code
+	}{
+		{
+			name:     "requests with the token are allowed",
+			home:     home,
+			ping:     codes.OK,
+			database: codes.Unimplemented,
+		},
+		{
+			name:     "requests with an invalid token are rejected",
+			home:     wrongToken,
+			ping:     codes.OK,
+			database: codes.Unauthenticated,
+		},
+		{
+			name:     "servers with another certificate are not trusted",
+			home:     wrongCert,
+			ping:     codes.Unavailable,
+			database: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, err := grpc.Dial(lis.Addr().String(), DialOptions(tt.home)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cc.Close()
+
+			client := protob.NewNitroClient(cc)
+
+			_, err = client.Ping(context.Background(), &protob.PingRequest{})
+			if got := status.Code(err); got != tt.ping {
+				t.Errorf("expected Ping to return %v, got %v", tt.ping, err)
+			}
+
+			_, err = client.RemoveDatabase(context.Background(), &protob.RemoveDatabaseRequest{})
+			if got := status.Code(err); got != tt.database {
+				t.Errorf("expected RemoveDatabase to return %v, got %v", tt.database, err)
+			}
+		})
+	}
+}
+
+func TestServerOptions_RequiresToken(t *testing.T) {
+	creds, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ServerOptions("", creds.Cert, creds.Key); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
@@ -0,0 +1,66 @@
+package apiauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// DialOptions returns the gRPC dial options that present the token and only trust the
+// certificate created for nitrod. The credentials are read when they are first needed,
+// so the client can be created before `nitro init` creates them.
+func DialOptions(home string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			// the certificate is self-signed, so it is compared to the saved certificate instead
+			InsecureSkipVerify: true,
+			VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				return verify(home, rawCerts)
+			},
+		})),
+		grpc.WithPerRPCCredentials(tokenCredentials{home: home}),
+	}
+}
+
+// verify checks that the certificate presented by nitrod is the saved certificate.
+func verify(home string, rawCerts [][]byte) error {
+	c, err := Load(home)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(c.Cert)
+	if block == nil {
+		return fmt.Errorf("unable to decode the nitrod certificate")
+	}
+
+	if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], block.Bytes) {
+		return fmt.Errorf("the nitrod certificate does not match, run `nitro update` to recreate the proxy")
+	}
+
+	return nil
+}
+
+// tokenCredentials sends the saved token with each request.
+type tokenCredentials struct {
+	home string
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c, err := Load(t.home)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{metadataKey: "Bearer " + c.Token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,83 @@
+package apiauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataKey is the request metadata the token is sent in, as a bearer token.
+const metadataKey = "authorization"
+
+// unauthenticated are the methods that do not require the token, the CLI waits for the
+// API to be ready using ping and it does not return any details.
+var unauthenticated = map[string]bool{
+	"/nitrod.Nitro/Ping": true,
+}
+
+// ServerOptions returns the gRPC server options that require TLS and the token for each request.
+func ServerOptions(token string, cert, key []byte) ([]grpc.ServerOption, error) {
+	if token == "" {
+		return nil, fmt.Errorf("the token is required, set %s", EnvToken)
+	}
+
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the TLS certificate, set %s and %s, %w", EnvCert, EnvKey, err)
+	}
+
+	return []grpc.ServerOption{
+		grpc.Creds(credentials.NewServerTLSFromCert(&pair)),
+		grpc.UnaryInterceptor(UnaryServerInterceptor(token)),
+		grpc.StreamInterceptor(StreamServerInterceptor(token)),
+	}, nil
+}
+
+// UnaryServerInterceptor rejects requests that do not have the token.
+func UnaryServerInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, token, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams that do not have the token.
+func StreamServerInterceptor(token string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), token, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, token, method string) error {
+	if unauthenticated[method] {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing the nitrod token")
+	}
+
+	for _, v := range md.Get(metadataKey) {
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(v, "Bearer ")), []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid nitrod token")
+}
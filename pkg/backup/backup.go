@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -61,10 +60,11 @@ func (o *Options) Validate() error {
 }
 
 // Prompt is used to ask a user for input and walk them through selecting a database engine (container) and a database. It will return the container ID
-// as the first string, the database name, and the last return is an error.
-func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPIClient, output terminal.Outputer, containers []types.Container, containerList []string) (string, string, string, string, error) {
+// as the first string, the database name, and the last return is an error. The engine (e.g. mysql-8.0-3306.database.nitro) and database are used
+// instead of prompting when they are not empty.
+func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPIClient, output terminal.Outputer, containers []types.Container, containerList []string, engine, database string) (string, string, string, string, error) {
 	// prompt the user for which database to backup
-	selected, err := output.Select(reader, "Which database engine? ", containerList)
+	selected, err := choose(reader, terminal.Flag(output, "engine"), "Which database engine? ", containerList, engine)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -82,15 +82,15 @@ func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPICli
 
 	// prompt the user for the specific database to backup
 	var db string
-	switch len(databases) {
-	case 1:
+	switch {
+	case len(databases) == 0:
+		return "", "", "", "", fmt.Errorf("no databases found")
+	case len(databases) == 1 && database == "":
 		output.Info("There is only one database to backup…")
 
 		db = databases[0]
-	case 0:
-		return "", "", "", "", fmt.Errorf("no databases found")
 	default:
-		selected, err := output.Select(os.Stdin, "Which database should we backup? ", databases)
+		selected, err := choose(reader, terminal.Flag(output, "name"), "Which database should we backup? ", databases, database)
 		if err != nil {
 			return "", "", "", "", err
 		}
@@ -101,6 +101,21 @@ func Prompt(ctx context.Context, reader io.Reader, docker client.ContainerAPICli
 	return id, name, compatibility, db, nil
 }
 
+// choose returns the position of the value in the options, or prompts for the option when the value is empty.
+func choose(reader io.Reader, output terminal.Outputer, message string, opts []string, value string) (int, error) {
+	if value == "" {
+		return output.Select(reader, message, opts)
+	}
+
+	for i, o := range opts {
+		if o == value {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to find %q, use one of %s", value, strings.Join(opts, ", "))
+}
+
 // Databases is used to get a list of all the databases for a specific engine. It is returned as a slice of strings using the
 // containers hostname (e.g. mysql-8.0-3306) so it can be presented to the user as a list.
 func Databases(ctx context.Context, docker client.ContainerAPIClient, containerID, compatibility string) ([]string, error) {
@@ -1,5 +1,7 @@
 package caddy
 
+import "encoding/json"
+
 type UpdateRequest struct {
 	HTTPS   Server `json:"https,omitempty"`
 	HTTP    Server `json:"http,omitempty"`
@@ -7,10 +9,27 @@ type UpdateRequest struct {
 	NodeAlt Server `json:"node_alt,omitempty"`
 }
 
+// Servers returns the servers keyed by the names used in the Caddy config.
+func (u UpdateRequest) Servers() map[string]Server {
+	return map[string]Server{
+		"https":    u.HTTPS,
+		"http":     u.HTTP,
+		"node":     u.Node,
+		"node_alt": u.NodeAlt,
+	}
+}
+
+// Optional returns true for the servers that proxy to the node dev servers (e.g. npm run dev)
+// in the site containers, the upstreams are only up while a dev server is running.
+func Optional(server string) bool {
+	return server == "node" || server == "node_alt"
+}
+
 type Server struct {
-	Listen         []string       `json:"listen"`
-	Routes         []ServerRoute  `json:"routes"`
-	AutomaticHTTPS AutomaticHTTPS `json:"automatic_https"`
+	Listen                []string          `json:"listen"`
+	Routes                []ServerRoute     `json:"routes"`
+	AutomaticHTTPS        AutomaticHTTPS    `json:"automatic_https"`
+	TLSConnectionPolicies []json.RawMessage `json:"tls_connection_policies,omitempty"`
 }
 
 type AutomaticHTTPS struct {
@@ -29,6 +48,9 @@ type RouteHandle struct {
 	Root      string     `json:"root,omitempty"`
 	Upstreams []Upstream `json:"upstreams,omitempty"`
 	Hide      []string   `json:"hide,omitempty"`
+
+	// Routes are the routes for a subroute handler
+	Routes []ServerRoute `json:"routes,omitempty"`
 }
 
 type Match struct {
@@ -0,0 +1,282 @@
+package caddy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// DefaultAddr is the address of the Caddy admin API in the proxy container.
+const DefaultAddr = "http://127.0.0.1:2019"
+
+// serversPath is the path to the HTTP servers in the Caddy config.
+const serversPath = "/config/apps/http/servers"
+
+// updateAttempts is how many times an update is tried when the config is changed
+// by another request.
+const updateAttempts = 3
+
+// ErrConflict is returned when the config was changed after it was read.
+var ErrConflict = errors.New("the caddy config was changed by another request")
+
+// Client is a typed client for the Caddy admin API.
+type Client struct {
+	Addr string
+	HTTP *http.Client
+}
+
+// NewClient returns a client for the Caddy admin API at the addr, the default
+// addr and http client are used when they are not provided.
+func NewClient(addr string, c *http.Client) *Client {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	return &Client{Addr: addr, HTTP: c}
+}
+
+// Config is the HTTP servers loaded in Caddy. The ETag is used to make sure the
+// config has not changed before loading a new config.
+type Config struct {
+	Servers map[string]Server
+	ETag    string
+
+	// raw is the config as returned by Caddy, it is used to restore the config
+	raw []byte
+}
+
+// Servers returns the HTTP servers loaded in Caddy, the servers are nil when
+// the config does not have any.
+func (c *Client) Servers(ctx context.Context) (*Config, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Addr+serversPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the request, %w", err)
+	}
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the config from the Caddy API, %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := responseError(res); err != nil {
+		return nil, err
+	}
+
+	raw, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config from the Caddy API, %w", err)
+	}
+
+	cfg := &Config{ETag: res.Header.Get("Etag"), raw: raw}
+
+	// caddy returns null when there are no servers
+	if err := json.Unmarshal(raw, &cfg.Servers); err != nil {
+		return nil, fmt.Errorf("unable to parse the config from the Caddy API, %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Update applies the route-level changes between the current config and the
+// servers, and returns the changes. Each change is sent to the path of the
+// route, or server, it changes with the ETag of the config it was made from,
+// so routes are never changed by their position in a config that was changed
+// by another request. The config is read again after each change, and the
+// change is tried again when the config was changed in the meantime. If a
+// change fails, the config from before the first change is restored.
+func (c *Client) Update(ctx context.Context, servers map[string]Server) ([]Change, error) {
+	// the changes and config from before the first change was applied
+	var changes []Change
+	var previous *Config
+
+	last := -1
+	for conflicts := 0; conflicts < updateAttempts; {
+		current, err := c.Servers(ctx)
+		if err != nil {
+			return changes, err
+		}
+
+		pending := Diff(current.Servers, servers)
+		if len(pending) == 0 {
+			return changes, nil
+		}
+
+		// changes that were not applied were replaced by another request
+		if last >= 0 && len(pending) >= last {
+			conflicts++
+		}
+
+		err = c.apply(ctx, current, servers, pending[0])
+		if errors.Is(err, ErrConflict) {
+			conflicts++
+			last = -1
+			continue
+		}
+		if err != nil {
+			if previous == nil {
+				previous = current
+			}
+
+			if rerr := c.restore(ctx, previous); rerr != nil {
+				return changes, fmt.Errorf("unable to %s, %v, and unable to restore the previous config, %w", pending[0], err, rerr)
+			}
+
+			return changes, fmt.Errorf("unable to %s, the previous config was restored, %w", pending[0], err)
+		}
+
+		if previous == nil {
+			previous, changes = current, pending
+		}
+
+		last = len(pending)
+	}
+
+	return changes, ErrConflict
+}
+
+// apply sends a single change to the path it changes, using the ETag of the
+// config the change was made from. Servers that are added or removed, and
+// servers with different settings, are changed as a whole. Routes are added,
+// updated, or removed by their position in the servers routes, and the
+// server is changed as a whole when more than one route matches the same
+// hostnames.
+func (c *Client) apply(ctx context.Context, current *Config, servers map[string]Server, change Change) error {
+	// caddy can only change the paths that exist
+	if current.Servers == nil {
+		return c.send(ctx, http.MethodPost, serversPath, servers, current.ETag)
+	}
+
+	server := serversPath + "/" + change.Server
+
+	have, ok := current.Servers[change.Server]
+	want, wanted := servers[change.Server]
+
+	switch {
+	case !ok:
+		return c.send(ctx, http.MethodPut, server, want, current.ETag)
+	case !wanted:
+		return c.send(ctx, http.MethodDelete, server, nil, current.ETag)
+	case !equal(settings(have), settings(want)):
+		return c.send(ctx, http.MethodPatch, server, want, current.ETag)
+	}
+
+	haveIndexes := indexes(have.Routes, change.Hostname)
+	wantIndexes := indexes(want.Routes, change.Hostname)
+
+	switch {
+	case len(haveIndexes) == 0 && len(wantIndexes) == 1 && len(have.Routes) > 0:
+		route := want.Routes[wantIndexes[0]]
+
+		// add the route before the next route that is already loaded, so routes
+		// without hostnames (e.g. the welcome page) stay last
+		for _, r := range want.Routes[wantIndexes[0]+1:] {
+			if next := indexes(have.Routes, strings.Join(hosts(r), ",")); len(next) > 0 {
+				return c.send(ctx, http.MethodPut, fmt.Sprintf("%s/routes/%d", server, next[0]), route, current.ETag)
+			}
+		}
+
+		return c.send(ctx, http.MethodPost, server+"/routes", route, current.ETag)
+	case len(haveIndexes) == 1 && len(wantIndexes) == 1:
+		return c.send(ctx, http.MethodPatch, fmt.Sprintf("%s/routes/%d", server, haveIndexes[0]), want.Routes[wantIndexes[0]], current.ETag)
+	case len(haveIndexes) == 1 && len(wantIndexes) == 0:
+		return c.send(ctx, http.MethodDelete, fmt.Sprintf("%s/routes/%d", server, haveIndexes[0]), nil, current.ETag)
+	}
+
+	return c.send(ctx, http.MethodPatch, server, want, current.ETag)
+}
+
+// indexes returns the positions of the routes that match the hostnames (e.g. site.nitro,site.test).
+func indexes(routes []ServerRoute, hostnames string) []int {
+	var list []int
+	for i, r := range routes {
+		if strings.Join(hosts(r), ",") == hostnames {
+			list = append(list, i)
+		}
+	}
+
+	return list
+}
+
+// send makes a request to change the config at the path, the value is sent as
+// JSON when it is not nil.
+func (c *Client) send(ctx context.Context, method, path string, value interface{}, etag string) error {
+	var body io.Reader
+	if value != nil {
+		content, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to create the config, %w", err)
+		}
+
+		body = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.Addr+path, body)
+	if err != nil {
+		return fmt.Errorf("unable to create the request, %w", err)
+	}
+
+	if value != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send the config to the Caddy API, %w", err)
+	}
+	defer res.Body.Close()
+
+	return responseError(res)
+}
+
+// restore loads the previous config if the current config is different.
+func (c *Client) restore(ctx context.Context, previous *Config) error {
+	current, err := c.Servers(ctx)
+	if err == nil && bytes.Equal(bytes.TrimSpace(current.raw), bytes.TrimSpace(previous.raw)) {
+		return nil
+	}
+
+	// there were no servers to restore
+	if previous.Servers == nil {
+		return c.send(ctx, http.MethodDelete, serversPath, nil, "")
+	}
+
+	return c.send(ctx, http.MethodPost, serversPath, json.RawMessage(previous.raw), "")
+}
+
+// responseError returns the error from the Caddy API for responses that are not successful.
+func responseError(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	if res.StatusCode == http.StatusPreconditionFailed {
+		return ErrConflict
+	}
+
+	// caddy returns the error as json (e.g. {"error": "loading config: ..."})
+	var body struct {
+		Error string `json:"error"`
+	}
+
+	content, _ := ioutil.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if err := json.Unmarshal(content, &body); err == nil && body.Error != "" {
+		return fmt.Errorf("received %d response from the Caddy API, %s", res.StatusCode, body.Error)
+	}
+
+	return fmt.Errorf("received %d response from the Caddy API", res.StatusCode)
+}
@@ -0,0 +1,317 @@
+package caddy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// admin is a fake Caddy admin API for the servers, it checks If-Match and
+// changes the config at the request path like Caddy.
+type admin struct {
+	mu     sync.Mutex
+	config string
+	loads  int
+
+	// requests are the method and path of the requests that change the config
+	requests []string
+
+	// beforeLoad is called before a change is checked (e.g. to change the config)
+	beforeLoad func(a *admin)
+
+	// fail returns an error for changes, after making the change to simulate a partial load
+	fail bool
+}
+
+func (a *admin) etag() string {
+	return fmt.Sprintf(`"%s %x"`, serversPath, sha256.Sum256([]byte(a.config)))
+}
+
+func (a *admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		w.Header().Set("Etag", a.etag())
+		fmt.Fprint(w, a.config)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	if a.beforeLoad != nil {
+		a.beforeLoad(a)
+		a.beforeLoad = nil
+	}
+
+	if m := r.Header.Get("If-Match"); m != "" && m != a.etag() {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := a.change(r.Method, strings.TrimPrefix(r.URL.Path, serversPath), body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"error": %q}`, err.Error())
+		return
+	}
+
+	a.loads++
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+
+	if a.fail {
+		a.fail = false
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": "loading config: listening on :443: address already in use"}`)
+	}
+}
+
+// change sets the value at the path in the servers, arrays are changed by the index.
+func (a *admin) change(method, path string, body []byte) error {
+	if path == "" {
+		switch method {
+		case http.MethodDelete:
+			a.config = "null"
+		default:
+			a.config = string(body)
+		}
+
+		return nil
+	}
+
+	var config, value interface{}
+	if err := json.Unmarshal([]byte(a.config), &config); err != nil {
+		return err
+	}
+
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &value); err != nil {
+			return err
+		}
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	// the parent of the last part of the path
+	parent := config
+	var set func(v interface{})
+	for _, part := range parts[:len(parts)-1] {
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			key := part
+			set = func(n interface{}) { v[key] = n }
+			parent = v[part]
+		case []interface{}:
+			i, _ := strconv.Atoi(part)
+			set = func(n interface{}) { v[i] = n }
+			parent = v[i]
+		default:
+			return fmt.Errorf("invalid traversal path at: %s", path)
+		}
+	}
+
+	last := parts[len(parts)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		switch method {
+		case http.MethodDelete:
+			delete(v, last)
+		case http.MethodPost:
+			if arr, ok := v[last].([]interface{}); ok {
+				v[last] = append(arr, value)
+			} else {
+				v[last] = value
+			}
+		default:
+			v[last] = value
+		}
+	case []interface{}:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(v) {
+			return fmt.Errorf("invalid index: %s", last)
+		}
+
+		switch method {
+		case http.MethodDelete:
+			v = append(v[:i:i], v[i+1:]...)
+		case http.MethodPut:
+			v = append(v[:i:i], append([]interface{}{value}, v[i:]...)...)
+		case http.MethodPatch:
+			v[i] = value
+		default:
+			return fmt.Errorf("unsupported method %s for %s", method, path)
+		}
+
+		set(v)
+	default:
+		return fmt.Errorf("invalid traversal path at: %s", path)
+	}
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	a.config = string(content)
+
+	return nil
+}
+
+func TestClient_Update(t *testing.T) {
+	previous := `{"https":{"listen":[":443"],"routes":[{"handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"one.nitro:8080"}]}],"match":[{"host":["one.nitro"]}],"terminal":true}],"automatic_https":{"disable_redirects":false}}}`
+
+	unchanged := map[string]Server{
+		"https": {Listen: []string{":443"}, Routes: []ServerRoute{proxyRoute("one.nitro:8080", "one.nitro")}},
+	}
+
+	changed := map[string]Server{
+		"https": {Listen: []string{":443"}, Routes: []ServerRoute{proxyRoute("one.nitro:8080", "one.nitro"), proxyRoute("two.nitro:8080", "two.nitro")}},
+	}
+
+	tests := []struct {
+		name        string
+		admin       *admin
+		servers     map[string]Server
+		wantChanges []Change
+		wantLoads   int
+		wantErr     string
+		wantPaths   []string
+		wantConfig  string
+	}{
+		{
+			name:       "unchanged routes are not loaded",
+			admin:      &admin{config: previous},
+			servers:    unchanged,
+			wantLoads:  0,
+			wantConfig: previous,
+		},
+		{
+			name:        "changed routes are loaded",
+			admin:       &admin{config: previous},
+			servers:     changed,
+			wantChanges: []Change{{Server: "https", Hostname: "two.nitro", Action: ChangeAdd}},
+			wantLoads:   1,
+			wantPaths:   []string{"POST " + serversPath + "/https/routes"},
+		},
+		{
+			name: "changes by another request are read again",
+			admin: &admin{config: previous, beforeLoad: func(a *admin) {
+				a.config = strings.Replace(previous, "one.nitro:8080", "one.nitro:9000", 1)
+			}},
+			servers: changed,
+			wantChanges: []Change{
+				{Server: "https", Hostname: "one.nitro", Action: ChangeUpdate},
+				{Server: "https", Hostname: "two.nitro", Action: ChangeAdd},
+			},
+			wantLoads: 2,
+			wantPaths: []string{
+				"PATCH " + serversPath + "/https/routes/0",
+				"POST " + serversPath + "/https/routes",
+			},
+		},
+		{
+			name:  "routes are changed at their position",
+			admin: &admin{config: `{"http":{"listen":[":80"],"routes":[{"handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"one.nitro:8080"}]}],"match":[{"host":["one.nitro"]}],"terminal":true},{"handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"three.nitro:8080"}]}],"match":[{"host":["three.nitro"]}],"terminal":true},{"handle":[{"handler":"file_server","root":"/var/www/html"}],"terminal":true}],"automatic_https":{"disable_redirects":true}}}`},
+			servers: map[string]Server{
+				"http": {
+					Listen:         []string{":80"},
+					Routes:         []ServerRoute{proxyRoute("two.nitro:8080", "two.nitro"), proxyRoute("three.nitro:9000", "three.nitro"), {Handle: []RouteHandle{{Handler: "file_server", Root: "/var/www/html"}}, Terminal: true}},
+					AutomaticHTTPS: AutomaticHTTPS{DisableRedirects: true},
+				},
+				"node": {Listen: []string{":3000"}},
+			},
+			wantChanges: []Change{
+				{Server: "http", Hostname: "one.nitro", Action: ChangeRemove},
+				{Server: "http", Hostname: "three.nitro", Action: ChangeUpdate},
+				{Server: "http", Hostname: "two.nitro", Action: ChangeAdd},
+				{Server: "node", Action: ChangeAdd},
+			},
+			wantLoads: 4,
+			wantPaths: []string{
+				"DELETE " + serversPath + "/http/routes/0",
+				"PATCH " + serversPath + "/http/routes/0",
+				"PUT " + serversPath + "/http/routes/0",
+				"PUT " + serversPath + "/node",
+			},
+		},
+		{
+			name:       "failed loads restore the previous config",
+			admin:      &admin{config: previous, fail: true},
+			servers:    changed,
+			wantLoads:  2,
+			wantErr:    "unable to add two.nitro on https, the previous config was restored, received 400 response from the Caddy API, loading config: listening on :443: address already in use",
+			wantConfig: previous,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.admin)
+			defer srv.Close()
+
+			changes, err := NewClient(srv.URL, srv.Client()).Update(context.Background(), tt.servers)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Update() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Update() unexpected error = %v", err)
+				}
+
+				if fmt.Sprint(changes) != fmt.Sprint(tt.wantChanges) {
+					t.Errorf("Update() = %v, want %v", changes, tt.wantChanges)
+				}
+			}
+
+			if tt.wantPaths != nil && fmt.Sprint(tt.admin.requests) != fmt.Sprint(tt.wantPaths) {
+				t.Errorf("expected the requests %v, got %v", tt.wantPaths, tt.admin.requests)
+			}
+
+			if tt.admin.loads != tt.wantLoads {
+				t.Errorf("expected %d loads, got %d", tt.wantLoads, tt.admin.loads)
+			}
+
+			if tt.wantConfig != "" && tt.admin.config != tt.wantConfig {
+				t.Errorf("expected the config %s, got %s", tt.wantConfig, tt.admin.config)
+			}
+
+			// the config matches the servers after the changes
+			if tt.wantErr == "" {
+				var servers map[string]Server
+				if err := json.Unmarshal([]byte(tt.admin.config), &servers); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := Diff(servers, tt.servers); len(diff) > 0 {
+					t.Errorf("expected the config to match the servers, got the changes %v", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Update_Conflict(t *testing.T) {
+	// the config changes before every load
+	a := &admin{config: "null"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			a.beforeLoad = func(a *admin) { a.config += " " }
+		}
+
+		a.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, srv.Client()).Update(context.Background(), map[string]Server{"https": {Listen: []string{":443"}}})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Update() error = %v, want %v", err, ErrConflict)
+	}
+}
@@ -0,0 +1,129 @@
+package caddy
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	// ChangeAdd is a route for a hostname that is not in the current config
+	ChangeAdd = "add"
+
+	// ChangeUpdate is a route for a hostname that is different from the current config
+	ChangeUpdate = "update"
+
+	// ChangeRemove is a route for a hostname that is only in the current config
+	ChangeRemove = "remove"
+)
+
+// Change is a route that is added, updated, or removed for the hostnames on
+// a server. Changes to the server itself (e.g. the listeners) use an empty
+// hostname, as do routes that do not match a hostname (e.g. the welcome page).
+type Change struct {
+	Server   string
+	Hostname string
+	Action   string
+}
+
+func (c Change) String() string {
+	if c.Hostname == "" {
+		return c.Action + " " + c.Server
+	}
+
+	return c.Action + " " + c.Hostname + " on " + c.Server
+}
+
+// Diff returns the route-level changes between the current and desired
+// servers. Routes are keyed by the hostnames they match, so the routes for
+// other sites are not changes when a single site changes. The changes are
+// sorted by server, hostname, and action.
+func Diff(current, desired map[string]Server) []Change {
+	var changes []Change
+
+	for name, want := range desired {
+		have, ok := current[name]
+		if !ok {
+			changes = append(changes, Change{Server: name, Action: ChangeAdd})
+			continue
+		}
+
+		// check the server without the routes
+		if !equal(settings(have), settings(want)) {
+			changes = append(changes, Change{Server: name, Action: ChangeUpdate})
+		}
+
+		haveRoutes, wantRoutes := routesByHost(have), routesByHost(want)
+
+		for host, routes := range wantRoutes {
+			existing, ok := haveRoutes[host]
+			switch {
+			case !ok:
+				changes = append(changes, Change{Server: name, Hostname: host, Action: ChangeAdd})
+			case !equal(existing, routes):
+				changes = append(changes, Change{Server: name, Hostname: host, Action: ChangeUpdate})
+			}
+		}
+
+		for host := range haveRoutes {
+			if _, ok := wantRoutes[host]; !ok {
+				changes = append(changes, Change{Server: name, Hostname: host, Action: ChangeRemove})
+			}
+		}
+	}
+
+	for name := range current {
+		if _, ok := desired[name]; !ok {
+			changes = append(changes, Change{Server: name, Action: ChangeRemove})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Server != b.Server {
+			return a.Server < b.Server
+		}
+		if a.Hostname != b.Hostname {
+			return a.Hostname < b.Hostname
+		}
+
+		return a.Action < b.Action
+	})
+
+	return changes
+}
+
+// routesByHost returns the routes for the server keyed by the hostnames they match (e.g. site.nitro,site.test).
+func routesByHost(s Server) map[string][]ServerRoute {
+	routes := make(map[string][]ServerRoute)
+	for _, r := range s.Routes {
+		key := strings.Join(hosts(r), ",")
+		routes[key] = append(routes[key], r)
+	}
+
+	return routes
+}
+
+// settings returns the server without the routes.
+func settings(s Server) Server {
+	s.Routes = nil
+
+	return s
+}
+
+// equal compares the JSON for the values, so values loaded from the Caddy
+// API are equal to the values created by the API.
+func equal(a, b interface{}) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(x, y)
+}
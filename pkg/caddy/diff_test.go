@@ -0,0 +1,80 @@
+package caddy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func proxyRoute(upstream string, hosts ...string) ServerRoute {
+	return ServerRoute{
+		Handle:   []RouteHandle{{Handler: "reverse_proxy", Upstreams: []Upstream{{Dial: upstream}}}},
+		Match:    []Match{{Host: hosts}},
+		Terminal: true,
+	}
+}
+
+func TestDiff(t *testing.T) {
+	welcome := ServerRoute{Handle: []RouteHandle{{Handler: "file_server", Root: "/var/www/html"}}, Terminal: true}
+
+	current := map[string]Server{
+		"https": {
+			Listen: []string{":443"},
+			Routes: []ServerRoute{proxyRoute("one.nitro:8080", "one.nitro"), proxyRoute("two.nitro:8080", "two.nitro"), welcome},
+		},
+		"srv0": {Listen: []string{":80"}},
+	}
+
+	tests := []struct {
+		name    string
+		desired map[string]Server
+		want    []Change
+	}{
+		{
+			name: "reordered routes are not changes",
+			desired: map[string]Server{
+				"https": {
+					Listen: []string{":443"},
+					Routes: []ServerRoute{proxyRoute("two.nitro:8080", "two.nitro"), proxyRoute("one.nitro:8080", "one.nitro"), welcome},
+				},
+				"srv0": {Listen: []string{":80"}},
+			},
+		},
+		{
+			name: "changes are keyed by the hostnames",
+			desired: map[string]Server{
+				"https": {
+					Listen: []string{":443"},
+					Routes: []ServerRoute{proxyRoute("one.nitro:9000", "one.nitro"), proxyRoute("three.nitro:8080", "three.nitro", "three.test"), welcome},
+				},
+				"srv0": {Listen: []string{":80"}},
+			},
+			want: []Change{
+				{Server: "https", Hostname: "one.nitro", Action: ChangeUpdate},
+				{Server: "https", Hostname: "three.nitro,three.test", Action: ChangeAdd},
+				{Server: "https", Hostname: "two.nitro", Action: ChangeRemove},
+			},
+		},
+		{
+			name: "servers are added, updated, and removed",
+			desired: map[string]Server{
+				"https": {
+					Listen: []string{":8443"},
+					Routes: []ServerRoute{proxyRoute("one.nitro:8080", "one.nitro"), proxyRoute("two.nitro:8080", "two.nitro"), welcome},
+				},
+				"node": {Listen: []string{":3000"}},
+			},
+			want: []Change{
+				{Server: "https", Action: ChangeUpdate},
+				{Server: "node", Action: ChangeAdd},
+				{Server: "srv0", Action: ChangeRemove},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(current, tt.desired); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package caddy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Route is a hostname served by a Caddy server and the upstream the requests
+// for the hostname are proxied to.
+type Route struct {
+	Hostname string
+	Server   string
+	Listen   []string
+	TLS      bool
+	Upstream string
+}
+
+// Routes returns a route for each hostname and upstream in the servers,
+// including the routes in subroutes. Routes without a hostname or that are
+// not proxied (e.g. the welcome page) are not returned. The routes are
+// sorted by the hostname and server name.
+func Routes(servers map[string]Server) []Route {
+	var routes []Route
+	for name, srv := range servers {
+		for _, r := range srv.Routes {
+			for _, host := range hosts(r) {
+				for _, upstream := range upstreams(r.Handle) {
+					routes = append(routes, Route{
+						Hostname: host,
+						Server:   name,
+						Listen:   srv.Listen,
+						TLS:      srv.TLS(),
+						Upstream: upstream,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Hostname != routes[j].Hostname {
+			return routes[i].Hostname < routes[j].Hostname
+		}
+
+		return routes[i].Server < routes[j].Server
+	})
+
+	return routes
+}
+
+// TLS returns true if the server uses https, Caddy enables automatic https
+// unless it is disabled or the server only listens on the http port.
+func (s Server) TLS() bool {
+	if len(s.TLSConnectionPolicies) > 0 {
+		return true
+	}
+
+	if s.AutomaticHTTPS.Disable {
+		return false
+	}
+
+	for _, l := range s.Listen {
+		if !strings.HasSuffix(l, ":80") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hosts returns the hostnames matched by the route.
+func hosts(r ServerRoute) []string {
+	var hosts []string
+	for _, m := range r.Match {
+		hosts = append(hosts, m.Host...)
+	}
+
+	return hosts
+}
+
+// upstreams returns the upstreams for the reverse proxy handlers, including the handlers in subroutes.
+func upstreams(handlers []RouteHandle) []string {
+	var dials []string
+	for _, h := range handlers {
+		switch h.Handler {
+		case "reverse_proxy":
+			for _, u := range h.Upstreams {
+				if u.Dial != "" {
+					dials = append(dials, u.Dial)
+				}
+			}
+		case "subroute":
+			for _, r := range h.Routes {
+				dials = append(dials, upstreams(r.Handle)...)
+			}
+		}
+	}
+
+	return dials
+}
@@ -0,0 +1,55 @@
+package caddy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var https Server
+	if err := json.Unmarshal(content, &https); err != nil {
+		t.Fatal(err)
+	}
+
+	node := Server{
+		Listen: []string{":3000"},
+		Routes: []ServerRoute{
+			{
+				Handle: []RouteHandle{{Handler: "reverse_proxy", Upstreams: []Upstream{{Dial: "siteone.nitro:3000"}}}},
+				Match:  []Match{{Host: []string{"siteone.nitro"}}},
+			},
+		},
+		AutomaticHTTPS: AutomaticHTTPS{Disable: true},
+	}
+
+	http := Server{
+		Listen: []string{":80"},
+		Routes: []ServerRoute{
+			{
+				Handle: []RouteHandle{{Handler: "reverse_proxy", Upstreams: []Upstream{{Dial: "sitetwo.nitro:8080"}}}},
+				Match:  []Match{{Host: []string{"sitetwo.nitro"}}},
+			},
+		},
+	}
+
+	got := Routes(map[string]Server{"https": https, "node": node, "http": http})
+
+	want := []Route{
+		{Hostname: "siteone.localhost", Server: "https", Listen: []string{":443"}, TLS: true, Upstream: "siteone.nitro:8080"},
+		{Hostname: "siteone.nitro", Server: "https", Listen: []string{":443"}, TLS: true, Upstream: "siteone.nitro:8080"},
+		{Hostname: "siteone.nitro", Server: "node", Listen: []string{":3000"}, TLS: false, Upstream: "siteone.nitro:3000"},
+		{Hostname: "sitetwo.nitro", Server: "http", Listen: []string{":80"}, TLS: false, Upstream: "sitetwo.nitro:8080"},
+		{Hostname: "sitetwo.nitro", Server: "https", Listen: []string{":443"}, TLS: true, Upstream: "sitetwo.nitro:8080"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v, want %+v", got, want)
+	}
+}
@@ -1,14 +1,18 @@
 package config
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/craftcms/nitro/pkg/datetime"
 	"github.com/craftcms/nitro/pkg/helpers"
+	"github.com/craftcms/nitro/pkg/validate"
 
 	"gopkg.in/yaml.v3"
 )
@@ -48,13 +52,27 @@ var (
 
 // Config represents the nitro-dev.yaml users add for local development.
 type Config struct {
+	Version    int         `json:"version,omitempty" yaml:"version,omitempty"`
 	Containers []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Blackfire  Blackfire   `json:"blackfire,omitempty" yaml:"blackfire,omitempty"`
 	Databases  []Database  `json:"databases,omitempty" yaml:"databases,omitempty"`
+	Hooks      ApplyHooks  `json:"hooks,omitempty" yaml:"hooks,omitempty"`
 	Services   Services    `json:"services" yaml:"services"`
 	Sites      []Site      `json:"sites,omitempty" yaml:"sites,omitempty"`
 	File       string      `json:"-" yaml:"-"`
 
+	// Backup is the file the original config was copied to before it was migrated
+	Backup string `json:"-" yaml:"-"`
+
+	// Migrations is the list of changes made when upgrading the config file
+	Migrations []string `json:"-" yaml:"-"`
+
+	// checksum is the checksum of the file when it was loaded
+	checksum string
+
+	// projects are the project configs merged since the config was loaded
+	projects []*Project
+
 	// rw sync.RWMutex
 }
 
@@ -240,16 +258,57 @@ type Services struct {
 // Site represents a web application. It has a hostname, aliases (which
 // are alternate domains), the local path to the site, additional mounts
 // to add to the container, and the directory the index.php is located.
+// INI is a map of php.ini directives that are written to the sites
+// container and take precedence over the PHP settings. Env and EnvFile
+// add custom environment variables to the sites container. Image or
+// Build replace the default image for the site, custom images should
+// be based on the craftcms/nginx images.
 type Site struct {
-	Hostname   string   `json:"hostname" yaml:"hostname"`
-	Aliases    []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
-	Path       string   `json:"path" yaml:"path"`
-	Version    string   `json:"version" yaml:"version"`
-	PHP        PHP      `json:"php,omitempty" yaml:"php,omitempty"`
-	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
-	Webroot    string   `json:"webroot" yaml:"webroot"`
-	Xdebug     bool     `json:"xdebug" yaml:"xdebug"`
-	Blackfire  bool     `json:"blackfire" yaml:"blackfire"`
+	Hostname   string            `json:"hostname" yaml:"hostname"`
+	Aliases    []string          `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Path       string            `json:"path" yaml:"path"`
+	Version    string            `json:"version" yaml:"version"`
+	PHP        PHP               `json:"php,omitempty" yaml:"php,omitempty"`
+	INI        map[string]string `json:"ini,omitempty" yaml:"ini,omitempty"`
+	Env        map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	EnvFile    string            `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+	Mounts     []Mount           `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	Image      string            `json:"image,omitempty" yaml:"image,omitempty"`
+	Build      *Build            `json:"build,omitempty" yaml:"build,omitempty"`
+	Extensions []string          `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Webroot    string            `json:"webroot" yaml:"webroot"`
+	Xdebug     bool              `json:"xdebug" yaml:"xdebug"`
+	Blackfire  bool              `json:"blackfire" yaml:"blackfire"`
+	Hooks      Hooks             `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+}
+
+// Build is used to build a custom image for a site from a Dockerfile. The
+// context is relative to the sites path and the Dockerfile is relative to
+// the context.
+type Build struct {
+	Context    string            `json:"context,omitempty" yaml:"context,omitempty"`
+	Dockerfile string            `json:"dockerfile,omitempty" yaml:"dockerfile,omitempty"`
+	Args       map[string]string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// GetAbsContext takes the users home directory and the site and returns the
+// directory to use as the build context.
+func (b *Build) GetAbsContext(home string, site Site) (string, error) {
+	context := b.Context
+	if !filepath.IsAbs(context) && !strings.HasPrefix(context, "~") {
+		context = filepath.Join(site.Path, context)
+	}
+
+	return cleanPath(home, context)
+}
+
+// GetDockerfile returns the path of the Dockerfile relative to the context.
+func (b *Build) GetDockerfile() string {
+	if b.Dockerfile == "" {
+		return "Dockerfile"
+	}
+
+	return b.Dockerfile
 }
 
 // GetAbsPath gets the directory for a site.Path,
@@ -298,28 +357,46 @@ func (s *Site) AsEnvs(addr string) []string {
 	return append(envs, xdebugVars(s.PHP, s.Xdebug, s.Version, s.Hostname, addr)...)
 }
 
-// SetPHPBoolSetting is used to set php settings that are bool. It will look
-// for the site by its hostname and change the setting. If it cannot find the
-// site or setting it will return an error.
-func (c *Config) SetPHPBoolSetting(hostname, setting string, value bool) error {
-	for i, s := range c.Sites {
-		if s.Hostname == hostname {
-			switch setting {
-			case "display_errors":
-				c.Sites[i].PHP.DisplayErrors = value
+// PHPINI returns the sites php.ini directives as the contents of an ini
+// file, the directives are sorted so the file is the same every time.
+func (s *Site) PHPINI() string {
+	if len(s.INI) == 0 {
+		return ""
+	}
 
-				return nil
-			case "opcache_enable":
-				c.Sites[i].PHP.OpcacheEnable = value
+	var directives []string
+	for k := range s.INI {
+		directives = append(directives, k)
+	}
 
-				return nil
-			case "opcache_validate_timestamps":
-				c.Sites[i].PHP.OpcacheValidateTimestamps = value
+	sort.Strings(directives)
 
-				return nil
-			default:
-				return fmt.Errorf("unknown php setting %s", setting)
+	b := &strings.Builder{}
+	b.WriteString("; generated by nitro for " + s.Hostname + "\n")
+	for _, d := range directives {
+		fmt.Fprintf(b, "%s = %s\n", d, iniValue(s.INI[d]))
+	}
+
+	return b.String()
+}
+
+// SetPHPINISetting is used to set any php.ini directive for a site. It will
+// validate the value and return an error if it is invalid or the site
+// cannot be found.
+func (c *Config) SetPHPINISetting(hostname, directive, value string) error {
+	if err := validate.PHPINISetting(directive, value); err != nil {
+		return err
+	}
+
+	for i, s := range c.Sites {
+		if s.Hostname == hostname {
+			if c.Sites[i].INI == nil {
+				c.Sites[i].INI = make(map[string]string)
 			}
+
+			c.Sites[i].INI[directive] = value
+
+			return nil
 		}
 	}
 
@@ -389,70 +466,6 @@ func (c *Config) SetPHPExtension(hostname, extension string) error {
 	return fmt.Errorf("unable to find the site: %s", hostname)
 }
 
-// SetPHPIntSetting is used to set php settings that are ints. It will look
-// for the site by its hostname and change the setting. If it cannot find the
-// site or setting it will return an error.
-func (c *Config) SetPHPIntSetting(hostname, setting string, value int) error {
-	for i, s := range c.Sites {
-		if s.Hostname == hostname {
-			switch setting {
-			case "max_execution_time":
-				c.Sites[i].PHP.MaxExecutionTime = value
-
-				return nil
-			case "max_input_vars":
-				c.Sites[i].PHP.MaxInputVars = value
-
-				return nil
-			case "max_input_time":
-				c.Sites[i].PHP.MaxInputTime = value
-
-				return nil
-			case "opcache_revalidate_freq":
-				c.Sites[i].PHP.OpcacheRevalidateFreq = value
-
-				return nil
-			default:
-				return fmt.Errorf("unknown php setting %s", setting)
-			}
-		}
-	}
-
-	return fmt.Errorf("unable to find the site: %s", hostname)
-}
-
-// SetPHPStrSetting is used to set php settings that are strings. It will look
-// for the site by its hostname and change the setting. If it cannot find the
-// site or setting it will return an error.
-func (c *Config) SetPHPStrSetting(hostname, setting, value string) error {
-	for i, s := range c.Sites {
-		if s.Hostname == hostname {
-			switch setting {
-			case "post_max_size":
-				c.Sites[i].PHP.PostMaxSize = value
-
-				return nil
-			case "max_file_upload":
-				c.Sites[i].PHP.MaxFileUpload = value
-
-				return nil
-			case "memory_limit":
-				c.Sites[i].PHP.MemoryLimit = value
-
-				return nil
-			case "upload_max_file_size":
-				c.Sites[i].PHP.UploadMaxFileSize = value
-
-				return nil
-			default:
-				return fmt.Errorf("unknown php setting %s", setting)
-			}
-		}
-	}
-
-	return fmt.Errorf("unable to find the site: %s", hostname)
-}
-
 // PHP is nested in a configuration and allows setting environment variables
 // for sites to override in the local development environment.
 type PHP struct {
@@ -471,27 +484,63 @@ type PHP struct {
 
 // Load is used to return the unmarshalled config, and
 // returns an error when trying to get the users home directory or
-// while marshalling the config.
+// while marshalling the config. Configs from older versions of nitro
+// are backed up and upgraded to the current version. References to
+// environment variables (e.g. ${VAR} or ${VAR:-default}) and secrets
+// (e.g. ${secret:blackfire-token}) are expanded in all of the values.
 func Load(home string) (*Config, error) {
 	file, err := IsEmpty(home)
 	if err != nil {
 		return nil, err
 	}
 
+	// read the file
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
 	// create the config
 	c := &Config{
-		File: file,
+		File:     file,
+		checksum: checksum(data),
 	}
 
-	// read the file
-	data, err := ioutil.ReadFile(file)
+	// parse the file so older versions can be migrated
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	from, changes, err := migrate(&doc)
 	if err != nil {
 		return nil, err
 	}
 
 	// unmarshal
-	if err := yaml.Unmarshal(data, &c); err != nil {
-		return nil, err
+	if root := documentRoot(&doc); root != nil {
+		// expand the references to environment variables and secrets
+		if err := interpolateNode(root, expander(filepath.Dir(file))); err != nil {
+			return nil, fmt.Errorf("unable to load the config, %w", err)
+		}
+
+		if err := doc.Decode(c); err != nil {
+			return nil, err
+		}
+	}
+
+	// backup the original file and save the upgraded config
+	if len(changes) > 0 {
+		c.Backup = fmt.Sprintf("%s.v%d-%s.bak", file, from, datetime.Parse(time.Now()))
+		if err := ioutil.WriteFile(c.Backup, data, 0644); err != nil {
+			return nil, fmt.Errorf("unable to backup the config before migrating, %w", err)
+		}
+
+		c.Migrations = changes
+
+		if err := c.Save(); err != nil {
+			return nil, err
+		}
 	}
 
 	// return the config
@@ -647,56 +696,130 @@ func (c *Config) EnableXdebug(site string) error {
 	return fmt.Errorf("unknown site, %s", site)
 }
 
-// Save takes a file path and marshals the config into a file.
+// Save takes a file path and marshals the config into a file. The
+// existing file is updated in place so comments and the order of keys
+// are kept and only the settings that changed are rewritten. Writes are
+// locked and atomic, and if the file was changed since it was loaded
+// ErrConfigChanged is returned instead of overwriting those changes.
 func (c *Config) Save() error {
-	// make sure the file exists
-	if _, err := os.Stat(c.File); os.IsNotExist(err) {
-		dir, _ := filepath.Split(c.File)
+	dir := filepath.Dir(c.File)
 
-		if err := c.createFile(dir); err != nil {
-			return err
-		}
+	// make sure the directory exists
+	if err := helpers.MkdirIfNotExists(dir); err != nil {
+		return err
 	}
 
-	// open the file
-	f, err := os.OpenFile(c.File, os.O_TRUNC|os.O_WRONLY, os.ModeAppend)
+	// lock the config so other commands can't write at the same time
+	lock, err := lockFile(filepath.Join(dir, configLockFile), true)
 	if err != nil {
 		return err
 	}
+	defer lock.Unlock()
+
+	// read the existing file to keep comments and ordering
+	existing, err := ioutil.ReadFile(c.File)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// make sure the file has not changed since it was loaded
+	if c.checksum != "" && checksum(existing) != c.checksum {
+		return ErrConfigChanged
+	}
+
+	// always write the current version
+	c.Version = CurrentVersion
+
+	// write the references from merged projects first, so the expanded values are not saved
+	if len(c.projects) > 0 {
+		existing, err = encodeInto(existing, c.withProjectReferences(), expander(dir))
+		if err != nil {
+			return err
+		}
+	}
 
 	// unmarshal
-	data, err := yaml.Marshal(&c)
+	data, err := encodeInto(existing, c, expander(dir))
 	if err != nil {
 		return err
 	}
 
-	// write the content
-	if _, err := f.Write(data); err != nil {
+	if err := writeFile(c.File, data); err != nil {
 		return err
 	}
 
-	return f.Close()
+	c.checksum = checksum(data)
+
+	return nil
 }
 
-func (c *Config) createFile(dir string) error {
-	// create the .nitro directory if it does not exist
-	if err := helpers.MkdirIfNotExists(dir); err != nil {
-		return err
+// Redacted returns the config as yaml to show to users. Values that
+// reference an environment variable or secret are shown as the
+// reference and plain text blackfire credentials are masked.
+func (c *Config) Redacted() ([]byte, error) {
+	existing, err := ioutil.ReadFile(c.File)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	data, err := encodeInto(existing, c, expander(filepath.Dir(c.File)))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	// redact blackfire credentials
+	blackfire := mappingValue(documentRoot(&doc), "blackfire")
+	for _, k := range []string{"server_id", "server_token"} {
+		if v := mappingValue(blackfire, k); v != nil && v.Value != "" && !isReference(v.Value) {
+			v.Value = strings.Repeat("*", len(v.Value))
+		}
+	}
+
+	return encodeNode(&doc, detectIndent(data))
+}
+
+// writeFile writes the data to a temp file and renames it over the
+// file so other commands never read a partially written config.
+func writeFile(file string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(file); err == nil {
+		mode = info.Mode().Perm()
 	}
 
-	// otherwise create it
-	f, err := os.Create(c.File)
+	tmp, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer os.Remove(tmp.Name())
 
-	// try to chown otherwise be quiet
-	if err := f.Chown(os.Geteuid(), os.Getuid()); err != nil {
-		return nil
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
 	}
 
-	return nil
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func checksum(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
 }
 
 // GetFile returns the file location for the config
@@ -704,6 +827,17 @@ func (c *Config) GetFile() string {
 	return c.File
 }
 
+// iniValue quotes php.ini values that would otherwise be parsed as a comment
+// or lose spaces, other values are not quoted so constants and expressions
+// such as E_ALL & ~E_NOTICE still work.
+func iniValue(v string) string {
+	if v == "" || strings.ContainsAny(v, ";=") || strings.TrimSpace(v) != v {
+		return `"` + v + `"`
+	}
+
+	return v
+}
+
 func phpVars(php PHP, version string) []string {
 	// set the composer home so we can install plugins and
 	// updates from the control panel
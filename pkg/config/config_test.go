@@ -1,6 +1,7 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -283,7 +284,8 @@ func TestLoad(t *testing.T) {
 				home: testdir,
 			},
 			want: &Config{
-				File: filepath.Join(testdir, DirectoryName, FileName),
+				File:     filepath.Join(testdir, DirectoryName, FileName),
+				checksum: checksumFile(t, filepath.Join(testdir, DirectoryName, FileName)),
 				Blackfire: Blackfire{
 					ServerID:    "my-id",
 					ServerToken: "my-token",
@@ -346,6 +348,15 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func checksumFile(t *testing.T, file string) string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return checksum(data)
+}
+
 func TestConfig_EnableXdebug(t *testing.T) {
 	type fields struct {
 		Blackfire Blackfire
@@ -583,216 +594,56 @@ func TestSite_GetAbsPath(t *testing.T) {
 	}
 }
 
-func TestConfig_SetPHPStrSetting(t *testing.T) {
-	type fields struct {
-		Sites []Site
-	}
-	type args struct {
-		hostname string
-		setting  string
-		value    string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    Config
-		wantErr bool
-	}{
-		// upload_max_file_size
-		{
-			name: "can change a sites php upload_max_file_size setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "upload_max_file_size",
-				value:    "24M",
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php post max size setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "post_max_size",
-				value:    "1024M",
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php max file upload setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "max_file_upload",
-				value:    "1024M",
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php memory limit setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "memory_limit",
-				value:    "1024M",
-			},
-			wantErr: false,
-		},
-		{
-			name: "unknown settings return an error",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "new_setting_who_dis",
-				value:    "1024M",
-			},
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := &Config{
-				Sites: tt.fields.Sites,
-			}
-
-			if err := c.SetPHPStrSetting(tt.args.hostname, tt.args.setting, tt.args.value); (err != nil) != tt.wantErr {
-				t.Errorf("Config.SetPHPStrSetting() error = %v, wantErr %v", err, tt.wantErr)
-			}
-
-			// find the site
-			var site Site
-			for _, s := range c.Sites {
-				if s.Hostname == tt.args.hostname {
-					site = s
-				}
-			}
-
-			switch tt.args.setting {
-			case "memory_limit":
-				if site.PHP.MemoryLimit != tt.args.value {
-					t.Errorf("expected the setting to be %s, got %s", tt.args.value, site.PHP.MemoryLimit)
-				}
-			case "post_max_size":
-				if site.PHP.PostMaxSize != tt.args.value {
-					t.Errorf("expected the setting to be %s, got %s", tt.args.value, site.PHP.PostMaxSize)
-				}
-			case "max_file_upload":
-				if site.PHP.MaxFileUpload != tt.args.value {
-					t.Errorf("expected the setting to be %s, got %s", tt.args.value, site.PHP.MaxFileUpload)
-				}
-			}
-		})
-	}
-}
-
-func TestConfig_SetPHPBoolSetting(t *testing.T) {
-	type fields struct {
-		Sites []Site
-	}
+func TestConfig_SetPHPINISetting(t *testing.T) {
 	type args struct {
-		hostname string
-		setting  string
-		value    bool
+		hostname  string
+		directive string
+		value     string
 	}
 	tests := []struct {
 		name    string
-		fields  fields
+		sites   []Site
 		args    args
+		want    map[string]string
 		wantErr bool
 	}{
 		{
-			name: "can change a sites php opcache enable setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
+			name:  "can set any php.ini directive",
+			sites: []Site{{Hostname: "siteone.nitro"}},
 			args: args{
-				hostname: "siteone.nitro",
-				setting:  "opcache_enable",
-				value:    true,
+				hostname:  "siteone.nitro",
+				directive: "date.timezone",
+				value:     "America/Chicago",
 			},
-			wantErr: false,
+			want: map[string]string{"date.timezone": "America/Chicago"},
 		},
 		{
-			name: "can change a sites php post max size setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
+			name:  "existing directives are replaced",
+			sites: []Site{{Hostname: "siteone.nitro", INI: map[string]string{"session.gc_maxlifetime": "1440", "date.timezone": "UTC"}}},
 			args: args{
-				hostname: "siteone.nitro",
-				setting:  "display_errors",
-				value:    false,
+				hostname:  "siteone.nitro",
+				directive: "session.gc_maxlifetime",
+				value:     "86400",
 			},
-			wantErr: false,
+			want: map[string]string{"session.gc_maxlifetime": "86400", "date.timezone": "UTC"},
 		},
 		{
-			name: "unknown settings return an error",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
+			name:  "known directives are validated",
+			sites: []Site{{Hostname: "siteone.nitro"}},
 			args: args{
-				hostname: "siteone.nitro",
-				setting:  "new_setting_who_dis",
-				value:    false,
+				hostname:  "siteone.nitro",
+				directive: "memory_limit",
+				value:     "lots",
 			},
 			wantErr: true,
 		},
 		{
-			name: "missing site returns an error",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
+			name:  "unknown sites return an error",
+			sites: []Site{{Hostname: "siteone.nitro"}},
 			args: args{
-				hostname: "newsite.nitro",
-				setting:  "display_errors",
-				value:    false,
+				hostname:  "sitetwo.nitro",
+				directive: "date.timezone",
+				value:     "UTC",
 			},
 			wantErr: true,
 		},
@@ -800,186 +651,58 @@ func TestConfig_SetPHPBoolSetting(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Config{
-				Sites: tt.fields.Sites,
+				Sites: tt.sites,
 			}
 
-			if err := c.SetPHPBoolSetting(tt.args.hostname, tt.args.setting, tt.args.value); (err != nil) != tt.wantErr {
-				t.Errorf("Config.SetPHPBoolSetting() error = %v, wantErr %v", err, tt.wantErr)
+			if err := c.SetPHPINISetting(tt.args.hostname, tt.args.directive, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("Config.SetPHPINISetting() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
-			// find the site
-			var site Site
-			for _, s := range c.Sites {
-				if s.Hostname == tt.args.hostname {
-					site = s
-				}
+			if tt.wantErr {
+				return
 			}
 
-			switch tt.args.setting {
-			case "display_errors":
-				if site.PHP.DisplayErrors != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.DisplayErrors)
-				}
-			case "opcache_enable":
-				if site.PHP.OpcacheEnable != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.OpcacheEnable)
-				}
-			case "opcache_validate_timestamps":
-				if site.PHP.OpcacheValidateTimestamps != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.OpcacheValidateTimestamps)
-				}
+			if !reflect.DeepEqual(c.Sites[0].INI, tt.want) {
+				t.Errorf("expected the php.ini settings to be %v, got %v", tt.want, c.Sites[0].INI)
 			}
 		})
 	}
 }
 
-func TestConfig_SetPHPIntSetting(t *testing.T) {
-	type fields struct {
-		Sites []Site
-	}
-	type args struct {
-		hostname string
-		setting  string
-		value    int
-	}
+func TestSite_PHPINI(t *testing.T) {
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
+		name string
+		site Site
+		want string
 	}{
 		{
-			name: "can change a sites php max_execution_time setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "max_execution_time",
-				value:    7000,
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php max_input_vars setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "max_input_vars",
-				value:    13000,
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php max_input_time setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "max_input_time",
-				value:    13000,
-			},
-			wantErr: false,
-		},
-		{
-			name: "can change a sites php opcache_revalidate_freq setting",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "opcache_revalidate_freq",
-				value:    30,
-			},
-			wantErr: false,
-		},
-		{
-			name: "unknown settings return an error",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
-				},
-			},
-			args: args{
-				hostname: "siteone.nitro",
-				setting:  "new_setting_who_dis",
-				value:    0,
-			},
-			wantErr: true,
+			name: "sites without settings return an empty string",
+			site: Site{Hostname: "siteone.nitro"},
+			want: "",
 		},
 		{
-			name: "missing site returns an error",
-			fields: fields{
-				Sites: []Site{
-					{
-						Hostname: "siteone.nitro",
-					},
+			name: "settings are sorted and quoted when needed",
+			site: Site{
+				Hostname: "siteone.nitro",
+				INI: map[string]string{
+					"session.gc_maxlifetime": "86400",
+					"date.timezone":          "America/Chicago",
+					"error_reporting":        "E_ALL & ~E_NOTICE",
+					"sendmail_path":          "/usr/bin/env catchmail -f some@from.address; other",
 				},
 			},
-			args: args{
-				hostname: "newsite.nitro",
-				setting:  "display_errors",
-				value:    0,
-			},
-			wantErr: true,
+			want: `; generated by nitro for siteone.nitro
+date.timezone = America/Chicago
+error_reporting = E_ALL & ~E_NOTICE
+sendmail_path = "/usr/bin/env catchmail -f some@from.address; other"
+session.gc_maxlifetime = 86400
+`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &Config{
-				Sites: tt.fields.Sites,
-			}
-
-			if err := c.SetPHPIntSetting(tt.args.hostname, tt.args.setting, tt.args.value); (err != nil) != tt.wantErr {
-				t.Errorf("Config.SetPHPIntSetting() error = %v, wantErr %v", err, tt.wantErr)
-			}
-
-			// find the site
-			var site Site
-			for _, s := range c.Sites {
-				if s.Hostname == tt.args.hostname {
-					site = s
-				}
-			}
-
-			switch tt.args.setting {
-			case "max_execution_time":
-				if site.PHP.MaxExecutionTime != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.MaxExecutionTime)
-				}
-			case "max_input_vars":
-				if site.PHP.MaxInputVars != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.MaxInputVars)
-				}
-			case "max_input_time":
-				if site.PHP.MaxInputTime != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.MaxInputTime)
-				}
-			case "opcache_revalidate_freq":
-				if site.PHP.OpcacheRevalidateFreq != tt.args.value {
-					t.Errorf("expected the setting to be %v, got %v", tt.args.value, site.PHP.OpcacheRevalidateFreq)
-				}
+			if got := tt.site.PHPINI(); got != tt.want {
+				t.Errorf("Site.PHPINI() = \ngot:\n%s\nwant:\n%s", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultEnvironment is the name of the environment used when one is
+// not selected, its config file is the default nitro.yaml.
+const DefaultEnvironment = "nitro"
+
+var (
+	// Environment is the name of the selected environment. Each environment
+	// has its own config file in the nitro directory (e.g. ~/.nitro/client.yaml).
+	Environment = DefaultEnvironment
+
+	environmentName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+)
+
+// SetEnvironment takes the name of an environment and selects it, an empty
+// name selects the default environment. It returns an error if the name
+// cannot be used for the config file and docker resources.
+func SetEnvironment(name string) error {
+	if name == "" {
+		name = DefaultEnvironment
+	}
+
+	if !environmentName.MatchString(name) {
+		return fmt.Errorf("the environment name %q can only contain lowercase letters, numbers, and dashes", name)
+	}
+
+	Environment = name
+	FileName = name + ".yaml"
+
+	return nil
+}
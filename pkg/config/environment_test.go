@@ -0,0 +1,54 @@
+package config
+
+import "testing"
+
+func TestSetEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		wantFile string
+		wantErr  bool
+	}{
+		{
+			name:     "empty names use the default environment",
+			env:      "",
+			wantFile: "nitro.yaml",
+		},
+		{
+			name:     "named environments use their own config file",
+			env:      "client-a",
+			wantFile: "client-a.yaml",
+		},
+		{
+			name:    "names with path separators return an error",
+			env:     "../client",
+			wantErr: true,
+		},
+		{
+			name:    "names with uppercase letters return an error",
+			env:     "Client",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				Environment, FileName = DefaultEnvironment, "nitro.yaml"
+			}()
+
+			err := SetEnvironment(tt.env)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if FileName != tt.wantFile {
+				t.Errorf("SetEnvironment() file = %v, want %v", FileName, tt.wantFile)
+			}
+		})
+	}
+}
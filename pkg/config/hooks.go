@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// HookPostCreate runs after a sites container is created
+	HookPostCreate = "post_create"
+
+	// HookPostStart runs after a sites container is started, which includes when it is created
+	HookPostStart = "post_start"
+
+	// HookPreDestroy runs before a sites container is removed
+	HookPreDestroy = "pre_destroy"
+
+	// HookPreApply runs before apply makes any changes
+	HookPreApply = "pre_apply"
+
+	// HookPostApply runs after apply has updated the containers and the proxy
+	HookPostApply = "post_apply"
+)
+
+// Hook is a command that runs at a point in a sites lifecycle, or before and
+// after apply. Site hooks run in the sites container unless host is true, in
+// which case they run on the host from the sites path. Apply hooks run on the
+// host unless a site is set to run the command in that sites container.
+type Hook struct {
+	Run  string `json:"run" yaml:"run"`
+	Host bool   `json:"host,omitempty" yaml:"host,omitempty"`
+	Site string `json:"site,omitempty" yaml:"site,omitempty"`
+}
+
+// String returns the command with where it runs (e.g. composer install (host)).
+func (h Hook) String() string {
+	if h.Host {
+		return h.Run + " (host)"
+	}
+
+	return h.Run
+}
+
+// Hooks are the commands to run when a sites container is created, started,
+// or before it is destroyed.
+type Hooks struct {
+	PostCreate []Hook `json:"post_create,omitempty" yaml:"post_create,omitempty"`
+	PostStart  []Hook `json:"post_start,omitempty" yaml:"post_start,omitempty"`
+	PreDestroy []Hook `json:"pre_destroy,omitempty" yaml:"pre_destroy,omitempty"`
+}
+
+// For returns the hooks for the event (e.g. post_create).
+func (h Hooks) For(event string) []Hook {
+	switch event {
+	case HookPostCreate:
+		return h.PostCreate
+	case HookPostStart:
+		return h.PostStart
+	case HookPreDestroy:
+		return h.PreDestroy
+	}
+
+	return nil
+}
+
+// Validate checks that each hook has a command, site hooks always run in
+// their own container so they cannot set a site.
+func (h Hooks) Validate() error {
+	for _, event := range []string{HookPostCreate, HookPostStart, HookPreDestroy} {
+		for _, hook := range h.For(event) {
+			if strings.TrimSpace(hook.Run) == "" {
+				return fmt.Errorf("the %s hook requires a command to run", event)
+			}
+
+			if hook.Site != "" {
+				return fmt.Errorf("the %s hook %q cannot set a site", event, hook.Run)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyHooks are the commands to run before and after apply.
+type ApplyHooks struct {
+	PreApply  []Hook `json:"pre_apply,omitempty" yaml:"pre_apply,omitempty"`
+	PostApply []Hook `json:"post_apply,omitempty" yaml:"post_apply,omitempty"`
+}
+
+// For returns the hooks for the event (e.g. pre_apply).
+func (h ApplyHooks) For(event string) []Hook {
+	switch event {
+	case HookPreApply:
+		return h.PreApply
+	case HookPostApply:
+		return h.PostApply
+	}
+
+	return nil
+}
+
+// Validate checks that each hook has a command and that the sites for hooks
+// running in a container are in the config.
+func (h ApplyHooks) Validate(sites []Site) error {
+	for _, event := range []string{HookPreApply, HookPostApply} {
+		for _, hook := range h.For(event) {
+			if strings.TrimSpace(hook.Run) == "" {
+				return fmt.Errorf("the %s hook requires a command to run", event)
+			}
+
+			if hook.Site == "" {
+				continue
+			}
+
+			if hook.Host {
+				return fmt.Errorf("the %s hook %q cannot set a site and run on the host", event, hook.Run)
+			}
+
+			found := false
+			for _, s := range sites {
+				if s.Hostname == hook.Site {
+					found = true
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("the %s hook %q uses the site %s which is not in the config", event, hook.Run, hook.Site)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import "testing"
+
+func TestHooks_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   Hooks
+		wantErr bool
+	}{
+		{
+			name: "hooks can run in the container or on the host",
+			hooks: Hooks{
+				PostCreate: []Hook{{Run: "composer install"}},
+				PostStart:  []Hook{{Run: "php craft migrate/all --interactive=0"}},
+				PreDestroy: []Hook{{Run: "npm run build", Host: true}},
+			},
+		},
+		{
+			name:    "hooks require a command",
+			hooks:   Hooks{PostStart: []Hook{{Run: " "}}},
+			wantErr: true,
+		},
+		{
+			name:    "site hooks cannot set a site",
+			hooks:   Hooks{PostCreate: []Hook{{Run: "composer install", Site: "other.nitro"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hooks.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Hooks.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyHooks_Validate(t *testing.T) {
+	sites := []Site{{Hostname: "craft-dev.nitro"}}
+
+	tests := []struct {
+		name    string
+		hooks   ApplyHooks
+		wantErr bool
+	}{
+		{
+			name: "hooks run on the host or in a site container",
+			hooks: ApplyHooks{
+				PreApply:  []Hook{{Run: "git pull"}},
+				PostApply: []Hook{{Run: "php craft project-config/apply", Site: "craft-dev.nitro"}},
+			},
+		},
+		{
+			name:    "hooks require a command",
+			hooks:   ApplyHooks{PreApply: []Hook{{}}},
+			wantErr: true,
+		},
+		{
+			name:    "the site must be in the config",
+			hooks:   ApplyHooks{PostApply: []Hook{{Run: "php craft up", Site: "unknown.nitro"}}},
+			wantErr: true,
+		},
+		{
+			name:    "hooks with a site cannot run on the host",
+			hooks:   ApplyHooks{PostApply: []Hook{{Run: "php craft up", Site: "craft-dev.nitro", Host: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hooks.Validate(sites); (err != nil) != tt.wantErr {
+				t.Errorf("ApplyHooks.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// SecretsDirectoryName is the directory, in the nitro directory, that secret references are read from
+	SecretsDirectoryName = "secrets"
+
+	// reference matches ${VAR}, ${VAR:-default}, ${secret:name} and the escaped $${...}
+	reference = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+	secretName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+const secretPrefix = "secret:"
+
+// expander returns a function that replaces references in a value from the config file.
+// Environment variables are referenced with ${VAR}, or ${VAR:-default} to use a default
+// when the variable is empty, and secrets are referenced with ${secret:name} which reads
+// the file name from the secrets directory in dir. Use $${ to write a literal ${.
+func expander(dir string) func(string) (string, error) {
+	return func(value string) (string, error) {
+		if !strings.Contains(value, "${") {
+			return value, nil
+		}
+
+		var err error
+		expanded := reference.ReplaceAllStringFunc(value, func(match string) string {
+			if err != nil {
+				return ""
+			}
+
+			// escaped references are not expanded
+			if strings.HasPrefix(match, "$$") {
+				return match[1:]
+			}
+
+			expr := match[2 : len(match)-1]
+
+			if strings.HasPrefix(expr, secretPrefix) {
+				var secret string
+				secret, err = readSecret(dir, strings.TrimPrefix(expr, secretPrefix))
+				return secret
+			}
+
+			name, def := expr, ""
+			if i := strings.Index(expr, ":-"); i >= 0 {
+				name, def = expr[:i], expr[i+2:]
+			}
+
+			if name == "" {
+				err = fmt.Errorf("the reference %q is missing a variable name", match)
+				return ""
+			}
+
+			if v := os.Getenv(name); v != "" {
+				return v
+			}
+
+			if strings.Contains(expr, ":-") {
+				return def
+			}
+
+			err = fmt.Errorf("the environment variable %s is not set, set it or use ${%s:-default}", name, name)
+
+			return ""
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return expanded, nil
+	}
+}
+
+// readSecret reads a secret from the secrets directory. Secrets must only be
+// readable by the owner, so they are not shared with other users.
+func readSecret(dir, name string) (string, error) {
+	if !secretName.MatchString(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("the secret name %q is not valid", name)
+	}
+
+	file := filepath.Join(dir, SecretsDirectoryName, name)
+
+	info, err := os.Stat(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("unable to find the secret %q, create the file %s", name, file)
+		}
+
+		return "", err
+	}
+
+	// windows does not use unix permissions
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("the secret file %s can be read by other users, run chmod 600 %s", file, file)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the secret %q, %w", name, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// interpolateNode expands the references in all of the values in a yaml tree, keys are not expanded.
+func interpolateNode(n *yaml.Node, expand func(string) (string, error)) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		v, err := expand(n.Value)
+		if err != nil {
+			return err
+		}
+
+		if v != n.Value {
+			n.Value = v
+
+			// let the value resolve to its own type (e.g. a boolean)
+			n.Tag = ""
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if err := interpolateNode(n.Content[i], expand); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, c := range n.Content {
+			if err := interpolateNode(c, expand); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isReference returns true if the value contains a reference that is expanded when the config is loaded.
+func isReference(value string) bool {
+	for _, m := range reference.FindAllString(value, -1) {
+		if !strings.HasPrefix(m, "$$") {
+			return true
+		}
+	}
+
+	return false
+}
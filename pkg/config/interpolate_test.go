@@ -0,0 +1,226 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func Test_expander(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, SecretsDirectoryName), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, SecretsDirectoryName, "token"), []byte("my-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, SecretsDirectoryName, "shared"), []byte("shared"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NITRO_TEST_PORT", "3307")
+	defer os.Unsetenv("NITRO_TEST_PORT")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "values without references are not changed",
+			value: "~/dev/demo",
+			want:  "~/dev/demo",
+		},
+		{
+			name:  "environment variables are expanded",
+			value: "${NITRO_TEST_PORT}",
+			want:  "3307",
+		},
+		{
+			name:  "defaults are used for missing variables",
+			value: "${NITRO_TEST_MISSING:-5432}",
+			want:  "5432",
+		},
+		{
+			name:  "defaults are ignored when the variable is set",
+			value: "${NITRO_TEST_PORT:-5432}",
+			want:  "3307",
+		},
+		{
+			name:  "references can be part of a value",
+			value: "~/dev/${NITRO_TEST_PORT}/site",
+			want:  "~/dev/3307/site",
+		},
+		{
+			name:  "escaped references are not expanded",
+			value: "$${NITRO_TEST_PORT}",
+			want:  "${NITRO_TEST_PORT}",
+		},
+		{
+			name:  "secrets are read from the secrets directory",
+			value: "${secret:token}",
+			want:  "my-token",
+		},
+		{
+			name:    "missing variables return an error",
+			value:   "${NITRO_TEST_MISSING}",
+			wantErr: true,
+		},
+		{
+			name:    "missing secrets return an error",
+			value:   "${secret:missing}",
+			wantErr: true,
+		},
+		{
+			name:    "secrets outside of the secrets directory return an error",
+			value:   "${secret:../nitro.yaml}",
+			wantErr: true,
+		},
+		{
+			name:    "secrets readable by other users return an error",
+			value:   "${secret:shared}",
+			wantErr: runtime.GOOS != "windows",
+			want:    "shared",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expander(dir)(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expander() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expander() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Interpolate(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, DirectoryName)
+
+	if err := os.MkdirAll(filepath.Join(dir, SecretsDirectoryName), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, SecretsDirectoryName, "blackfire-token"), []byte("my-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NITRO_TEST_SERVER_ID", "my-id")
+	defer os.Unsetenv("NITRO_TEST_SERVER_ID")
+
+	config := `version: 2
+blackfire:
+    server_id: ${NITRO_TEST_SERVER_ID}
+    server_token: ${secret:blackfire-token}
+sites:
+    - hostname: demo.nitro
+      path: ~/dev/demo
+      version: "7.4"
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, FileName), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Blackfire.ServerID != "my-id" || cfg.Blackfire.ServerToken != "my-token" {
+		t.Errorf("expected the blackfire credentials to be expanded, got %v", cfg.Blackfire)
+	}
+
+	// saving the config should keep the references
+	cfg.Sites[0].Version = "8.0"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(saved), "my-token") || !strings.Contains(string(saved), "${secret:blackfire-token}") {
+		t.Errorf("expected the secret reference to be saved, got:\n%s", saved)
+	}
+
+	if !strings.Contains(string(saved), `version: "8.0"`) {
+		t.Errorf("expected the site to be updated, got:\n%s", saved)
+	}
+
+	// the redacted config should not show any of the values
+	redacted, err := cfg.Redacted()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(redacted), "my-id") || strings.Contains(string(redacted), "my-token") {
+		t.Errorf("expected the credentials to be redacted, got:\n%s", redacted)
+	}
+}
+
+func TestLoad_InterpolateEscapes(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, DirectoryName)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `version: 2
+sites:
+    - hostname: demo.nitro
+      path: ~/dev/demo
+      version: "7.4"
+      env:
+        APP_NAME: $${NITRO_TEST_UNSET}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, FileName), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.Sites[0].Env["APP_NAME"]; got != "${NITRO_TEST_UNSET}" {
+		t.Fatalf("expected the escaped reference to load as a literal, got %q", got)
+	}
+
+	// saving and loading again should keep the escape
+	cfg.Sites[0].Version = "8.0"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(saved), "$${NITRO_TEST_UNSET}") {
+		t.Errorf("expected the escaped reference to be saved, got:\n%s", saved)
+	}
+
+	cfg, err = Load(home)
+	if err != nil {
+		t.Fatalf("expected the saved config to load, got %v", err)
+	}
+
+	if got := cfg.Sites[0].Env["APP_NAME"]; got != "${NITRO_TEST_UNSET}" {
+		t.Errorf("expected the escaped reference after saving, got %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/craftcms/nitro/pkg/helpers"
+)
+
+var (
+	// ErrLocked is returned when a lock is held by another nitro command
+	ErrLocked = fmt.Errorf("another nitro command is currently running")
+
+	// ErrConfigChanged is returned when saving a config that was changed on disk since it was loaded
+	ErrConfigChanged = fmt.Errorf("the config file was changed by another command since it was loaded, run the command again")
+)
+
+const (
+	// applyLockFile is locked while apply is making changes to containers
+	applyLockFile = ".apply.lock"
+
+	// configLockFile is locked while the config file is being written
+	configLockFile = ".config.lock"
+)
+
+// Lock is an advisory lock on a file in the nitro config directory. Locks
+// are only respected by other nitro commands, and are released by the
+// operating system if the process exits.
+type Lock struct {
+	file *os.File
+}
+
+// LockApply takes the users home directory and locks the selected environment
+// so only one apply can change its containers at a time. When wait is false
+// and the lock is held by another command, ErrLocked is returned.
+func LockApply(home string, wait bool) (*Lock, error) {
+	name := applyLockFile
+	if Environment != DefaultEnvironment {
+		// each environment has its own lock (e.g. .client.apply.lock)
+		name = "." + Environment + applyLockFile
+	}
+
+	return lockFile(filepath.Join(home, DirectoryName, name), wait)
+}
+
+// Unlock releases the lock, calling it more than once has no effect.
+func (l *Lock) Unlock() error {
+	if l.file == nil {
+		return nil
+	}
+
+	f := l.file
+	l.file = nil
+
+	if err := unlock(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func lockFile(path string, wait bool) (*Lock, error) {
+	if err := helpers.MkdirIfNotExists(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lock(f, wait); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Lock{file: f}, nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockApply(t *testing.T) {
+	home := t.TempDir()
+
+	lock, err := LockApply(home, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second lock should fail while the first is held
+	if _, err := LockApply(home, false); err != ErrLocked {
+		t.Errorf("expected error %v, got %v", ErrLocked, err)
+	}
+
+	// other environments have their own lock
+	if err := SetEnvironment("client"); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := LockApply(home, false)
+	SetEnvironment("")
+	if err != nil {
+		t.Fatalf("expected the lock for another environment, got %v", err)
+	}
+
+	if err := other.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the lock can be acquired once released
+	lock, err = LockApply(home, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// unlocking again does nothing
+	if err := lock.Unlock(); err != nil {
+		t.Errorf("expected no error unlocking twice, got %v", err)
+	}
+}
+
+func TestConfig_Save(t *testing.T) {
+	home := t.TempDir()
+	file := filepath.Join(home, DirectoryName, FileName)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("# my sites\nsites:\n  - hostname: demo.nitro\n    path: ~/dev/demo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// saving more than once should not be seen as a change on disk
+	for i := 0; i < 2; i++ {
+		if err := cfg.EnableXdebug("demo.nitro"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("Save() returned an unexpected error %v", err)
+		}
+	}
+
+	// the permissions of the file should be kept
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the file mode to be %v, got %v", os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// no temp files should be left behind
+	files, err := ioutil.ReadDir(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range files {
+		if filepath.Ext(f.Name()) == ".tmp" {
+			t.Errorf("expected the temp file %s to be removed", f.Name())
+		}
+	}
+
+	// change the file on disk after it was loaded
+	stale, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.DisableXdebug("demo.nitro"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stale.Save(); err != ErrConfigChanged {
+		t.Errorf("expected error %v, got %v", ErrConfigChanged, err)
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the schema version of the config file written by
+// this version of nitro. Older files are upgraded when they are loaded.
+const CurrentVersion = 2
+
+// migration upgrades a config document to the version, it returns a
+// human readable list of the changes that were made to the document.
+type migration struct {
+	version int
+	migrate func(doc *yaml.Node) ([]string, error)
+}
+
+// migrations are run in order on any config that is older than the migrations version.
+var migrations = []migration{
+	{version: 2, migrate: migrateMultipass},
+}
+
+// v1Keys are the top level keys that only exist in Nitro v1 (multipass) configs.
+var v1Keys = []string{"name", "php", "cpus", "memory", "disk", "mounts"}
+
+// migrate takes the root node of a config file and upgrades it to the
+// current version. It returns the version the config started at and
+// the changes that were made, it will return an error if the config
+// is newer than this version of nitro understands.
+func migrate(doc *yaml.Node) (int, []string, error) {
+	root := documentRoot(doc)
+	if root == nil {
+		return CurrentVersion, nil, nil
+	}
+
+	version, err := detectVersion(root)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if version > CurrentVersion {
+		return version, nil, fmt.Errorf("the config file is version %d but this version of nitro only supports up to version %d, run `nitro selfupdate` to update", version, CurrentVersion)
+	}
+
+	var changes []string
+	for _, m := range migrations {
+		if version >= m.version {
+			continue
+		}
+
+		c, err := m.migrate(root)
+		if err != nil {
+			return version, nil, fmt.Errorf("unable to migrate the config to version %d, %w", m.version, err)
+		}
+
+		for _, change := range c {
+			changes = append(changes, fmt.Sprintf("v%d: %s", m.version, change))
+		}
+	}
+
+	return version, changes, nil
+}
+
+// detectVersion returns the schema version of a config. Configs without
+// a version are either Nitro v1 configs, which are version 1, or a
+// Nitro 2 config from before versioning which are version 2.
+func detectVersion(root *yaml.Node) (int, error) {
+	if v := mappingValue(root, "version"); v != nil {
+		version, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return 0, fmt.Errorf("the config version %q is not a number", v.Value)
+		}
+
+		return version, nil
+	}
+
+	for _, k := range v1Keys {
+		if mappingValue(root, k) != nil {
+			return 1, nil
+		}
+	}
+
+	return 2, nil
+}
+
+// migrateMultipass converts a Nitro v1 config, where sites were mounted
+// into a multipass virtual machine, into sites with a local path.
+func migrateMultipass(root *yaml.Node) ([]string, error) {
+	var changes []string
+
+	// the php version was global in v1
+	var php string
+	if v := mappingValue(root, "php"); v != nil {
+		php = v.Value
+	}
+
+	// mounts mapped a local directory to a path in the machine
+	type mount struct{ source, dest string }
+	var mounts []mount
+	if m := mappingValue(root, "mounts"); m != nil {
+		for _, n := range m.Content {
+			var v struct {
+				Source string `yaml:"source"`
+				Dest   string `yaml:"dest"`
+			}
+			if err := n.Decode(&v); err != nil {
+				return nil, err
+			}
+
+			mounts = append(mounts, mount{source: v.Source, dest: strings.TrimRight(v.Dest, "/")})
+		}
+	}
+
+	if sites := mappingValue(root, "sites"); sites != nil {
+		for _, s := range sites.Content {
+			hostname := ""
+			if h := mappingValue(s, "hostname"); h != nil {
+				hostname = h.Value
+			}
+
+			if mappingValue(s, "version") == nil && php != "" {
+				setScalar(s, "version", php)
+				changes = append(changes, fmt.Sprintf("set the php version for %s to %s", hostname, php))
+			}
+
+			// sites with a path are already using the new format
+			if mappingValue(s, "path") != nil {
+				continue
+			}
+
+			webroot := ""
+			if w := mappingValue(s, "webroot"); w != nil {
+				webroot = strings.TrimRight(w.Value, "/")
+			}
+
+			// find the most specific mount for the web root
+			var found *mount
+			for i, m := range mounts {
+				if webroot == m.dest || strings.HasPrefix(webroot, m.dest+"/") {
+					if found == nil || len(m.dest) > len(found.dest) {
+						found = &mounts[i]
+					}
+				}
+			}
+
+			if found == nil {
+				changes = append(changes, fmt.Sprintf("unable to find a mount for %s, set the path for the site manually", hostname))
+				continue
+			}
+
+			var sitePath, siteWebroot string
+			switch rel := strings.TrimPrefix(strings.TrimPrefix(webroot, found.dest), "/"); rel {
+			case "":
+				sitePath, siteWebroot = path.Dir(found.source), path.Base(found.source)
+			default:
+				sitePath, siteWebroot = path.Join(found.source, path.Dir(rel)), path.Base(rel)
+			}
+
+			setScalar(s, "path", sitePath)
+			setScalar(s, "webroot", siteWebroot)
+
+			changes = append(changes, fmt.Sprintf("set the path for %s to %s with the web root %s", hostname, sitePath, siteWebroot))
+		}
+	}
+
+	for _, k := range v1Keys {
+		if removeKey(root, k) {
+			changes = append(changes, fmt.Sprintf("removed the unused %q setting", k))
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const multipassConfig = `name: nitro-dev
+php: "7.4"
+cpus: "2"
+memory: 4G
+disk: 40G
+mounts:
+  - source: ~/dev/demo
+    dest: /home/ubuntu/sites/demo
+  - source: ~/dev
+    dest: /home/ubuntu/sites
+databases:
+  - engine: mysql
+    version: "5.7"
+    port: "3306"
+sites:
+  - hostname: demo.test
+    webroot: /home/ubuntu/sites/demo/web
+  - hostname: other.test
+    webroot: /home/ubuntu/sites/other/public
+  - hostname: missing.test
+    webroot: /var/www/missing/web
+`
+
+func Test_migrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		wantVersion int
+		wantChanges []string
+		wantSites   []Site
+		wantErr     bool
+	}{
+		{
+			name:        "nitro v1 configs are converted to sites with paths",
+			config:      multipassConfig,
+			wantVersion: 1,
+			wantChanges: []string{
+				"v2: set the php version for demo.test to 7.4",
+				"v2: set the path for demo.test to ~/dev/demo with the web root web",
+				"v2: set the php version for other.test to 7.4",
+				"v2: set the path for other.test to ~/dev/other with the web root public",
+				"v2: set the php version for missing.test to 7.4",
+				"v2: unable to find a mount for missing.test, set the path for the site manually",
+				`v2: removed the unused "name" setting`,
+				`v2: removed the unused "php" setting`,
+				`v2: removed the unused "cpus" setting`,
+				`v2: removed the unused "memory" setting`,
+				`v2: removed the unused "disk" setting`,
+				`v2: removed the unused "mounts" setting`,
+			},
+			wantSites: []Site{
+				{Hostname: "demo.test", Path: "~/dev/demo", Version: "7.4", Webroot: "web"},
+				{Hostname: "other.test", Path: "~/dev/other", Version: "7.4", Webroot: "public"},
+				{Hostname: "missing.test", Version: "7.4", Webroot: "/var/www/missing/web"},
+			},
+		},
+		{
+			name:        "configs without a version are nitro 2 configs",
+			config:      "sites:\n  - hostname: demo.nitro\n    path: ~/dev/demo\n",
+			wantVersion: 2,
+			wantSites:   []Site{{Hostname: "demo.nitro", Path: "~/dev/demo"}},
+		},
+		{
+			name:        "current configs are not changed",
+			config:      "version: 2\nsites:\n  - hostname: demo.nitro\n    path: ~/dev/demo\n",
+			wantVersion: 2,
+			wantSites:   []Site{{Hostname: "demo.nitro", Path: "~/dev/demo"}},
+		},
+		{
+			name:    "newer configs return an error",
+			config:  "version: 99\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid versions return an error",
+			config:  "version: two\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.config), &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			version, changes, err := migrate(&doc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("migrate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if version != tt.wantVersion {
+				t.Errorf("migrate() version = %v, want %v", version, tt.wantVersion)
+			}
+
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("migrate() changes = \ngot:\n%v,\nwant:\n%v", strings.Join(changes, "\n"), strings.Join(tt.wantChanges, "\n"))
+			}
+
+			var cfg Config
+			if err := doc.Decode(&cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(cfg.Sites, tt.wantSites) {
+				t.Errorf("migrate() sites = \ngot:\n%v,\nwant:\n%v", cfg.Sites, tt.wantSites)
+			}
+		})
+	}
+}
+
+func TestLoad_Migrate(t *testing.T) {
+	home := t.TempDir()
+	file := filepath.Join(home, DirectoryName, FileName)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(multipassConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Migrations) == 0 {
+		t.Errorf("expected the config to be migrated")
+	}
+
+	// the backup should be the original file
+	backup, err := ioutil.ReadFile(cfg.Backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(backup) != multipassConfig {
+		t.Errorf("expected the backup to match the original config, got:\n%s", backup)
+	}
+
+	// loading the upgraded config should not migrate again
+	upgraded, err := Load(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upgraded.Version != CurrentVersion {
+		t.Errorf("expected the config version to be %d, got %d", CurrentVersion, upgraded.Version)
+	}
+
+	if len(upgraded.Migrations) > 0 {
+		t.Errorf("expected no migrations, got %v", upgraded.Migrations)
+	}
+
+	if !reflect.DeepEqual(upgraded.Sites, cfg.Sites) {
+		t.Errorf("expected the sites to be saved, got:\n%v\nwant:\n%v", upgraded.Sites, cfg.Sites)
+	}
+}
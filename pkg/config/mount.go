@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MountTypeBind mounts a local directory into the container
+	MountTypeBind = "bind"
+
+	// MountTypeVolume mounts a named docker volume into the container
+	MountTypeVolume = "volume"
+)
+
+// Mount is an additional bind mount or named volume for a site container. Binds
+// use the local path as the source, which is relative to the sites path, and
+// volumes use the source as the volume name. The target is the path in the
+// container and is relative to /app if it is not absolute.
+type Mount struct {
+	Type     string `json:"type,omitempty" yaml:"type,omitempty"`
+	Source   string `json:"source,omitempty" yaml:"source,omitempty"`
+	Target   string `json:"target" yaml:"target"`
+	ReadOnly bool   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// GetType returns the type of mount, mounts without a type are binds.
+func (m *Mount) GetType() string {
+	if m.Type == "" {
+		return MountTypeBind
+	}
+
+	return m.Type
+}
+
+// GetTarget returns the absolute path of the mount in the container.
+func (m *Mount) GetTarget() string {
+	if path.IsAbs(m.Target) {
+		return path.Clean(m.Target)
+	}
+
+	return path.Join("/app", m.Target)
+}
+
+// GetVolumeName returns the name for a volume mount, if there is no source
+// the name is based on the target (e.g. node_modules or vendor).
+func (m *Mount) GetVolumeName() string {
+	if m.Source != "" {
+		return m.Source
+	}
+
+	return strings.Trim(strings.Replace(strings.TrimPrefix(m.GetTarget(), "/app"), "/", "_", -1), "_")
+}
+
+// GetAbsSource takes the users home directory and the site and returns the
+// local path for a bind mount. Sources that are not absolute are relative
+// to the sites path.
+func (m *Mount) GetAbsSource(home string, site Site) (string, error) {
+	source := m.Source
+	if !filepath.IsAbs(source) && !strings.HasPrefix(source, "~") {
+		source = filepath.Join(site.Path, source)
+	}
+
+	return cleanPath(home, source)
+}
+
+// Validate checks the type, source, and target of the mount.
+func (m *Mount) Validate() error {
+	if m.Target == "" {
+		return fmt.Errorf("mounts require a target")
+	}
+
+	switch m.GetType() {
+	case MountTypeBind:
+		if m.Source == "" {
+			return fmt.Errorf("the bind mount for %s requires a source", m.Target)
+		}
+	case MountTypeVolume:
+		if m.GetVolumeName() == "" {
+			return fmt.Errorf("the volume mount for %s requires a source", m.Target)
+		}
+	default:
+		return fmt.Errorf("the mount type %q for %s is not valid, use bind or volume", m.Type, m.Target)
+	}
+
+	if m.GetTarget() == "/app" {
+		return fmt.Errorf("the mount target %s is already used for the sites path", m.Target)
+	}
+
+	return nil
+}
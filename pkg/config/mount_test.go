@@ -0,0 +1,65 @@
+package config
+
+import "testing"
+
+func TestMount_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		mount          Mount
+		wantTarget     string
+		wantVolumeName string
+		wantErr        bool
+	}{
+		{
+			name:       "binds are the default type",
+			mount:      Mount{Source: "../plugin", Target: "/plugins/plugin"},
+			wantTarget: "/plugins/plugin",
+		},
+		{
+			name:           "volumes without a source are named using the target",
+			mount:          Mount{Type: "volume", Target: "node_modules"},
+			wantTarget:     "/app/node_modules",
+			wantVolumeName: "node_modules",
+		},
+		{
+			name:           "volumes can be named",
+			mount:          Mount{Type: "volume", Source: "composer-cache", Target: "web/cpresources"},
+			wantTarget:     "/app/web/cpresources",
+			wantVolumeName: "composer-cache",
+		},
+		{
+			name:    "binds require a source",
+			mount:   Mount{Target: "/plugins/plugin"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown types return an error",
+			mount:   Mount{Type: "tmpfs", Target: "/tmp"},
+			wantErr: true,
+		},
+		{
+			name:    "the site path can not be replaced",
+			mount:   Mount{Source: "~/dev/other", Target: "/app"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mount.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Mount.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := tt.mount.GetTarget(); got != tt.wantTarget {
+				t.Errorf("Mount.GetTarget() = %v, want %v", got, tt.wantTarget)
+			}
+
+			if tt.wantVolumeName != "" && tt.mount.GetVolumeName() != tt.wantVolumeName {
+				t.Errorf("Mount.GetVolumeName() = %v, want %v", tt.mount.GetVolumeName(), tt.wantVolumeName)
+			}
+		})
+	}
+}
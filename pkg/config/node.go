@@ -0,0 +1,296 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent is the indent used for new config files.
+const defaultIndent = 4
+
+// encodeInto takes the existing contents of a config file and updates the
+// yaml tree with the value. Only the keys that changed are updated so
+// comments, the order of keys, and quoting in the file are kept. If the
+// existing file is empty or cannot be parsed the value is encoded as is.
+// Values in the file that reference an environment variable or secret are
+// kept when expand resolves them to the new value.
+func encodeInto(existing []byte, v interface{}, expand func(string) (string, error)) ([]byte, error) {
+	var updated yaml.Node
+	if err := updated.Encode(v); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(existing, &doc); err != nil || documentRoot(&doc) == nil {
+		return encodeNode(&updated, defaultIndent)
+	}
+
+	mergeNode(documentRoot(&doc), &updated, expand)
+
+	return encodeNode(&doc, detectIndent(existing))
+}
+
+func encodeNode(n *yaml.Node, indent int) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(indent)
+
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// detectIndent returns the indent of the first nested key in a file.
+func detectIndent(data []byte) int {
+	for _, l := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(l, " ")
+		if trimmed == "" || trimmed == l || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if indent := len(l) - len(trimmed); indent >= 2 {
+			return indent
+		}
+	}
+
+	return defaultIndent
+}
+
+// mergeNode updates the dst node with the values from src while
+// keeping the comments and styles on the dst node.
+func mergeNode(dst, src *yaml.Node, expand func(string) (string, error)) {
+	if dst.Kind != src.Kind {
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *src
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+
+		return
+	}
+
+	switch src.Kind {
+	case yaml.MappingNode:
+		mergeMapping(dst, src, expand)
+	case yaml.SequenceNode:
+		mergeSequence(dst, src, expand)
+	case yaml.ScalarNode:
+		if dst.Value == src.Value {
+			return
+		}
+
+		// keep references so secrets are not written to the file, and escaped
+		// references (e.g. $${VAR}) so they are not expanded on the next load
+		if expand != nil && strings.Contains(dst.Value, "${") {
+			if v, err := expand(dst.Value); err == nil && v == src.Value {
+				return
+			}
+		}
+
+		dst.Value = src.Value
+
+		// keep the type in the file when the value still resolves to it,
+		// e.g. a port written as a number should not become quoted
+		plain := &yaml.Node{Kind: yaml.ScalarNode, Value: src.Value}
+		if dst.Tag != src.Tag && plain.ShortTag() != dst.Tag {
+			dst.Tag, dst.Style = src.Tag, src.Style
+		}
+	}
+}
+
+func mergeMapping(dst, src *yaml.Node, expand func(string) (string, error)) {
+	// remove keys that are no longer set, empty values are
+	// kept since they are the same as omitting the key
+	for i := 0; i+1 < len(dst.Content); {
+		if mappingValue(src, dst.Content[i].Value) == nil && !isEmptyNode(dst.Content[i+1]) {
+			dst.Content = append(dst.Content[:i], dst.Content[i+2:]...)
+			continue
+		}
+
+		i += 2
+	}
+
+	// update existing keys and add new keys next to the keys around them
+	insert := -1
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+
+		if idx := mappingIndex(dst, key.Value); idx >= 0 {
+			mergeNode(dst.Content[idx+1], value, expand)
+			insert = idx + 2
+
+			continue
+		}
+
+		at := insert
+		if at < 0 {
+			at = len(dst.Content)
+			for j := i + 2; j+1 < len(src.Content); j += 2 {
+				if idx := mappingIndex(dst, src.Content[j].Value); idx >= 0 {
+					at = idx
+					break
+				}
+			}
+		}
+
+		// keep the comment at the top of the file above a new first key
+		if at == 0 && len(dst.Content) > 0 {
+			key.HeadComment, dst.Content[0].HeadComment = dst.Content[0].HeadComment, ""
+		}
+
+		content := append([]*yaml.Node{key, value}, dst.Content[at:]...)
+		dst.Content = append(dst.Content[:at], content...)
+		insert = at + 2
+	}
+}
+
+func mergeSequence(dst, src *yaml.Node, expand func(string) (string, error)) {
+	used := make([]bool, len(dst.Content))
+
+	var content []*yaml.Node
+	for i, s := range src.Content {
+		id := nodeIdentity(s)
+
+		match := -1
+		for j, d := range dst.Content {
+			if !used[j] && nodeIdentity(d) == id {
+				match = j
+				break
+			}
+		}
+
+		// when items are only edited, match items without a name by position
+		if match == -1 && len(src.Content) == len(dst.Content) && !used[i] && !isNamed(s) && !isNamed(dst.Content[i]) {
+			match = i
+		}
+
+		if match == -1 {
+			content = append(content, s)
+			continue
+		}
+
+		used[match] = true
+		mergeNode(dst.Content[match], s, expand)
+		content = append(content, dst.Content[match])
+	}
+
+	dst.Content = content
+}
+
+// nodeIdentity returns a key used to match items in a list, sites are
+// matched by hostname and containers by name. Any other item is matched
+// using its values.
+func nodeIdentity(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value
+	case yaml.MappingNode:
+		for _, k := range []string{"hostname", "name"} {
+			if v := mappingValue(n, k); v != nil {
+				return k + "=" + v.Value
+			}
+		}
+
+		var values []string
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i+1].Kind == yaml.ScalarNode {
+				values = append(values, n.Content[i].Value+"="+n.Content[i+1].Value)
+			}
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	return ""
+}
+
+// isNamed returns true if the node is a mapping with a hostname or name.
+func isNamed(n *yaml.Node) bool {
+	return mappingValue(n, "hostname") != nil || mappingValue(n, "name") != nil
+}
+
+// isEmptyNode returns true if the node is the zero value for a type.
+func isEmptyNode(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		switch n.Value {
+		case "", "~", "null", "false", "0":
+			return true
+		}
+	case yaml.MappingNode, yaml.SequenceNode:
+		return len(n.Content) == 0
+	}
+
+	return false
+}
+
+// documentRoot returns the top level mapping of a yaml document.
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return nil
+		}
+
+		doc = doc.Content[0]
+	}
+
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	return doc
+}
+
+// mappingIndex returns the index of the key in a mapping node or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return -1
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mappingValue returns the value node for the key in a mapping node.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(m, key); i >= 0 {
+		return m.Content[i+1]
+	}
+
+	return nil
+}
+
+// setScalar sets the key in a mapping node to a string value, adding the key if it does not exist.
+func setScalar(m *yaml.Node, key, value string) {
+	if v := mappingValue(m, key); v != nil {
+		v.Kind, v.Tag, v.Value, v.Content = yaml.ScalarNode, "!!str", value, nil
+		return
+	}
+
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+}
+
+// removeKey removes the key and its value from a mapping node and returns true if it was found.
+func removeKey(m *yaml.Node, key string) bool {
+	if i := mappingIndex(m, key); i >= 0 {
+		m.Content = append(m.Content[:i], m.Content[i+2:]...)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"testing"
+)
+
+func Test_encodeInto(t *testing.T) {
+	existing := `# my nitro config
+blackfire:
+  server_id: my-id # the id
+databases:
+  # primary db
+  - engine: mysql
+    version: "8.0"
+    port: 3306
+sites:
+  # the demo site
+  - hostname: demo.nitro
+    path: ~/dev/demo
+    version: "8.0"
+    php:
+      display_errors: false
+      memory_limit: 256M # big
+    webroot: web
+    xdebug: false
+    blackfire: false
+  - hostname: other.nitro
+    path: ~/dev/other
+    version: "7.4"
+    webroot: web
+    xdebug: false
+    blackfire: false
+services:
+  redis: true
+`
+
+	tests := []struct {
+		name     string
+		existing string
+		config   func() *Config
+		want     string
+	}{
+		{
+			name:     "changes only update the keys that changed",
+			existing: existing,
+			config: func() *Config {
+				c := &Config{
+					Version:   CurrentVersion,
+					Blackfire: Blackfire{ServerID: "my-id"},
+					Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3307"}},
+					Services:  Services{Redis: true},
+					Sites: []Site{
+						{Hostname: "demo.nitro", Path: "~/dev/demo", Version: "8.0", Webroot: "web", PHP: PHP{MemoryLimit: "256M", MaxInputVars: 300}},
+						{Hostname: "other.nitro", Path: "~/dev/other", Version: "7.4", Webroot: "web"},
+					},
+				}
+
+				_ = c.EnableXdebug("demo.nitro")
+				_ = c.SetSiteAlias("demo.nitro", "alias.nitro")
+
+				return c
+			},
+			want: `# my nitro config
+version: 2
+blackfire:
+  server_id: my-id # the id
+databases:
+  # primary db
+  - engine: mysql
+    version: "8.0"
+    port: 3307
+sites:
+  # the demo site
+  - hostname: demo.nitro
+    aliases:
+      - alias.nitro
+    path: ~/dev/demo
+    version: "8.0"
+    php:
+      display_errors: false
+      max_input_vars: 300
+      memory_limit: 256M # big
+    webroot: web
+    xdebug: true
+    blackfire: false
+  - hostname: other.nitro
+    path: ~/dev/other
+    version: "7.4"
+    webroot: web
+    xdebug: false
+    blackfire: false
+services:
+  dynamodb: false
+  mailhog: false
+  minio: false
+  redis: true
+`,
+		},
+		{
+			name:     "removed items keep the comments of the remaining items",
+			existing: existing,
+			config: func() *Config {
+				return &Config{
+					Version:   CurrentVersion,
+					Blackfire: Blackfire{ServerID: "my-id"},
+					Services:  Services{Redis: true},
+					Sites: []Site{
+						{Hostname: "demo.nitro", Path: "~/dev/demo", Version: "8.0", Webroot: "web", PHP: PHP{MemoryLimit: "256M"}},
+					},
+				}
+			},
+			want: `# my nitro config
+version: 2
+blackfire:
+  server_id: my-id # the id
+sites:
+  # the demo site
+  - hostname: demo.nitro
+    path: ~/dev/demo
+    version: "8.0"
+    php:
+      display_errors: false
+      memory_limit: 256M # big
+    webroot: web
+    xdebug: false
+    blackfire: false
+services:
+  dynamodb: false
+  mailhog: false
+  minio: false
+  redis: true
+`,
+		},
+		{
+			name:     "empty files use the default indent",
+			existing: "",
+			config: func() *Config {
+				return &Config{
+					Version:   CurrentVersion,
+					Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+				}
+			},
+			want: `version: 2
+databases:
+    - engine: mysql
+      version: "8.0"
+      port: "3306"
+services:
+    dynamodb: false
+    mailhog: false
+    minio: false
+    redis: false
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeInto([]byte(tt.existing), tt.config(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("encodeInto() = \ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/craftcms/nitro/pkg/pathexists"
+)
+
+var (
+	// ProjectFileName is the name of the project-local config file that
+	// is committed with a project and merged into the global config.
+	ProjectFileName = ".nitro.yaml"
+
+	// ErrNoProjectFile is returned when a project config file cannot be found
+	ErrNoProjectFile = fmt.Errorf("there is no project config file")
+)
+
+// Project represents the .nitro.yaml file that lives at the root of a project.
+// It declares the sites, databases, and custom containers the project needs
+// so a fresh clone can be applied without entering them by hand.
+type Project struct {
+	Containers []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Databases  []Database  `json:"databases,omitempty" yaml:"databases,omitempty"`
+	Sites      []Site      `json:"sites,omitempty" yaml:"sites,omitempty"`
+	File       string      `json:"-" yaml:"-"`
+
+	// raw is the project before the references were expanded, it is used when
+	// saving the merged sites and containers so secrets are not written
+	raw *Project
+}
+
+// FindProject takes a directory and walks up the tree looking for a
+// project config file. It returns ErrNoProjectFile if none is found.
+func FindProject(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		file := filepath.Join(dir, ProjectFileName)
+		if pathexists.IsFile(file) {
+			return file, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoProjectFile
+		}
+
+		dir = parent
+	}
+}
+
+// LoadProject takes the users home directory and the current working directory
+// and returns the project config that applies to the directory. Site paths in
+// the project file are relative to the file, so they are resolved and stored
+// using the same ~ notation the global config uses. References are expanded
+// the same way as the global config, using the secrets in the nitro directory.
+func LoadProject(home, wd string) (*Project, error) {
+	file, err := FindProject(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &Project{File: file}
+
+	// be strict about unknown keys so typos in a shared file are not ignored
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(raw); err != nil {
+		return nil, fmt.Errorf("unable to parse %s, %w", file, err)
+	}
+
+	p := &Project{File: file, raw: raw}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse %s, %w", file, err)
+	}
+
+	if root := documentRoot(&doc); root != nil {
+		// expand the references to environment variables and secrets
+		if err := interpolateNode(root, expander(filepath.Join(home, DirectoryName))); err != nil {
+			return nil, fmt.Errorf("unable to load %s, %w", file, err)
+		}
+
+		if err := doc.Decode(p); err != nil {
+			return nil, fmt.Errorf("unable to parse %s, %w", file, err)
+		}
+	}
+
+	for _, proj := range []*Project{p, raw} {
+		if err := proj.resolvePaths(home); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// resolvePaths replaces the site paths, which are relative to the project file,
+// with the path using the ~ notation.
+func (p *Project) resolvePaths(home string) error {
+	dir := filepath.Dir(p.File)
+	for i, s := range p.Sites {
+		if s.Hostname == "" {
+			return fmt.Errorf("a site in %s is missing a hostname", p.File)
+		}
+
+		path, err := projectPath(home, dir, s.Path)
+		if err != nil {
+			return err
+		}
+
+		p.Sites[i].Path = path
+	}
+
+	return nil
+}
+
+// MergeProject takes a project config and merges it into the global config.
+// The project owns the sites it declares, so they replace a global site with
+// the same hostname and path. Databases and containers are shared across
+// projects and must match any existing definition exactly. It returns true
+// if the global config was changed and an error for any conflict.
+func (c *Config) MergeProject(p *Project) (bool, error) {
+	changed := false
+
+	for _, s := range p.Sites {
+		existing := -1
+		for i, e := range c.Sites {
+			if e.Hostname == s.Hostname {
+				existing = i
+				continue
+			}
+
+			// the hostname and aliases can't be claimed by another site
+			for _, name := range append([]string{s.Hostname}, s.Aliases...) {
+				if name == e.Hostname {
+					return false, fmt.Errorf("the alias %q in %s is already used by the site %s", name, p.File, e.Hostname)
+				}
+
+				for _, a := range e.Aliases {
+					if a == name {
+						return false, fmt.Errorf("the hostname %q in %s is already an alias for the site %s", name, p.File, e.Hostname)
+					}
+				}
+			}
+		}
+
+		switch existing {
+		case -1:
+			if err := c.AddSite(s); err != nil {
+				return false, err
+			}
+
+			changed = true
+		default:
+			if c.Sites[existing].Path != s.Path {
+				return false, fmt.Errorf("the site %s in %s conflicts with an existing site using the path %s", s.Hostname, p.File, c.Sites[existing].Path)
+			}
+
+			if !reflect.DeepEqual(c.Sites[existing], s) {
+				c.Sites[existing] = s
+				changed = true
+			}
+		}
+	}
+
+	for _, d := range p.Databases {
+		found := false
+		for _, e := range c.Databases {
+			if e.Port != d.Port {
+				continue
+			}
+
+			if e.Engine != d.Engine || e.Version != d.Version {
+				return false, fmt.Errorf("the database %s %s in %s conflicts with %s %s already using port %s", d.Engine, d.Version, p.File, e.Engine, e.Version, e.Port)
+			}
+
+			found = true
+		}
+
+		if !found {
+			c.Databases = append(c.Databases, d)
+			changed = true
+		}
+	}
+
+	for _, ct := range p.Containers {
+		e, err := c.FindContainerByName(ct.Name)
+		if err != nil {
+			if err := c.AddContainer(ct); err != nil {
+				return false, err
+			}
+
+			changed = true
+
+			continue
+		}
+
+		if !reflect.DeepEqual(*e, ct) {
+			return false, fmt.Errorf("the container %s in %s conflicts with an existing container using the image %s:%s", ct.Name, p.File, e.Image, e.Tag)
+		}
+	}
+
+	if changed && p.raw != nil {
+		c.projects = append(c.projects, p.raw)
+	}
+
+	return changed, nil
+}
+
+// withProjectReferences returns a copy of the config with the sites and containers
+// merged from a project replaced by the project values before they were expanded.
+func (c *Config) withProjectReferences() *Config {
+	cp := *c
+	cp.Sites = append([]Site(nil), c.Sites...)
+	cp.Containers = append([]Container(nil), c.Containers...)
+
+	for _, p := range c.projects {
+		for _, s := range p.Sites {
+			for i := range cp.Sites {
+				if cp.Sites[i].Hostname == s.Hostname {
+					cp.Sites[i] = s
+				}
+			}
+		}
+
+		for _, ct := range p.Containers {
+			for i := range cp.Containers {
+				if cp.Containers[i].Name == ct.Name {
+					cp.Containers[i] = ct
+				}
+			}
+		}
+	}
+
+	return &cp
+}
+
+// Untrusted takes the users home directory and a project config and returns the
+// host hooks, builds, and host paths outside of the project directory the project
+// adds or changes compared to the global config. Host hooks run commands on the
+// host, builds run the projects Dockerfile, and the site path, bind mounts, and
+// env_file give the container access to host files, so they are confirmed before
+// a project file is merged (e.g. demo.nitro post_create: composer install).
+func (c *Config) Untrusted(home string, p *Project) []string {
+	dir := filepath.Dir(p.File)
+
+	var list []string
+	for _, s := range p.Sites {
+		var existing *Site
+		for i := range c.Sites {
+			if c.Sites[i].Hostname == s.Hostname {
+				existing = &c.Sites[i]
+			}
+		}
+
+		if existing == nil || existing.Path != s.Path {
+			if path, err := s.GetAbsPath(home); err != nil || outside(dir, path) {
+				list = append(list, fmt.Sprintf("%s path: %s", s.Hostname, s.Path))
+			}
+		}
+
+		if s.Build != nil && (existing == nil || !reflect.DeepEqual(existing.Build, s.Build)) {
+			list = append(list, fmt.Sprintf("%s build: %s", s.Hostname, filepath.Join(s.Build.Context, s.Build.GetDockerfile())))
+		}
+
+		for _, m := range s.Mounts {
+			if m.GetType() != MountTypeBind || (existing != nil && containsMount(existing.Mounts, m)) {
+				continue
+			}
+
+			if source, err := m.GetAbsSource(home, s); err != nil || outside(dir, source) {
+				list = append(list, fmt.Sprintf("%s mount: %s", s.Hostname, m.Source))
+			}
+		}
+
+		if s.EnvFile != "" && (existing == nil || existing.EnvFile != s.EnvFile) {
+			if file, err := s.EnvFilePath(home); err != nil || outside(dir, file) {
+				list = append(list, fmt.Sprintf("%s env_file: %s", s.Hostname, s.EnvFile))
+			}
+		}
+
+		for _, event := range []string{HookPostCreate, HookPostStart, HookPreDestroy} {
+			for _, h := range s.Hooks.For(event) {
+				if !h.Host || (existing != nil && containsHook(existing.Hooks.For(event), h)) {
+					continue
+				}
+
+				list = append(list, fmt.Sprintf("%s %s: %s", s.Hostname, event, h.Run))
+			}
+		}
+	}
+
+	return list
+}
+
+// outside returns true if the path is not in the directory.
+func outside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// containsMount returns true if the mount is in the list.
+func containsMount(mounts []Mount, mount Mount) bool {
+	for _, m := range mounts {
+		if m == mount {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsHook returns true if the hook is in the list.
+func containsHook(hooks []Hook, hook Hook) bool {
+	for _, h := range hooks {
+		if h == hook {
+			return true
+		}
+	}
+
+	return false
+}
+
+// projectPath resolves a site path from a project file relative to the
+// project directory and returns it using the ~ notation for the home dir.
+func projectPath(home, dir, path string) (string, error) {
+	switch {
+	case path == "", path == ".":
+		path = dir
+	case strings.HasPrefix(path, "~"):
+		path = strings.Replace(path, "~", home, 1)
+	case !filepath.IsAbs(path):
+		path = filepath.Join(dir, path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(abs); err != nil {
+		return "", fmt.Errorf("unable to find the site path %s, %w", abs, err)
+	}
+
+	home = filepath.Clean(home)
+	if strings.HasPrefix(abs, home+string(os.PathSeparator)) {
+		return "~" + strings.TrimPrefix(abs, home), nil
+	}
+
+	return abs, nil
+}
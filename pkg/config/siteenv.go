@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// managedEnvs are the environment variables nitro sets on site containers that are not in DefaultEnvs.
+var managedEnvs = []string{"COMPOSER_HOME", "PHP_IDE_CONFIG"}
+
+// CustomEnvs takes the users home directory and returns the sites custom
+// environment variables, sorted by name (e.g. CRAFT_ENVIRONMENT=dev). The
+// variables from the env_file are loaded first and the env map overrides
+// them. An env_file that is not absolute is relative to the sites path.
+// It returns an error if a variable is managed by nitro.
+func (s *Site) CustomEnvs(home string) ([]string, error) {
+	envs := make(map[string]string)
+
+	if s.EnvFile != "" {
+		p, err := s.EnvFilePath(home)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the env file for %s, %w", s.Hostname, err)
+		}
+
+		for k, v := range parseEnvFile(string(content)) {
+			envs[k] = v
+		}
+	}
+
+	for k, v := range s.Env {
+		envs[k] = v
+	}
+
+	var list []string
+	for k, v := range envs {
+		if isManagedEnv(k) {
+			return nil, fmt.Errorf("the environment variable %s for %s is set by nitro and cannot be changed", k, s.Hostname)
+		}
+
+		list = append(list, k+"="+v)
+	}
+
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// EnvFilePath returns the full path to the sites env_file, an env_file that
+// is not absolute is relative to the sites path.
+func (s *Site) EnvFilePath(home string) (string, error) {
+	file := s.EnvFile
+	if !filepath.IsAbs(file) && !strings.HasPrefix(file, "~") {
+		file = filepath.Join(s.Path, file)
+	}
+
+	return cleanPath(home, file)
+}
+
+// parseEnvFile parses the lines of an env file (e.g. KEY=value) and ignores
+// comments, empty lines, and export. Values can be wrapped in quotes.
+func parseEnvFile(content string) map[string]string {
+	envs := make(map[string]string)
+
+	for _, l := range strings.Split(content, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		l = strings.TrimPrefix(l, "export ")
+
+		sp := strings.SplitN(l, "=", 2)
+		if len(sp) != 2 {
+			continue
+		}
+
+		k, v := strings.TrimSpace(sp[0]), strings.TrimSpace(sp[1])
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+
+		envs[k] = v
+	}
+
+	return envs
+}
+
+func isManagedEnv(name string) bool {
+	if _, ok := DefaultEnvs[name]; ok {
+		return true
+	}
+
+	for _, e := range managedEnvs {
+		if e == name {
+			return true
+		}
+	}
+
+	return false
+}
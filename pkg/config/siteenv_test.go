@@ -0,0 +1,76 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSite_CustomEnvs(t *testing.T) {
+	home := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(home, "dev", "demo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	envFile := `# the site environment
+CRAFT_ENVIRONMENT=dev
+export API_KEY="abc123"
+FEATURE_FLAG='on'
+`
+	if err := ioutil.WriteFile(filepath.Join(home, "dev", "demo", ".env.nitro"), []byte(envFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		site    Site
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "sites without envs return nothing",
+			site: Site{Hostname: "demo.nitro", Path: "~/dev/demo"},
+		},
+		{
+			name: "env maps are sorted",
+			site: Site{Hostname: "demo.nitro", Path: "~/dev/demo", Env: map[string]string{"Z_VAR": "z", "CRAFT_ENVIRONMENT": "dev"}},
+			want: []string{"CRAFT_ENVIRONMENT=dev", "Z_VAR=z"},
+		},
+		{
+			name: "env files are relative to the site path",
+			site: Site{Hostname: "demo.nitro", Path: "~/dev/demo", EnvFile: ".env.nitro"},
+			want: []string{"API_KEY=abc123", "CRAFT_ENVIRONMENT=dev", "FEATURE_FLAG=on"},
+		},
+		{
+			name: "env maps override env files",
+			site: Site{Hostname: "demo.nitro", Path: "~/dev/demo", EnvFile: "~/dev/demo/.env.nitro", Env: map[string]string{"CRAFT_ENVIRONMENT": "staging"}},
+			want: []string{"API_KEY=abc123", "CRAFT_ENVIRONMENT=staging", "FEATURE_FLAG=on"},
+		},
+		{
+			name:    "missing env files return an error",
+			site:    Site{Hostname: "demo.nitro", Path: "~/dev/demo", EnvFile: ".env.missing"},
+			wantErr: true,
+		},
+		{
+			name:    "variables managed by nitro return an error",
+			site:    Site{Hostname: "demo.nitro", Path: "~/dev/demo", Env: map[string]string{"PHP_MEMORY_LIMIT": "1G"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.site.CustomEnvs(home)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Site.CustomEnvs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Site.CustomEnvs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package containerexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Run runs the command in the container and writes the stdout and stderr to w
+// as the command runs. It waits for the command to finish and returns the exit
+// code, the error is only for problems running the command.
+func Run(ctx context.Context, docker client.ContainerAPIClient, containerID string, config types.ExecConfig, w io.Writer) (int, error) {
+	config.AttachStdout = true
+	config.AttachStderr = true
+	config.Tty = false
+
+	// create the exec
+	exec, err := docker.ContainerExecCreate(ctx, containerID, config)
+	if err != nil {
+		return 0, err
+	}
+
+	// attach to the container
+	attach, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{
+		Tty: false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer attach.Close()
+
+	// copy the output until the command is done
+	if _, err := stdcopy.StdCopy(w, w, attach.Reader); err != nil {
+		return 0, fmt.Errorf("unable to copy the output of container, %w", err)
+	}
+
+	// start the exec
+	if err := docker.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{}); err != nil {
+		return 0, fmt.Errorf("unable to start the container, %w", err)
+	}
+
+	// wait for the container exec to complete
+	for {
+		resp, err := docker.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		if !resp.Running {
+			return resp.ExitCode, nil
+		}
+	}
+}
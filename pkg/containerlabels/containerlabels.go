@@ -1,62 +1,203 @@
 package containerlabels
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
 )
 
-const (
+var (
 	// Nitro is used to label a container as "for nitro"
-	Nitro = "com.craftcms.nitro"
+	Nitro string
 
 	// NitroContainer is used to identify a custom container added to the config
-	NitroContainer = "com.craftcms.nitro.container"
+	NitroContainer string
 
 	// NitroContainerPort is used to identify a custom containers port in the config
-	NitroContainerPort = "com.craftcms.nitro.container-port"
+	NitroContainerPort string
 
 	// DatabaseCompatibility is the compatibility of the database (e.g. mariadb and mysql are compatible)
-	DatabaseCompatibility = "com.craftcms.nitro.database-compatibility"
+	DatabaseCompatibility string
 
 	// DatabaseEngine is used to identify the engine that is being used for a database container (e.g. mysql, postgres)
-	DatabaseEngine = "com.craftcms.nitro.database-engine"
+	DatabaseEngine string
 
 	// DatabasePort is used to identify the port that is being used for a database container (e.g. mysql, postgres)
-	DatabasePort = "com.craftcms.nitro.database-port"
+	DatabasePort string
 
 	// DatabaseVersion is the version of the database the container is running (e.g. 11, 12, 5.7)
-	DatabaseVersion = "com.craftcms.nitro.database-version"
+	DatabaseVersion string
+
+	// Env is used to label a site container with the names of the custom environment variables
+	Env string
 
 	// Extensions is used for a list of comma seperated extensions for a site
-	Extensions = "com.craftcms.nitro.extensions"
+	Extensions string
+
+	// ExtensionsBase is used to label an image with extensions with the ID of the base image it was built from
+	ExtensionsBase string
 
 	// Host is used to identify a web application by the hostname of the site (e.g demo.nitro)
-	Host = "com.craftcms.nitro.host"
+	Host string
 
 	// PAth is used for containers that mount specific paths such as composer and npm
-	Path = "com.craftcms.nitro.path"
+	Path string
 
 	// Network is used to label a network for an environment
-	Network = "com.craftcms.nitro.network"
+	Network string
 
 	// Volume is used to identify a volume for an environment
-	Volume = "com.craftcms.nitro.volume"
+	Volume string
+
+	// PHPINI is used to label a site container with the php.ini directives written to the container
+	PHPINI string
 
 	// Proxy is the label used to identify the proxy container
-	Proxy = "com.craftcms.nitro.proxy"
+	Proxy string
 
 	// ProxyVersion is used to label a proxy container with a specific version
-	ProxyVersion = "com.craftcms.nitro.proxy-version"
+	ProxyVersion string
 
 	// Type is used to identity the type of container
-	Type = "com.craftcms.nitro.type"
+	Type string
 
 	// Webroot is used to label a container with the webroot for the site
-	Webroot = "com.craftcms.nitro.webroot"
+	Webroot string
 )
 
+// namespace is the prefix for all of the labels in the default environment.
+const namespace = "com.craftcms.nitro"
+
+// environment is the name of the selected environment.
+var environment = config.DefaultEnvironment
+
+func init() {
+	setNamespace(namespace)
+}
+
+// UseEnvironment takes the name of an environment and namespaces the labels,
+// along with the network, proxy, volume, and container names, so each
+// environment is isolated from the others. The default environment keeps
+// the names it has always used.
+func UseEnvironment(name string) {
+	if name == "" {
+		name = config.DefaultEnvironment
+	}
+
+	environment = name
+
+	switch name {
+	case config.DefaultEnvironment:
+		setNamespace(namespace)
+	default:
+		setNamespace(namespace + "-" + name)
+	}
+}
+
+// NetworkName returns the name of the docker network for the environment (e.g. nitro-network).
+func NetworkName() string {
+	if environment == config.DefaultEnvironment {
+		return "nitro-network"
+	}
+
+	return fmt.Sprintf("nitro-%s-network", environment)
+}
+
+// ProxyName returns the name of the proxy container for the environment (e.g. nitro-proxy).
+func ProxyName() string {
+	if environment == config.DefaultEnvironment {
+		return "nitro-proxy"
+	}
+
+	return fmt.Sprintf("nitro-%s-proxy", environment)
+}
+
+// VolumeName returns the name of the volume used by the proxy for the environment (e.g. nitro).
+func VolumeName() string {
+	if environment == config.DefaultEnvironment {
+		return "nitro"
+	}
+
+	return "nitro-" + environment
+}
+
+// RepositoryName takes the name of a repository for images nitro builds and returns the
+// repository for the environment, images are labeled per environment so they are not
+// shared with other environments (e.g. nitro-client-extensions).
+func RepositoryName(name string) string {
+	if environment == config.DefaultEnvironment {
+		return name
+	}
+
+	return fmt.Sprintf("nitro-%s-%s", environment, strings.TrimPrefix(name, "nitro-"))
+}
+
+// SiteVolumeName takes the hostname for a site and the name of a volume and
+// returns the name of the docker volume for the site (e.g. nitro_demo.nitro_vendor).
+func SiteVolumeName(hostname, name string) string {
+	return fmt.Sprintf("%s_%s_%s", VolumeName(), hostname, name)
+}
+
+// ContainerName takes the hostname for a container and returns the name of
+// the container in the environment. Container names are global in docker, so
+// named environments prefix the name (e.g. client.mysql-8.0-3306.database.nitro).
+func ContainerName(hostname string) string {
+	if environment == config.DefaultEnvironment {
+		return hostname
+	}
+
+	return environment + "." + hostname
+}
+
+// Hostname takes the name of a container, as returned by the docker API, and
+// returns the hostname by removing the leading slash and environment prefix.
+func Hostname(name string) string {
+	name = strings.TrimLeft(name, "/")
+	if environment == config.DefaultEnvironment {
+		return name
+	}
+
+	return strings.TrimPrefix(name, environment+".")
+}
+
+// EndpointsConfig takes the network id and hostname for a container and returns
+// the network settings used to create the container. Containers in a named
+// environment are given the hostname as an alias so they can still be reached
+// using the hostname on the network.
+func EndpointsConfig(networkID, hostname string) map[string]*network.EndpointSettings {
+	settings := &network.EndpointSettings{NetworkID: networkID}
+	if ContainerName(hostname) != hostname {
+		settings.Aliases = []string{hostname}
+	}
+
+	return map[string]*network.EndpointSettings{NetworkName(): settings}
+}
+
+func setNamespace(prefix string) {
+	Nitro = prefix
+	NitroContainer = prefix + ".container"
+	NitroContainerPort = prefix + ".container-port"
+	DatabaseCompatibility = prefix + ".database-compatibility"
+	DatabaseEngine = prefix + ".database-engine"
+	DatabasePort = prefix + ".database-port"
+	DatabaseVersion = prefix + ".database-version"
+	Env = prefix + ".env"
+	Extensions = prefix + ".extensions"
+	ExtensionsBase = prefix + ".extensions-base"
+	Host = prefix + ".host"
+	Path = prefix + ".path"
+	Network = prefix + ".network"
+	Volume = prefix + ".volume"
+	PHPINI = prefix + ".php-ini"
+	Proxy = prefix + ".proxy"
+	ProxyVersion = prefix + ".proxy-version"
+	Type = prefix + ".type"
+	Webroot = prefix + ".webroot"
+}
+
 // ForSite takes a site and returns labels to use on the sites container.
 func ForSite(s config.Site) map[string]string {
 	labels := map[string]string{
@@ -70,6 +211,11 @@ func ForSite(s config.Site) map[string]string {
 		labels[Extensions] = strings.Join(s.Extensions, ",")
 	}
 
+	// if there are php.ini directives, add the file contents
+	if ini := s.PHPINI(); ini != "" {
+		labels[PHPINI] = ini
+	}
+
 	return labels
 }
 
@@ -0,0 +1,83 @@
+package containerlabels
+
+import (
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+)
+
+func TestUseEnvironment(t *testing.T) {
+	tests := []struct {
+		name          string
+		environment   string
+		wantNitro     string
+		wantHost      string
+		wantNetwork   string
+		wantProxy     string
+		wantVolume    string
+		wantContainer string
+		wantRepo      string
+	}{
+		{
+			name:          "default environment keeps the existing names",
+			environment:   "",
+			wantNitro:     "com.craftcms.nitro",
+			wantHost:      "com.craftcms.nitro.host",
+			wantNetwork:   "nitro-network",
+			wantProxy:     "nitro-proxy",
+			wantVolume:    "nitro",
+			wantContainer: "demo.nitro",
+			wantRepo:      "nitro-extensions",
+		},
+		{
+			name:          "named environments are isolated",
+			environment:   "client",
+			wantNitro:     "com.craftcms.nitro-client",
+			wantHost:      "com.craftcms.nitro-client.host",
+			wantNetwork:   "nitro-client-network",
+			wantProxy:     "nitro-client-proxy",
+			wantVolume:    "nitro-client",
+			wantContainer: "client.demo.nitro",
+			wantRepo:      "nitro-client-extensions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer UseEnvironment(config.DefaultEnvironment)
+
+			UseEnvironment(tt.environment)
+
+			if Nitro != tt.wantNitro {
+				t.Errorf("expected the nitro label to be %q, got %q", tt.wantNitro, Nitro)
+			}
+
+			if Host != tt.wantHost {
+				t.Errorf("expected the host label to be %q, got %q", tt.wantHost, Host)
+			}
+
+			if got := NetworkName(); got != tt.wantNetwork {
+				t.Errorf("NetworkName() = %q, want %q", got, tt.wantNetwork)
+			}
+
+			if got := ProxyName(); got != tt.wantProxy {
+				t.Errorf("ProxyName() = %q, want %q", got, tt.wantProxy)
+			}
+
+			if got := VolumeName(); got != tt.wantVolume {
+				t.Errorf("VolumeName() = %q, want %q", got, tt.wantVolume)
+			}
+
+			if got := ContainerName("demo.nitro"); got != tt.wantContainer {
+				t.Errorf("ContainerName() = %q, want %q", got, tt.wantContainer)
+			}
+
+			if got := RepositoryName("nitro-extensions"); got != tt.wantRepo {
+				t.Errorf("RepositoryName() = %q, want %q", got, tt.wantRepo)
+			}
+
+			if got := Hostname("/" + tt.wantContainer); got != "demo.nitro" {
+				t.Errorf("Hostname() = %q, want %q", got, "demo.nitro")
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+var (
+	MySQLExportCommand    = "mysqldump"
+	PostgresExportCommand = "pg_dump"
+)
+
+// ExportOptions contain the information needed to export a database.
+type ExportOptions struct {
+	Engine       string
+	Version      string
+	Hostname     string
+	Port         string
+	DatabaseName string
+}
+
+// Export runs the export tool (e.g. mysqldump or pg_dump) for the database
+// and writes the dump to w compressed with gzip. The export tool is stopped
+// when the context is cancelled.
+func Export(ctx context.Context, opts *ExportOptions, w io.Writer, find func(engine, version string) (string, error)) error {
+	// ensure there are options
+	if opts == nil {
+		return fmt.Errorf("no options were provided")
+	}
+
+	switch {
+	case opts.Engine == "":
+		return fmt.Errorf("export options is missing the engine")
+	case opts.Port == "":
+		return fmt.Errorf("export options is missing the port")
+	case opts.Hostname == "":
+		return fmt.Errorf("export options is missing the hostname")
+	case opts.DatabaseName == "":
+		return fmt.Errorf("export options is missing the database name")
+	}
+
+	// find the export tool
+	tool, err := find(opts.Engine, opts.Version)
+	if err != nil {
+		return err
+	}
+
+	var commands []string
+	switch opts.Engine {
+	case "postgres":
+		commands = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", opts.DatabaseName}
+	default:
+		commands = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, opts.DatabaseName}
+	}
+
+	gz := gzip.NewWriter(w)
+
+	if err := run(ctx, tool, commands, nil, gz, nil); err != nil {
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("unable to compress the export, %w", err)
+	}
+
+	return nil
+}
+
+// DefaultExportToolFinder is used to find the executable path to the export
+// tool such as mysqldump or pg_dump. It is provided to the Export func and
+// returns an error if the command is not found.
+func DefaultExportToolFinder(engine, version string) (string, error) {
+	var command string
+	switch engine {
+	case "postgres":
+		command = PostgresExportCommand
+	case "mysql":
+		command = MySQLExportCommand
+	default:
+		return "", fmt.Errorf("unknown engine %q and version %q options provided", engine, version)
+	}
+
+	t, err := exec.LookPath(command)
+	if err != nil {
+		return "", fmt.Errorf("unable to find the `%q` export tool", command)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *ExportOptions
+		script  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "the dump is compressed with gzip",
+			opts:   &ExportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306", DatabaseName: "nitro"},
+			script: "echo \"-- $4\"\necho 'CREATE TABLE users (id int);'",
+			want:   "-- nitro\nCREATE TABLE users (id int);\n",
+		},
+		{
+			name:    "errors include the last line written to stderr",
+			opts:    &ExportOptions{Engine: "postgres", Hostname: "postgres-13-5432.database.nitro", Port: "5432", DatabaseName: "missing"},
+			script:  "echo 'pg_dump: error: database \"missing\" does not exist' >&2\nexit 1",
+			wantErr: "Exit Status: 1, pg_dump: error: database \"missing\" does not exist",
+		},
+		{
+			name:    "missing database names return an error",
+			opts:    &ExportOptions{Engine: "mysql", Hostname: "mysql-8.0-3306.database.nitro", Port: "3306"},
+			wantErr: "export options is missing the database name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := filepath.Join(t.TempDir(), "dump")
+			if err := ioutil.WriteFile(tool, []byte("#!/bin/sh\n"+tt.script+"\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			buf := &bytes.Buffer{}
+			err := Export(context.Background(), tt.opts, buf, func(engine, version string) (string, error) { return tool, nil })
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Export() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+
+			r, err := gzip.NewReader(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Export() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package database
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/craftcms/nitro/pkg/pathexists"
@@ -31,6 +37,19 @@ type ImportOptions struct {
 	Port            string
 	DatabaseName    string
 	File            string
+
+	// Progress is called while the file is read by the import tool and for
+	// each line the import tool writes to stderr.
+	Progress func(p Progress)
+}
+
+// Progress is the progress of an import, Stderr is only set for a line of
+// output from the import tool (e.g. mysql or psql).
+type Progress struct {
+	Bytes      int64
+	Total      int64
+	Statements int64
+	Stderr     string
 }
 
 type importer struct{}
@@ -64,48 +83,82 @@ func (importer *importer) Import(opts *ImportOptions, find func(engine, version
 		return err
 	}
 
-	// generate the commands to execute
+	// generate the commands to execute, the file is sent to the import tool
+	// using stdin so we can track how much has been imported
 	var createCommand, importCommand []string
 	switch opts.Engine {
 	case "postgres":
 		createCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", fmt.Sprintf(`-c CREATE DATABASE %s;`, opts.DatabaseName)}
-		importCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", opts.DatabaseName, "--file=" + opts.File}
+		importCommand = []string{fmt.Sprintf("--host=%s", opts.Hostname), "--port=" + opts.Port, "--username=nitro", opts.DatabaseName}
 	default:
-		createCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "-pnitro", fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, opts.DatabaseName)}
+		createCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), fmt.Sprintf(`-e CREATE DATABASE IF NOT EXISTS %s;`, opts.DatabaseName)}
 		// https://dev.mysql.com/doc/refman/8.0/en/mysql-command-options.html
-		importCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), "-pnitro", opts.DatabaseName, fmt.Sprintf(`-e source %s`, opts.File)}
+		importCommand = []string{"--user=nitro", fmt.Sprintf("--host=%s", opts.Hostname), opts.DatabaseName}
+	}
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(p Progress) {}
 	}
 
 	// if there is a create command, lets create the database
 	if createCommand != nil {
-		if err := importer.exec(tool, createCommand); err != nil {
-			// do not exit on error with the crate command - the error could be "Database already exists"
-			fmt.Println(err)
-		}
+		// do not exit on error with the create command - the error could be "Database already exists"
+		run(context.Background(), tool, createCommand, nil, ioutil.Discard, nil)
+	}
+
+	f, err := os.Open(opts.File)
+	if err != nil {
+		return fmt.Errorf("unable to open the file %s, %w", opts.File, err)
 	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to get the size of %s, %w", opts.File, err)
+	}
+
+	r := &counter{r: f, total: info.Size(), report: progress}
 
 	// import the database
-	if err := importer.exec(tool, importCommand); err != nil {
+	if err := run(context.Background(), tool, importCommand, r, ioutil.Discard, r.stderr); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (importer *importer) exec(tool string, commands []string) error {
-	c := exec.Command(tool, commands...)
+// run runs the import or export tool with the commands, stdin is optional and
+// each line written to stderr is passed to the stderr func. When the tool exits
+// with an error, the last line written to stderr is included in the error.
+func run(ctx context.Context, tool string, commands []string, stdin io.Reader, stdout io.Writer, stderr func(line string)) error {
+	c := exec.CommandContext(ctx, tool, commands...)
+
+	// use the env var for the mysql password so there is no warning about passwords on the command line
+	c.Env = append(os.Environ(), "MYSQL_PWD=nitro")
 
-	c.Stderr = ioutil.Discard
-	c.Stdout = ioutil.Discard
+	lw := &lineWriter{fn: stderr}
+
+	c.Stdin = stdin
+	c.Stderr = lw
+	c.Stdout = stdout
 
 	if err := c.Start(); err != nil {
 		return fmt.Errorf("unable to start the command: %w", err)
 	}
 
-	if err := c.Wait(); err != nil {
+	err := c.Wait()
+
+	lw.flush()
+
+	if err != nil {
 		if exiterr, ok := err.(*exec.ExitError); ok {
 			// The program has exited with an exit code != 0
 			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
+				if lw.last != "" {
+					return fmt.Errorf("Exit Status: %d, %s", status.ExitStatus(), lw.last)
+				}
+
 				return fmt.Errorf("Exit Status: %d", status.ExitStatus())
 			}
 		} else {
@@ -116,6 +169,99 @@ func (importer *importer) exec(tool string, commands []string) error {
 	return nil
 }
 
+// counter reports the bytes and statements read by the import tool, a
+// statement is counted for each line that ends with a semicolon. The
+// progress is reported from the goroutines for stdin and stderr, so the
+// counts are guarded by the mutex.
+type counter struct {
+	mu         sync.Mutex
+	r          io.Reader
+	bytes      int64
+	total      int64
+	statements int64
+	prev       byte
+	report     func(p Progress)
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, b := range p[:n] {
+		switch b {
+		case '\r':
+			continue
+		case '\n':
+			if c.prev == ';' {
+				c.statements++
+			}
+		}
+
+		c.prev = b
+	}
+
+	c.bytes += int64(n)
+
+	if n > 0 {
+		c.report(Progress{Bytes: c.bytes, Total: c.total, Statements: c.statements})
+	}
+
+	return n, err
+}
+
+// stderr reports a line written to stderr by the import tool.
+func (c *counter) stderr(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.report(Progress{Bytes: c.bytes, Total: c.total, Statements: c.statements, Stderr: line})
+}
+
+// lineWriter calls fn for each line written and keeps the last line.
+type lineWriter struct {
+	fn   func(line string)
+	buf  bytes.Buffer
+	last string
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(l.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		l.line(string(l.buf.Next(i + 1)))
+	}
+
+	return len(p), nil
+}
+
+// flush sends the last line when the output does not end with a new line.
+func (l *lineWriter) flush() {
+	if l.buf.Len() > 0 {
+		l.line(l.buf.String())
+		l.buf.Reset()
+	}
+}
+
+func (l *lineWriter) line(s string) {
+	s = strings.TrimRight(s, "\r\n")
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+
+	l.last = s
+
+	if l.fn != nil {
+		l.fn(s)
+	}
+}
+
 // Validate takes import options and returns an
 // error if the options are missing details
 // we need to run the import.
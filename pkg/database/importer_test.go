@@ -1,7 +1,10 @@
 package database
 
 import (
+	"io/ioutil"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -93,3 +96,74 @@ func TestDefaultImportToolFinder(t *testing.T) {
 		})
 	}
 }
+
+func TestImporter_Import(t *testing.T) {
+	dir := t.TempDir()
+
+	backup := filepath.Join(dir, "backup.sql")
+	content := "CREATE TABLE users (id int);\r\nINSERT INTO users VALUES (1),\n(2);\nSELECT 1;"
+	if err := ioutil.WriteFile(backup, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		wantErr    string
+		wantStderr []string
+	}{
+		{
+			name:   "reports the bytes and statements read by the tool",
+			script: "cat > /dev/null",
+		},
+		{
+			name:       "errors include the last line written to stderr",
+			script:     "cat > /dev/null\necho 'ERROR 1064 (42000) at line 2: syntax error' >&2\nexit 1",
+			wantErr:    "Exit Status: 1, ERROR 1064 (42000) at line 2: syntax error",
+			wantStderr: []string{"ERROR 1064 (42000) at line 2: syntax error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := filepath.Join(t.TempDir(), "mysql")
+			if err := ioutil.WriteFile(tool, []byte("#!/bin/sh\n"+tt.script+"\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			var last Progress
+			var stderr []string
+			opts := &ImportOptions{
+				Engine:       "mysql",
+				Hostname:     "mysql-8.0-3306.database.nitro",
+				Port:         "3306",
+				DatabaseName: "nitro",
+				File:         backup,
+				Progress: func(p Progress) {
+					if p.Stderr != "" {
+						stderr = append(stderr, p.Stderr)
+						return
+					}
+
+					last = p
+				},
+			}
+
+			err := NewImporter().Import(opts, func(engine, version string) (string, error) { return tool, nil })
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Import() unexpected error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %q", err, tt.wantErr)
+			}
+
+			want := Progress{Bytes: int64(len(content)), Total: int64(len(content)), Statements: 2}
+			if last != want {
+				t.Errorf("Import() progress = %+v, want %+v", last, want)
+			}
+
+			if strings.Join(stderr, "\n") != strings.Join(tt.wantStderr, "\n") {
+				t.Errorf("Import() stderr = %v, want %v", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/containerexec"
+	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Run runs the hooks for the event in order and stops at the first hook that fails. Hooks
+// run in the container unless they run on the host, which use dir as the working directory.
+// The output of each hook is shown line by line as it runs.
+func Run(ctx context.Context, docker client.ContainerAPIClient, containerID, dir, event string, hooks []config.Hook, output terminal.Outputer) error {
+	for _, h := range hooks {
+		output.Info("Running", event, "hook", h.String()+"…")
+
+		w := &lines{output: output}
+		err := run(ctx, docker, containerID, dir, h, w)
+		w.flush()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Site runs the sites hooks for the event, hooks run in the sites container or on the host
+// from the sites path. When the container is not running (e.g. pre_destroy for a stopped
+// site) only the hooks for the host are run.
+func Site(ctx context.Context, docker client.ContainerAPIClient, home string, site config.Site, event string, output terminal.Outputer) error {
+	list := site.Hooks.For(event)
+	if len(list) == 0 {
+		return nil
+	}
+
+	dir, err := site.GetAbsPath(home)
+	if err != nil {
+		return err
+	}
+
+	id, running, err := container(ctx, docker, site.Hostname)
+	if err != nil {
+		return err
+	}
+
+	if !running {
+		var host []config.Hook
+		for _, h := range list {
+			if h.Host {
+				host = append(host, h)
+			}
+		}
+
+		if len(host) < len(list) {
+			output.Info("Skipping the", event, "hooks for", site.Hostname, "that run in the container, the container is not running")
+		}
+
+		list = host
+	}
+
+	if err := Run(ctx, docker, id, dir, event, list, output); err != nil {
+		return fmt.Errorf("unable to run the %s hooks for %s, %w", event, site.Hostname, err)
+	}
+
+	return nil
+}
+
+// Apply runs the pre or post apply hooks. Hooks run on the host from the current
+// directory, unless they set a site to run in the sites container.
+func Apply(ctx context.Context, docker client.ContainerAPIClient, cfg *config.Config, event string, output terminal.Outputer) error {
+	for _, h := range cfg.Hooks.For(event) {
+		var id string
+		if h.Site != "" {
+			c, running, err := container(ctx, docker, h.Site)
+			if err != nil {
+				return err
+			}
+
+			if !running {
+				return fmt.Errorf("unable to run the %s hook %q, the container for %s is not running", event, h.Run, h.Site)
+			}
+
+			id = c
+		}
+
+		if err := Run(ctx, docker, id, "", event, []config.Hook{h}, output); err != nil {
+			return fmt.Errorf("unable to run the %s hooks, %w", event, err)
+		}
+	}
+
+	return nil
+}
+
+// container returns the ID of the sites container and if the container is running.
+func container(ctx context.Context, docker client.ContainerAPIClient, hostname string) (string, bool, error) {
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Host+"="+hostname)
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return "", false, fmt.Errorf("unable to get a list of the containers, %w", err)
+	}
+
+	if len(containers) == 0 {
+		return "", false, nil
+	}
+
+	return containers[0].ID, containers[0].State == "running", nil
+}
+
+func run(ctx context.Context, docker client.ContainerAPIClient, containerID, dir string, hook config.Hook, w io.Writer) error {
+	if hook.Host {
+		c := exec.CommandContext(ctx, shell()[0], append(shell()[1:], hook.Run)...)
+		c.Dir = dir
+		c.Stdout = w
+		c.Stderr = w
+
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("the hook %q failed, %w", hook.Run, err)
+		}
+
+		return nil
+	}
+
+	if containerID == "" {
+		return fmt.Errorf("unable to find a container to run the hook %q", hook.Run)
+	}
+
+	code, err := containerexec.Run(ctx, docker, containerID, types.ExecConfig{
+		Cmd:        []string{"sh", "-c", hook.Run},
+		WorkingDir: "/app",
+	}, w)
+	if err != nil {
+		return fmt.Errorf("unable to run the hook %q, %w", hook.Run, err)
+	}
+
+	if code != 0 {
+		return fmt.Errorf("the hook %q failed with exit code %d", hook.Run, code)
+	}
+
+	return nil
+}
+
+// shell returns the command used to run hooks on the host.
+func shell() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C"}
+	}
+
+	return []string{"sh", "-c"}
+}
+
+// lines writes the output of a hook as info messages, one for each line.
+type lines struct {
+	output terminal.Outputer
+	buf    bytes.Buffer
+}
+
+func (l *lines) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(l.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		l.info(string(l.buf.Next(i + 1)))
+	}
+
+	return len(p), nil
+}
+
+// flush writes the last line when the output does not end with a new line.
+func (l *lines) flush() {
+	if l.buf.Len() > 0 {
+		l.info(l.buf.String())
+		l.buf.Reset()
+	}
+}
+
+func (l *lines) info(line string) {
+	l.output.Info("  " + strings.TrimRight(line, "\r\n"))
+}
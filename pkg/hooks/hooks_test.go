@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+type spyOutputer struct {
+	infos []string
+}
+
+func (spy *spyOutputer) Ask(message, fallback, sep string, validator terminal.Validator) (string, error) {
+	return fallback, nil
+}
+
+func (spy *spyOutputer) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return fallback, nil
+}
+
+func (spy *spyOutputer) Info(s ...string) {
+	spy.infos = append(spy.infos, strings.Join(s, " "))
+}
+
+func (spy *spyOutputer) Success(s ...string) {}
+
+func (spy *spyOutputer) Pending(s ...string) {}
+
+func (spy *spyOutputer) Done() {}
+
+func (spy *spyOutputer) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return 0, nil
+}
+
+func (spy *spyOutputer) Warning() {}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks use sh")
+	}
+
+	dir := t.TempDir()
+
+	output := &spyOutputer{}
+	hooks := []config.Hook{
+		{Run: "basename \"$(pwd)\"", Host: true},
+		{Run: "printf 'one\\ntwo'", Host: true},
+	}
+
+	if err := Run(context.Background(), nil, "", dir, config.HookPostCreate, hooks, output); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Running post_create hook basename \"$(pwd)\" (host)…",
+		"  " + filepath.Base(dir),
+		"Running post_create hook printf 'one\\ntwo' (host)…",
+		"  one",
+		"  two",
+	}
+
+	if !reflect.DeepEqual(output.infos, want) {
+		t.Errorf("expected the output:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(output.infos, "\n"))
+	}
+}
+
+func TestRun_Failure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hooks use sh")
+	}
+
+	output := &spyOutputer{}
+	hooks := []config.Hook{
+		{Run: "echo failed && exit 3", Host: true},
+		{Run: "echo skipped", Host: true},
+	}
+
+	err := Run(context.Background(), nil, "", t.TempDir(), config.HookPreApply, hooks, output)
+	if err == nil {
+		t.Fatal("expected an error when the hook fails")
+	}
+
+	if want := fmt.Sprintf("the hook %q failed, exit status 3", "echo failed && exit 3"); err.Error() != want {
+		t.Errorf("expected the error %q, got %q", want, err.Error())
+	}
+
+	// hooks after the failure are not run
+	if got := output.infos[len(output.infos)-1]; got != "  failed" {
+		t.Errorf("expected the output of the failed hook, got %q", got)
+	}
+}
+
+func TestRun_NoContainer(t *testing.T) {
+	err := Run(context.Background(), nil, "", "", config.HookPostStart, []config.Hook{{Run: "composer install"}}, &spyOutputer{})
+	if err == nil || !strings.Contains(err.Error(), "unable to find a container") {
+		t.Errorf("expected an error without a container, got %v", err)
+	}
+}
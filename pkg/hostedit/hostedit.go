@@ -4,11 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
-)
 
-const (
-	startText = "# <nitro>"
-	endText   = "# </nitro>"
+	"github.com/craftcms/nitro/pkg/config"
 )
 
 var ErrNotNitroEntries = fmt.Errorf("there are no nitro entries to remove from the hosts file")
@@ -24,6 +21,8 @@ func Update(file, addr string, hosts ...string) (content string, err error) {
 	// split the file into multiple lines
 	lines := strings.Split(string(f), "\n")
 
+	startText, endText := markers()
+
 	// the index represents where the content (addr and hosts) should be placed
 	// which is in between the start and end text comment
 	var index int
@@ -44,9 +43,7 @@ func Update(file, addr string, hosts ...string) (content string, err error) {
 	switch index {
 	// if there is not a comment section, we need to create one
 	case 0:
-		lines = append(lines, startText)
-		lines = append(lines, fmt.Sprintf("%s\t%s", addr, strings.Join(hosts, " ")))
-		lines = append(lines, endText+"\n")
+		lines = append(lines, Section(addr, hosts...)+"\n")
 	default:
 		// replace the line between the start and end text with the contents of the address and hosts
 		lines[index] = fmt.Sprintf("%s\t%s", addr, strings.Join(hosts, " "))
@@ -55,6 +52,14 @@ func Update(file, addr string, hosts ...string) (content string, err error) {
 	return strings.Join(lines, "\n"), nil
 }
 
+// Section returns the section of the hosts file for the selected environment with the
+// addr and hosts, for users that need to add it to the hosts file themselves.
+func Section(addr string, hosts ...string) string {
+	startText, endText := markers()
+
+	return fmt.Sprintf("%s\n%s\t%s\n%s", startText, addr, strings.Join(hosts, " "), endText)
+}
+
 // IsUpdated is used to check if an update will make any changes
 // to the hosts file and return true if there is nothing to change
 func IsUpdated(file, addr string, hosts ...string) (updated bool, err error) {
@@ -75,6 +80,30 @@ func IsUpdated(file, addr string, hosts ...string) (updated bool, err error) {
 	return string(orig) == isUpdated, nil
 }
 
+// Hosts returns the hosts in the nitro section of the hosts file, if
+// there is no section it returns an empty list.
+func Hosts(file string) ([]string, error) {
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(f), "\n")
+
+	start, middle, end := indexes(f)
+	if start == 0 && middle == 0 && end == 0 {
+		return nil, nil
+	}
+
+	// the first field is the address
+	fields := strings.Fields(lines[middle])
+	if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+		return nil, nil
+	}
+
+	return fields[1:], nil
+}
+
 // Remove is responsible for removing all of the hosts entries
 // for the nitro config from the hosts file.
 func Remove(file string) (content string, err error) {
@@ -113,6 +142,8 @@ func indexes(content []byte) (start, middle, end int) {
 	// split the file into multiple lines
 	lines := strings.Split(string(content), "\n")
 
+	startText, endText := markers()
+
 	// the index represents where the content (addr and hosts) should be placed
 	// which is in between the start and end text comment
 	var s, m, e int
@@ -134,3 +165,15 @@ func indexes(content []byte) (start, middle, end int) {
 
 	return s, m, e
 }
+
+// markers returns the comments around the section for the selected environment, so
+// each environment has its own section (e.g. # <nitro-client>). The default environment
+// keeps the # <nitro> section.
+func markers() (start, end string) {
+	name := "nitro"
+	if config.Environment != config.DefaultEnvironment {
+		name = "nitro-" + config.Environment
+	}
+
+	return "# <" + name + ">", "# </" + name + ">"
+}
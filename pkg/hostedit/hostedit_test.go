@@ -2,7 +2,10 @@ package hostedit
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/craftcms/nitro/pkg/config"
 )
 
 func TestUpdate(t *testing.T) {
@@ -12,10 +15,11 @@ func TestUpdate(t *testing.T) {
 		hosts []string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    string
-		wantErr bool
+		name        string
+		args        args
+		environment string
+		want        string
+		wantErr     bool
 	}{
 		{
 			name: "can append to the file when no section is found",
@@ -63,6 +67,35 @@ func TestUpdate(t *testing.T) {
 # To allow the same kube context to work on the host and the container:
 127.0.0.1        kubernetes.docker.internal
 # End of section
+`,
+		},
+		{
+			name:        "other environments append their own section",
+			args:        args{file: "testdata/up-to-date.txt", addr: "127.0.0.1", hosts: []string{"four"}},
+			environment: "client",
+			want: `##
+# Host Database
+#
+# localhost is used to configure the loopback interface
+# when the system is booting.  Do not change this entry.
+##
+127.0.0.1        localhost
+255.255.255.255  broadcasthost
+::1              localhost
+
+# <nitro>
+127.0.0.1	one two three
+# </nitro>
+
+127.0.0.1        kubernetes.docker.internal
+# Added by Docker Desktop
+# To allow the same kube context to work on the host and the container:
+127.0.0.1        kubernetes.docker.internal
+# End of section
+
+# <nitro-client>
+127.0.0.1	four
+# </nitro-client>
 `,
 		},
 		{
@@ -74,6 +107,13 @@ func TestUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			if tt.environment != "" {
+				if err := config.SetEnvironment(tt.environment); err != nil {
+					t.Fatal(err)
+				}
+				defer config.SetEnvironment("")
+			}
+
 			got, err := Update(tt.args.file, tt.args.addr, tt.args.hosts...)
 
 			if (err != nil) != tt.wantErr {
@@ -246,3 +286,43 @@ func TestRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "returns the hosts in the nitro section",
+			file: filepath.Join("testdata", "up-to-date.txt"),
+			want: []string{"one", "two", "three"},
+		},
+		{
+			name: "empty sections return no hosts",
+			file: filepath.Join("testdata", "has-section.txt"),
+		},
+		{
+			name: "files without a section return no hosts",
+			file: filepath.Join("testdata", "no-section.txt"),
+		},
+		{
+			name:    "no file returns error",
+			file:    filepath.Join("testdata", "empty"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Hosts(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Hosts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package phpextensions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/phpversions"
+)
+
+const (
+	// MethodCore is used for extensions that are bundled with PHP and installed with docker-php-ext-install
+	MethodCore = "core"
+
+	// MethodPECL is used for extensions that are installed with pecl and enabled with docker-php-ext-enable
+	MethodPECL = "pecl"
+)
+
+var (
+	// ErrUnknownExtension is returned when an extension is not in the catalog
+	ErrUnknownExtension = fmt.Errorf("unknown extension")
+
+	// all is used for extensions that support every PHP version
+	all = phpversions.Versions
+)
+
+// Extension describes how to install a PHP extension in a sites image. An
+// extension can have more than one entry in the catalog when a PECL package
+// needs to be pinned for older PHP versions.
+type Extension struct {
+	// Name is the name of the extension (e.g. redis)
+	Name string
+
+	// Method is how the extension is installed, core or pecl
+	Method string
+
+	// Package is the PECL package to install, which can include a version (e.g. redis-5.3.7)
+	Package string
+
+	// SystemPackages are the apk packages the extension requires
+	SystemPackages []string
+
+	// Versions are the PHP versions the extension supports
+	Versions []string
+}
+
+// Catalog is the list of extensions that can be installed for a site.
+var Catalog = []Extension{
+	// core extensions
+	{Name: "bcmath", Method: MethodCore, Versions: all},
+	{Name: "bz2", Method: MethodCore, SystemPackages: []string{"bzip2-dev"}, Versions: all},
+	{Name: "calendar", Method: MethodCore, Versions: all},
+	{Name: "dba", Method: MethodCore, Versions: all},
+	{Name: "enchant", Method: MethodCore, SystemPackages: []string{"enchant2-dev"}, Versions: all},
+	{Name: "exif", Method: MethodCore, Versions: all},
+	{Name: "gettext", Method: MethodCore, SystemPackages: []string{"gettext-dev"}, Versions: all},
+	{Name: "gmp", Method: MethodCore, SystemPackages: []string{"gmp-dev"}, Versions: all},
+	{Name: "imap", Method: MethodCore, SystemPackages: []string{"imap-dev", "openssl-dev"}, Versions: all},
+	{Name: "ldap", Method: MethodCore, SystemPackages: []string{"openldap-dev"}, Versions: all},
+	{Name: "mysqli", Method: MethodCore, Versions: all},
+	{Name: "pcntl", Method: MethodCore, Versions: all},
+	{Name: "pdo_dblib", Method: MethodCore, SystemPackages: []string{"freetds-dev"}, Versions: all},
+	{Name: "pdo_sqlite", Method: MethodCore, SystemPackages: []string{"sqlite-dev"}, Versions: all},
+	{Name: "recode", Method: MethodCore, SystemPackages: []string{"recode-dev"}, Versions: []string{"7.3", "7.2", "7.1", "7.0"}},
+	{Name: "shmop", Method: MethodCore, Versions: all},
+	{Name: "snmp", Method: MethodCore, SystemPackages: []string{"net-snmp-dev"}, Versions: all},
+	{Name: "sockets", Method: MethodCore, SystemPackages: []string{"linux-headers"}, Versions: all},
+	{Name: "sysvmsg", Method: MethodCore, Versions: all},
+	{Name: "sysvsem", Method: MethodCore, Versions: all},
+	{Name: "sysvshm", Method: MethodCore, Versions: all},
+	{Name: "tidy", Method: MethodCore, SystemPackages: []string{"tidyhtml-dev"}, Versions: all},
+	{Name: "wddx", Method: MethodCore, SystemPackages: []string{"libxml2-dev"}, Versions: []string{"7.3", "7.2", "7.1", "7.0"}},
+	{Name: "xmlrpc", Method: MethodCore, SystemPackages: []string{"libxml2-dev"}, Versions: []string{"7.4", "7.3", "7.2", "7.1", "7.0"}},
+	{Name: "xsl", Method: MethodCore, SystemPackages: []string{"libxslt-dev"}, Versions: all},
+	{Name: "zend_test", Method: MethodCore, Versions: all},
+
+	// pecl extensions
+	{Name: "apcu", Method: MethodPECL, Package: "apcu-5.1.21", Versions: all},
+	{Name: "igbinary", Method: MethodPECL, Package: "igbinary-3.2.7", Versions: all},
+	{Name: "imagick", Method: MethodPECL, Package: "imagick-3.7.0", SystemPackages: []string{"imagemagick-dev"}, Versions: all},
+	{Name: "memcached", Method: MethodPECL, Package: "memcached-3.2.0", SystemPackages: []string{"libmemcached-dev", "zlib-dev"}, Versions: all},
+	{Name: "mongodb", Method: MethodPECL, Package: "mongodb-1.15.0", SystemPackages: []string{"openssl-dev"}, Versions: []string{"8.1", "8.0", "7.4", "7.3", "7.2"}},
+	{Name: "mongodb", Method: MethodPECL, Package: "mongodb-1.9.2", SystemPackages: []string{"openssl-dev"}, Versions: []string{"7.1", "7.0"}},
+	{Name: "msgpack", Method: MethodPECL, Package: "msgpack-2.1.2", Versions: all},
+	{Name: "pcov", Method: MethodPECL, Package: "pcov-1.0.11", Versions: all},
+	{Name: "redis", Method: MethodPECL, Package: "redis-5.3.7", Versions: all},
+	{Name: "uuid", Method: MethodPECL, Package: "uuid-1.2.0", SystemPackages: []string{"util-linux-dev"}, Versions: all},
+	{Name: "yaml", Method: MethodPECL, Package: "yaml-2.2.2", SystemPackages: []string{"yaml-dev"}, Versions: []string{"8.1", "8.0", "7.4", "7.3", "7.2", "7.1"}},
+	{Name: "yaml", Method: MethodPECL, Package: "yaml-2.0.4", SystemPackages: []string{"yaml-dev"}, Versions: []string{"7.0"}},
+}
+
+// Bundled are the extensions already installed in the base images, they
+// do not need to be installed for a site.
+var Bundled = []string{
+	"curl", "dom", "gd", "iconv", "intl", "json", "mbstring", "opcache",
+	"pdo", "pdo_mysql", "pdo_pgsql", "pgsql", "soap", "xml", "zip",
+}
+
+// Find takes the name of an extension and a PHP version and returns the
+// catalog entry to install the extension for the version. It returns an
+// error if the extension is unknown or does not support the version.
+func Find(name, version string) (*Extension, error) {
+	known := false
+	for _, e := range Catalog {
+		if e.Name != name {
+			continue
+		}
+
+		known = true
+
+		if e.Supports(version) {
+			e := e
+			return &e, nil
+		}
+	}
+
+	if !known {
+		return nil, fmt.Errorf("%w %s", ErrUnknownExtension, name)
+	}
+
+	return nil, fmt.Errorf("the extension %s is not available for PHP %s", name, version)
+}
+
+// Supports returns true if the extension can be installed for the PHP version.
+func (e *Extension) Supports(version string) bool {
+	for _, v := range e.Versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Available returns the sorted names of the extensions that can be installed
+// for the PHP version.
+func Available(version string) []string {
+	seen := make(map[string]bool)
+
+	var names []string
+	for _, e := range Catalog {
+		if !e.Supports(version) || seen[e.Name] {
+			continue
+		}
+
+		seen[e.Name] = true
+		names = append(names, e.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Resolve takes a PHP version and a list of extensions and returns the
+// extensions to install. Bundled extensions are skipped, so configs from
+// older versions can still be applied. It returns an error for the first
+// extension that is not in the catalog or not available for the version.
+func Resolve(version string, extensions []string) ([]string, error) {
+	var install []string
+	for _, name := range extensions {
+		if isBundled(name) {
+			continue
+		}
+
+		if _, err := Find(name, version); err != nil {
+			return nil, err
+		}
+
+		install = append(install, name)
+	}
+
+	return install, nil
+}
+
+// Validate takes a PHP version and a list of extensions and returns an error
+// for the first extension that is not in the catalog or not available for the
+// version. Bundled extensions are skipped.
+func Validate(version string, extensions []string) error {
+	_, err := Resolve(version, extensions)
+
+	return err
+}
+
+// isBundled returns true if the extension is installed in the base images.
+func isBundled(name string) bool {
+	for _, b := range Bundled {
+		if b == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Install takes a PHP version and a list of extensions and returns the shell
+// commands to install them. Bundled extensions are skipped. The
+// system packages are kept so the shared libraries are available, the build
+// dependencies are removed after the extensions are compiled.
+func Install(version string, extensions []string) ([]string, error) {
+	extensions, err := Resolve(version, extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+
+	var packages, core, pecl, enable []string
+	seen := make(map[string]bool)
+
+	for _, name := range extensions {
+		e, err := Find(name, version)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range e.SystemPackages {
+			if !seen[p] {
+				seen[p] = true
+				packages = append(packages, p)
+			}
+		}
+
+		switch e.Method {
+		case MethodPECL:
+			pecl = append(pecl, e.Package)
+			enable = append(enable, e.Name)
+		default:
+			core = append(core, e.Name)
+		}
+	}
+
+	var commands []string
+	if len(packages) > 0 {
+		sort.Strings(packages)
+		commands = append(commands, "apk add --no-cache "+strings.Join(packages, " "))
+	}
+
+	build := []string{"apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS"}
+
+	if len(core) > 0 {
+		build = append(build, "docker-php-ext-install "+strings.Join(core, " "))
+	}
+
+	if len(pecl) > 0 {
+		build = append(build, "pecl install "+strings.Join(pecl, " "), "docker-php-ext-enable "+strings.Join(enable, " "))
+	}
+
+	build = append(build, "apk del .nitro-build-deps")
+
+	return append(commands, strings.Join(build, " && ")), nil
+}
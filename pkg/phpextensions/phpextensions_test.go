@@ -0,0 +1,202 @@
+package phpextensions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name        string
+		extension   string
+		version     string
+		wantPackage string
+		wantMethod  string
+		wantErr     error
+	}{
+		{
+			name:       "core extensions are found",
+			extension:  "bcmath",
+			version:    "7.4",
+			wantMethod: MethodCore,
+		},
+		{
+			name:        "pecl extensions are found",
+			extension:   "redis",
+			version:     "8.0",
+			wantPackage: "redis-5.3.7",
+			wantMethod:  MethodPECL,
+		},
+		{
+			name:        "pecl packages are pinned for older versions",
+			extension:   "mongodb",
+			version:     "7.1",
+			wantPackage: "mongodb-1.9.2",
+			wantMethod:  MethodPECL,
+		},
+		{
+			name:      "unknown extensions return an error",
+			extension: "missing",
+			version:   "7.4",
+			wantErr:   ErrUnknownExtension,
+		},
+		{
+			name:      "extensions not available for the version return an error",
+			extension: "xmlrpc",
+			version:   "8.0",
+			wantErr:   errors.New("the extension xmlrpc is not available for PHP 8.0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(tt.extension, tt.version)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("Find() expected error %v, got nil", tt.wantErr)
+				}
+
+				if !errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error() {
+					t.Errorf("Find() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Find() unexpected error = %v", err)
+			}
+
+			if got.Package != tt.wantPackage {
+				t.Errorf("Find() package = %v, want %v", got.Package, tt.wantPackage)
+			}
+
+			if got.Method != tt.wantMethod {
+				t.Errorf("Find() method = %v, want %v", got.Method, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		extensions []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "core extensions use docker-php-ext-install",
+			version:    "7.4",
+			extensions: []string{"bcmath", "calendar"},
+			want: []string{
+				"apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS && docker-php-ext-install bcmath calendar && apk del .nitro-build-deps",
+			},
+		},
+		{
+			name:       "pecl extensions are installed and enabled with system packages",
+			version:    "8.0",
+			extensions: []string{"imagick", "redis", "gmp"},
+			want: []string{
+				"apk add --no-cache gmp-dev imagemagick-dev",
+				"apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS && docker-php-ext-install gmp && pecl install imagick-3.7.0 redis-5.3.7 && docker-php-ext-enable imagick redis && apk del .nitro-build-deps",
+			},
+		},
+		{
+			name:       "bundled extensions are skipped",
+			version:    "7.4",
+			extensions: []string{"intl", "calendar", "pdo_mysql"},
+			want: []string{
+				"apk add --no-cache --virtual .nitro-build-deps $PHPIZE_DEPS && docker-php-ext-install calendar && apk del .nitro-build-deps",
+			},
+		},
+		{
+			name:       "only bundled extensions return no commands",
+			version:    "7.4",
+			extensions: []string{"gd", "zip"},
+		},
+		{
+			name:       "extensions not available for the version return an error",
+			version:    "8.0",
+			extensions: []string{"imagick", "xmlrpc"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Install(tt.version, tt.extensions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Install() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Install() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		extensions  []string
+		wantInstall []string
+		wantErr     bool
+	}{
+		{
+			name:        "bundled extensions are skipped",
+			version:     "8.0",
+			extensions:  []string{"intl", "gd", "zip", "soap", "opcache", "pdo_mysql", "redis"},
+			wantInstall: []string{"redis"},
+		},
+		{
+			name:       "unknown extensions return an error",
+			version:    "7.4",
+			extensions: []string{"calendar", "redsi"},
+			wantErr:    true,
+		},
+		{
+			name:       "extensions not available for the version return an error",
+			version:    "8.1",
+			extensions: []string{"recode"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			install, err := Resolve(tt.version, tt.extensions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(install, tt.wantInstall) {
+				t.Errorf("Resolve() install = %v, want %v", install, tt.wantInstall)
+			}
+		})
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	for _, name := range Available("8.1") {
+		if name == "xmlrpc" || name == "recode" {
+			t.Errorf("Available() returned %s for PHP 8.1", name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range Available("7.0") {
+		if seen[name] {
+			t.Errorf("Available() returned %s more than once", name)
+		}
+
+		seen[name] = true
+	}
+
+	if !seen["mongodb"] || !seen["recode"] {
+		t.Errorf("Available() expected mongodb and recode for PHP 7.0, got %v", Available("7.0"))
+	}
+}
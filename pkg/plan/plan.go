@@ -0,0 +1,160 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/craftcms/nitro/pkg/terminal"
+)
+
+// Action is the type of change that will be made to a resource.
+type Action string
+
+const (
+	// None is used when a resource is up to date
+	None Action = "none"
+
+	// Create is used when a resource does not exist
+	Create Action = "create"
+
+	// Recreate is used when a container does not match the config and will be removed and created
+	Recreate Action = "recreate"
+
+	// Start is used when a container exists but is not running
+	Start Action = "start"
+
+	// Update is used when a resource is changed in place (e.g. the proxy routes or hosts file)
+	Update Action = "update"
+
+	// Remove is used when a resource is no longer in the config
+	Remove Action = "remove"
+)
+
+// symbols are used to prefix each change when printing a plan
+var symbols = map[Action]string{
+	Create:   "+",
+	Recreate: "~",
+	Start:    ">",
+	Update:   "~",
+	Remove:   "-",
+}
+
+// Change is a single change to a container, service, or file. The ID is the
+// existing container, if there is one, and the details are additional lines
+// shown with the change (e.g. where backups are saved). Differences are the
+// reasons a container is recreated.
+type Change struct {
+	Action      Action       `json:"action"`
+	Kind        string       `json:"kind"`
+	Name        string       `json:"name"`
+	ID          string       `json:"id,omitempty"`
+	Details     []string     `json:"details,omitempty"`
+	Differences []Difference `json:"differences,omitempty"`
+}
+
+// Difference is a single difference between the config and a container (e.g. the
+// image or an environment variable). Sensitive values, like custom environment
+// variables, are left empty and only the field is shown.
+type Difference struct {
+	Field    string `json:"field"`
+	Actual   string `json:"actual,omitempty"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// String returns the difference with the actual and expected values (e.g. image: nginx:7.4-dev → nginx:8.0-dev).
+func (d Difference) String() string {
+	if d.Actual == "" && d.Expected == "" {
+		return d.Field + " changed"
+	}
+
+	return fmt.Sprintf("%s: %s → %s", d.Field, value(d.Actual), value(d.Expected))
+}
+
+// Fields returns the fields of the differences separated by commas.
+func (c Change) Fields() string {
+	var fields []string
+	for _, d := range c.Differences {
+		fields = append(fields, d.Field)
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+// String returns the change as a single line (e.g. + create site demo.nitro).
+func (c Change) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s %s", symbols[c.Action], c.Action, c.Kind, c.Name))
+}
+
+// Plan is a list of changes to apply to the environment.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Add adds a change to the plan, changes with no action are ignored.
+func (p *Plan) Add(c Change) {
+	if c.Action == None || c.Action == "" {
+		return
+	}
+
+	p.Changes = append(p.Changes, c)
+}
+
+// HasChanges returns true if there are changes in the plan.
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Summary returns the number of changes for each action (e.g. 1 to create, 2 to remove).
+func (p *Plan) Summary() string {
+	counts := make(map[Action]int)
+	for _, c := range p.Changes {
+		counts[c.Action]++
+	}
+
+	var actions []string
+	for a := range counts {
+		actions = append(actions, string(a))
+	}
+
+	sort.Strings(actions)
+
+	var summary []string
+	for _, a := range actions {
+		summary = append(summary, fmt.Sprintf("%d to %s", counts[Action(a)], a))
+	}
+
+	return strings.Join(summary, ", ")
+}
+
+// Print shows each change and the details in the plan.
+func (p *Plan) Print(output terminal.Outputer) {
+	if !p.HasChanges() {
+		output.Info("No changes, the environment is up to date 👍")
+		return
+	}
+
+	output.Info("Planned changes:")
+
+	for _, c := range p.Changes {
+		output.Info("  " + c.String())
+
+		for _, d := range c.Differences {
+			output.Info("      " + d.String())
+		}
+
+		for _, d := range c.Details {
+			output.Info("      " + d)
+		}
+	}
+
+	output.Info("Plan:", p.Summary())
+}
+
+func value(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+
+	return v
+}
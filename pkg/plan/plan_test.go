@@ -0,0 +1,83 @@
+package plan
+
+import "testing"
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		changes     []Change
+		wantChanges bool
+		wantSummary string
+	}{
+		{
+			name:    "changes without an action are ignored",
+			changes: []Change{{Action: None, Kind: "site", Name: "demo.nitro"}, {Kind: "site", Name: "other.nitro"}},
+		},
+		{
+			name: "changes are counted by action",
+			changes: []Change{
+				{Action: Create, Kind: "site", Name: "demo.nitro"},
+				{Action: Remove, Kind: "container", Name: "old.nitro"},
+				{Action: Create, Kind: "database", Name: "mysql-8.0-3306.database.nitro"},
+				{Action: None, Kind: "site", Name: "current.nitro"},
+			},
+			wantChanges: true,
+			wantSummary: "2 to create, 1 to remove",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plan{}
+			for _, c := range tt.changes {
+				p.Add(c)
+			}
+
+			if got := p.HasChanges(); got != tt.wantChanges {
+				t.Errorf("HasChanges() = %v, want %v", got, tt.wantChanges)
+			}
+
+			if got := p.Summary(); got != tt.wantSummary {
+				t.Errorf("Summary() = %v, want %v", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	c := Change{Action: Recreate, Kind: "site", Name: "demo.nitro"}
+
+	if got, want := c.String(), "~ recreate site demo.nitro"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestDifference_String(t *testing.T) {
+	tests := []struct {
+		name string
+		diff Difference
+		want string
+	}{
+		{
+			name: "values are shown",
+			diff: Difference{Field: "image", Actual: "nginx:7.4-dev", Expected: "nginx:8.0-dev"},
+			want: "image: nginx:7.4-dev → nginx:8.0-dev",
+		},
+		{
+			name: "empty values are shown as none",
+			diff: Difference{Field: "extensions", Expected: "redis"},
+			want: "extensions: (none) → redis",
+		},
+		{
+			name: "differences without values only show the field",
+			diff: Difference{Field: "env API_KEY"},
+			want: "env API_KEY changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diff.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
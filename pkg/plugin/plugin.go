@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix for the executables that add commands to nitro, the
+// command is the name without the prefix (e.g. nitro-deploy adds deploy).
+const Prefix = "nitro-"
+
+// Plugin is an executable on the PATH that is run as a nitro command.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Find searches the directories in the path list (e.g. $PATH) for plugins
+// sorted by name. Like the shell, the first executable found for a name is
+// used when there is more than one.
+func Find(path string) []Plugin {
+	found := make(map[string]Plugin)
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, f := range files {
+			// use the mode of the file a symlink points to
+			if f.Mode()&os.ModeSymlink != 0 {
+				info, err := os.Stat(filepath.Join(dir, f.Name()))
+				if err != nil {
+					continue
+				}
+
+				f = info
+			}
+
+			name, ok := name(f)
+			if !ok {
+				continue
+			}
+
+			if _, exists := found[name]; exists {
+				continue
+			}
+
+			found[name] = Plugin{Name: name, Path: filepath.Join(dir, f.Name())}
+		}
+	}
+
+	var plugins []Plugin
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// name returns the command name for the file if it is a plugin.
+func name(f os.FileInfo) (string, bool) {
+	if f.IsDir() || !strings.HasPrefix(f.Name(), Prefix) {
+		return "", false
+	}
+
+	n := strings.TrimPrefix(f.Name(), Prefix)
+
+	switch runtime.GOOS {
+	case "windows":
+		ext := strings.ToLower(filepath.Ext(n))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+
+		n = strings.TrimSuffix(n, filepath.Ext(n))
+	default:
+		if f.Mode()&0111 == 0 {
+			return "", false
+		}
+	}
+
+	if n == "" || strings.ContainsAny(n, " \t") {
+		return "", false
+	}
+
+	return n, true
+}
@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins on windows use the file extension")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+
+	files := []struct {
+		dir  string
+		name string
+		mode os.FileMode
+	}{
+		{dir: first, name: "nitro-deploy", mode: 0755},
+		{dir: first, name: "nitro-notes", mode: 0644},
+		{dir: first, name: "composer", mode: 0755},
+		{dir: second, name: "nitro-deploy", mode: 0755},
+		{dir: second, name: "nitro-db-sync", mode: 0755},
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(f.dir, f.name), []byte("#!/bin/sh\n"), f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// directories are not plugins
+	if err := os.Mkdir(filepath.Join(second, "nitro-dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// symlinks use the mode of the executable
+	if err := os.Symlink(filepath.Join(second, "nitro-db-sync"), filepath.Join(first, "nitro-sync")); err != nil {
+		t.Fatal(err)
+	}
+
+	path := strings.Join([]string{first, filepath.Join(first, "missing"), second}, string(os.PathListSeparator))
+
+	want := []Plugin{
+		{Name: "db-sync", Path: filepath.Join(second, "nitro-db-sync")},
+		{Name: "deploy", Path: filepath.Join(first, "nitro-deploy")},
+		{Name: "sync", Path: filepath.Join(first, "nitro-sync")},
+	}
+
+	if got := Find(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+}
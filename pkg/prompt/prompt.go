@@ -24,13 +24,22 @@ import (
 // CreateDatabase is used to interactively walk a user through creating a new database. It will return true if the user created a database along
 // with the hostname, database, port, and driver for the database container.
 func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output terminal.Outputer) (bool, string, string, string, string, error) {
-	confirm, err := output.Confirm("Add a database for the site?", true, "")
-	if err != nil {
-		return false, "", "", "", "", err
+	if skip, _ := cmd.Flags().GetBool("skip-database"); skip {
+		return false, "", "", "", "", nil
 	}
 
-	if !confirm {
-		return false, "", "", "", "", nil
+	// the database flags answer the prompt
+	engine, _ := cmd.Flags().GetString("database-engine")
+	name, _ := cmd.Flags().GetString("database-name")
+	if engine == "" && name == "" {
+		confirm, err := terminal.Flag(output, "skip-database").Confirm("Add a database for the site?", true, "")
+		if err != nil {
+			return false, "", "", "", "", err
+		}
+
+		if !confirm {
+			return false, "", "", "", "", nil
+		}
 	}
 
 	// make sure the context is not nil
@@ -69,12 +78,12 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 			}
 		}
 
-		engineOpts = append(engineOpts, strings.TrimLeft(c.Names[0], "/"))
+		engineOpts = append(engineOpts, containerlabels.Hostname(c.Names[0]))
 	}
 
 	// prompt the user for the engine to add the database
 	var containerID, databaseEngine string
-	selected, err := output.Select(os.Stdin, "Select the database engine: ", engineOpts)
+	selected, err := Select(cmd, output, "database-engine", "Select the database engine: ", engineOpts)
 	if err != nil {
 		return false, "", "", "", "", err
 	}
@@ -87,7 +96,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 	}
 
 	// ask the user for the database to create
-	db, err := output.Ask("Enter the new database name", "", ":", &validate.DatabaseName{})
+	db, err := Ask(cmd, output, "database-name", "Enter the new database name", "", ":", &validate.DatabaseName{})
 	if err != nil {
 		return false, "", "", "", "", err
 	}
@@ -173,7 +182,7 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 	output.Info("Database added 💪")
 
 	// get the container hostname
-	hostname := strings.TrimLeft(containers[selected].Names[0], "/")
+	hostname := containerlabels.Hostname(containers[selected].Names[0])
 
 	// get the info from the container
 	info, err := docker.ContainerInspect(ctx, containers[selected].ID)
@@ -200,8 +209,9 @@ func CreateDatabase(cmd *cobra.Command, docker client.CommonAPIClient, output te
 }
 
 // CreateSite takes the users home directory and the site path and walked the user
-// through adding a site to the config.
-func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error) {
+// through adding a site to the config. The hostname, webroot, and php flags are
+// used instead of prompting when they are set.
+func CreateSite(cmd *cobra.Command, home, dir string, output terminal.Outputer) (*config.Site, error) {
 	// create a new site
 	site := config.Site{}
 
@@ -222,7 +232,7 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 	}
 
 	// prompt for the hostname
-	hostname, err := output.Ask("Enter the hostname", site.Hostname, ":", &validate.HostnameValidator{})
+	hostname, err := Ask(cmd, output, "hostname", "Enter the hostname", site.Hostname, ":", &validate.HostnameValidator{})
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +263,7 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 	site.Webroot = found
 
 	// prompt for the web root
-	root, err := output.Ask("Enter the web root for the site", site.Webroot, ":", nil)
+	root, err := Ask(cmd, output, "webroot", "Enter the web root for the site", site.Webroot, ":", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +274,7 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 
 	// prompt for the php version
 	versions := phpversions.Versions
-	selected, err := output.Select(os.Stdin, "Choose a PHP version: ", versions)
+	selected, err := Select(cmd, output, "php", "Choose a PHP version: ", versions)
 	if err != nil {
 		return nil, err
 	}
@@ -297,8 +307,12 @@ func CreateSite(home, dir string, output terminal.Outputer) (*config.Site, error
 // option that will not prompt the user and run apply regardless.
 func RunApply(cmd *cobra.Command, args []string, force bool, output terminal.Outputer) error {
 	if !force {
+		if skip, _ := cmd.Flags().GetBool("skip-apply"); skip {
+			return nil
+		}
+
 		// ask if the apply command should run
-		apply, err := output.Confirm("Apply changes now?", true, "")
+		apply, err := terminal.Flag(output, "yes").Confirm("Apply changes now?", true, "")
 		if err != nil {
 			return err
 		}
@@ -334,7 +348,7 @@ func VerifyInit(cmd *cobra.Command, args []string, home string, output terminal.
 		output.Info("Warning:", err.Error())
 
 		// ask if the init command should run
-		init, err := output.Confirm("Run `nitro init` now to create the config?", true, "")
+		init, err := terminal.Flag(output, "yes").Confirm("Run `nitro init` now to create the config?", true, "")
 		if err != nil {
 			return err
 		}
@@ -357,3 +371,79 @@ func VerifyInit(cmd *cobra.Command, args []string, home string, output terminal.
 
 	return nil
 }
+
+// SiteFlags adds the flags used instead of the prompts in CreateSite.
+func SiteFlags(cmd *cobra.Command) {
+	cmd.Flags().String("hostname", "", "the hostname for the site (e.g. my-project.nitro)")
+	cmd.Flags().String("webroot", "", "the web root for the site (e.g. web)")
+	cmd.Flags().String("php", "", "the PHP version for the site (e.g. 8.0)")
+}
+
+// DatabaseFlags adds the flags used instead of the prompts in CreateDatabase.
+func DatabaseFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("skip-database", false, "skip adding a database for the site")
+	cmd.Flags().String("database-engine", "", "the database engine for the new database (e.g. mysql-8.0-3306.database.nitro)")
+	cmd.Flags().String("database-name", "", "the name of the new database")
+}
+
+// SiteFlag adds the flag used instead of the prompt to select a site.
+func SiteFlag(cmd *cobra.Command) {
+	cmd.Flags().String("site", "", "the hostname of the site to use instead of selecting it (e.g. my-project.nitro)")
+}
+
+// FlagSites returns the site from the site flag, when it is set, so the site is
+// used without a prompt. Otherwise the sites are returned unchanged.
+func FlagSites(cmd *cobra.Command, cfg *config.Config, sites []config.Site) ([]config.Site, error) {
+	hostname, _ := cmd.Flags().GetString("site")
+	if hostname == "" {
+		return sites, nil
+	}
+
+	site, err := cfg.FindSiteByHostName(hostname)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for --site, %w", err)
+	}
+
+	return []config.Site{*site}, nil
+}
+
+// Ask returns the value of the flag, when it is set, or prompts for the value. When
+// prompts are disabled the error names the flag.
+func Ask(cmd *cobra.Command, output terminal.Outputer, flag, message, fallback, sep string, validator terminal.Validator) (string, error) {
+	if v, _ := cmd.Flags().GetString(flag); v != "" {
+		if validator != nil {
+			if err := validator.Validate(v); err != nil {
+				return "", fmt.Errorf("invalid value for --%s, %w", flag, err)
+			}
+		}
+
+		return v, nil
+	}
+
+	return terminal.Flag(output, flag).Ask(message, fallback, sep, validator)
+}
+
+// Select returns the position of the flag value in the options, when it is set, or
+// prompts for the option. When prompts are disabled the error names the flag.
+func Select(cmd *cobra.Command, output terminal.Outputer, flag, message string, opts []string) (int, error) {
+	if v, _ := cmd.Flags().GetString(flag); v != "" {
+		for i, o := range opts {
+			if o == v {
+				return i, nil
+			}
+		}
+
+		return 0, fmt.Errorf("invalid value for --%s %q, use one of %s", flag, v, strings.Join(opts, ", "))
+	}
+
+	return terminal.Flag(output, flag).Select(cmd.InOrStdin(), message, opts)
+}
+
+// Confirm returns the value of the flag, when it is set, or asks the user to confirm.
+func Confirm(cmd *cobra.Command, output terminal.Outputer, flag, message string, fallback bool) (bool, error) {
+	if cmd.Flags().Changed(flag) {
+		return cmd.Flags().GetBool(flag)
+	}
+
+	return terminal.Flag(output, flag).Confirm(message, fallback, "")
+}
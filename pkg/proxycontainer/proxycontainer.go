@@ -3,13 +3,19 @@ package proxycontainer
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	volumetypes "github.com/docker/docker/api/types/volume"
 
 	"github.com/craftcms/nitro/command/version"
+	"github.com/craftcms/nitro/pkg/apiauth"
+	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -24,15 +30,13 @@ var (
 	// ProxyImage is the docker hub image with the current CLI version
 	ProxyImage = fmt.Sprintf("craftcms/nitro-proxy:%s", version.Version)
 
-	// ProxyName is the name of the proxy container (e.g. nitro-proxy)
-	ProxyName = "nitro-proxy"
-
 	// ErrNoProxyContainer is returned when the proxy container is not found
 	ErrNoProxyContainer = fmt.Errorf("unable to locate the proxy container")
 )
 
-// Create is used to create a new proxy container for the nitro development environment.
-func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID string) error {
+// Create is used to create a new proxy container for the nitro development environment. The
+// credentials are passed to nitrod so the API requires TLS and the token.
+func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.Outputer, networkID string, creds *apiauth.Credentials) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -75,7 +79,7 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	var skipVolume bool
 	var volume *types.Volume
 	for _, v := range volumes.Volumes {
-		if v.Name == "nitro" {
+		if v.Name == containerlabels.VolumeName() {
 			skipVolume = true
 			volume = v
 		}
@@ -91,10 +95,10 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		// create a volume with the same name of the machine
 		resp, err := docker.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
 			Driver: "local",
-			Name:   "nitro",
+			Name:   containerlabels.VolumeName(),
 			Labels: map[string]string{
 				containerlabels.Nitro:  "true",
-				containerlabels.Volume: "nitro",
+				containerlabels.Volume: containerlabels.VolumeName(),
 			},
 		})
 		if err != nil {
@@ -121,9 +125,13 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	// check the containers and verify its running
 	for _, c := range containers {
 		for _, n := range c.Names {
-			if n == "nitro-proxy" || n == "/nitro-proxy" {
+			if n == containerlabels.ProxyName() || n == "/"+containerlabels.ProxyName() {
 				// check if it is running
 				if c.State != "running" {
+					if err := StopOthers(ctx, docker, output); err != nil {
+						return err
+					}
+
 					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
 						return fmt.Errorf("unable to start the nitro container, %w", err)
 					}
@@ -136,6 +144,11 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		}
 	}
 
+	// every environment uses the same ports, so stop the other environments
+	if err := StopOthers(ctx, docker, output); err != nil {
+		return err
+	}
+
 	// if we do not have a proxy, it needs to be create
 	output.Pending("creating proxy")
 
@@ -211,7 +224,7 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 				containerlabels.Proxy:        "true",
 				containerlabels.ProxyVersion: version.Version,
 			},
-			Env: []string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=" + version.Version},
+			Env: append([]string{"PGPASSWORD=nitro", "PGUSER=nitro", "NITRO_VERSION=" + version.Version}, creds.Env()...),
 		},
 		&container.HostConfig{
 			NetworkMode: "default",
@@ -257,13 +270,13 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
+				containerlabels.NetworkName(): {
 					NetworkID: networkID,
 				},
 			},
 		},
 		nil,
-		ProxyName,
+		containerlabels.ProxyName(),
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create proxy container: %s\n%w", ProxyImage, err)
@@ -278,10 +291,132 @@ func Create(ctx context.Context, docker client.CommonAPIClient, output terminal.
 	return nil
 }
 
-// FindAndStart will look for the proxy container and verify the container is started. It will return the
-// ErrNoProxyContainer error if it is unable to locate the proxy container. It is NOT responsible for
-// creating the proxy container as that is handled in the initialize package.
-func FindAndStart(ctx context.Context, docker client.ContainerAPIClient) (types.Container, error) {
+// Plan returns the change needed for the proxy container without making any changes.
+func Plan(ctx context.Context, docker client.ContainerAPIClient) (plan.Change, error) {
+	change := plan.Change{Action: plan.None, Kind: "proxy", Name: containerlabels.ProxyName()}
+
+	c, err := find(ctx, docker)
+	switch {
+	case errors.Is(err, ErrNoProxyContainer):
+		change.Action = plan.Create
+		return change, nil
+	case err != nil:
+		return change, err
+	}
+
+	change.ID = c.ID
+
+	if c.State != "running" {
+		change.Action = plan.Start
+	}
+
+	return change, nil
+}
+
+// FindAndStart will look for the proxy container and verify the container is started, the proxies for the
+// other environments are stopped first. It will return the ErrNoProxyContainer error if it is unable to
+// locate the proxy container. It is NOT responsible for creating the proxy container as that is handled
+// in the initialize package.
+func FindAndStart(ctx context.Context, docker client.ContainerAPIClient, output terminal.Outputer) (types.Container, error) {
+	c, err := find(ctx, docker)
+	if err != nil {
+		return types.Container{}, err
+	}
+
+	// check if it is running
+	if c.State != "running" {
+		if err := StopOthers(ctx, docker, output); err != nil {
+			return types.Container{}, err
+		}
+
+		if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+			return types.Container{}, fmt.Errorf("unable to start the proxy container: %w", err)
+		}
+	}
+
+	// return the container
+	return c, nil
+}
+
+// StopOthers stops the running containers for the other environments that publish ports,
+// which are the proxy, databases, services, and custom containers. The environments use
+// the same ports, so only one environment can be running at a time.
+func StopOthers(ctx context.Context, docker client.ContainerAPIClient, output terminal.Outputer) error {
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to list the containers, %w", err)
+	}
+
+	for _, c := range containers {
+		if !otherEnvironment(c.Labels) || !publishesPorts(c) {
+			continue
+		}
+
+		name := strings.TrimLeft(c.Names[0], "/")
+
+		output.Pending("stopping", name)
+
+		if err := docker.ContainerStop(ctx, c.ID, nil); err != nil {
+			return fmt.Errorf("unable to stop the container %s, %w", name, err)
+		}
+
+		output.Done()
+	}
+
+	return nil
+}
+
+// APIAddr returns the address of the nitrod API published by the proxy container for the
+// environment. It returns an error if the proxy is not running, so commands do not use the
+// API of another environment.
+func APIAddr(ctx context.Context, docker client.ContainerAPIClient) (string, error) {
+	c, err := find(ctx, docker)
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range c.Ports {
+		if p.PrivatePort == 5000 && p.PublicPort != 0 {
+			return "127.0.0.1:" + strconv.Itoa(int(p.PublicPort)), nil
+		}
+	}
+
+	return "", fmt.Errorf("the proxy container %s is not running, run `nitro start --env %s` to start it", containerlabels.ProxyName(), config.Environment)
+}
+
+// otherEnvironment checks the labels for the nitro label of an environment other than the
+// selected one (e.g. com.craftcms.nitro-client=true).
+func otherEnvironment(labels map[string]string) bool {
+	for k, v := range labels {
+		if k == containerlabels.Nitro || v != "true" {
+			continue
+		}
+
+		if k == "com.craftcms.nitro" {
+			return true
+		}
+
+		if env := strings.TrimPrefix(k, "com.craftcms.nitro-"); env != k && !strings.Contains(env, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// publishesPorts returns true if the container binds any ports on the host.
+func publishesPorts(c types.Container) bool {
+	for _, p := range c.Ports {
+		if p.PublicPort != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// find returns the proxy container for the environment.
+func find(ctx context.Context, docker client.ContainerAPIClient) (types.Container, error) {
 	// create the filters for the proxy
 	f := filters.NewArgs()
 	f.Add("label", containerlabels.Type+"=proxy")
@@ -294,15 +429,7 @@ func FindAndStart(ctx context.Context, docker client.ContainerAPIClient) (types.
 
 	for _, c := range containers {
 		for _, n := range c.Names {
-			if n == "nitro-proxy" || n == "/nitro-proxy" {
-				// check if it is running
-				if c.State != "running" {
-					if err := docker.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
-						return types.Container{}, fmt.Errorf("unable to start the proxy container: %w", err)
-					}
-				}
-
-				// return the container
+			if n == containerlabels.ProxyName() || n == "/"+containerlabels.ProxyName() {
 				return c, nil
 			}
 		}
@@ -0,0 +1,51 @@
+package proxycontainer
+
+import (
+	"testing"
+
+	"github.com/craftcms/nitro/pkg/containerlabels"
+)
+
+func Test_otherEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		labels      map[string]string
+		want        bool
+	}{
+		{
+			name:        "the selected environment is not another environment",
+			environment: "client",
+			labels:      map[string]string{"com.craftcms.nitro-client": "true", "com.craftcms.nitro-client.type": "database"},
+			want:        false,
+		},
+		{
+			name:        "the default environment is another environment",
+			environment: "client",
+			labels:      map[string]string{"com.craftcms.nitro": "true", "com.craftcms.nitro.type": "proxy"},
+			want:        true,
+		},
+		{
+			name:        "named environments are other environments",
+			environment: "nitro",
+			labels:      map[string]string{"com.craftcms.nitro-client": "true", "com.craftcms.nitro-client.type": "database"},
+			want:        true,
+		},
+		{
+			name:        "containers without the nitro label are ignored",
+			environment: "nitro",
+			labels:      map[string]string{"com.craftcms.nitro-client.type": "database"},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containerlabels.UseEnvironment(tt.environment)
+			defer containerlabels.UseEnvironment("")
+
+			if got := otherEnvironment(tt.labels); got != tt.want {
+				t.Errorf("otherEnvironment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
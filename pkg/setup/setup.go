@@ -1,14 +1,17 @@
 package setup
 
 import (
-	"io"
-	"os"
+	"fmt"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
 
 	"github.com/craftcms/nitro/pkg/config"
 	"github.com/craftcms/nitro/pkg/portavail"
+	"github.com/craftcms/nitro/pkg/prompt"
 	"github.com/craftcms/nitro/pkg/terminal"
 )
 
@@ -20,8 +23,9 @@ var (
 // FirstTime is used when there is no configuration file found in a users
 // home/.nitro directory. We do not prompt for input such as memory, cpu,
 // disk space in version 2 as that is defined and managed at the docker
-// level. If anything fails, we return an error.
-func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
+// level. If anything fails, we return an error. The mysql, mariadb,
+// postgres, and redis flags are used instead of prompting when they are set.
+func FirstTime(cmd *cobra.Command, home string, output terminal.Outputer) error {
 	c := config.Config{File: filepath.Join(home, config.DirectoryName, config.FileName)}
 
 	output.Info("Setting up Nitro…")
@@ -35,21 +39,12 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 			output.Info("ARM computers do not work with MySQL images.")
 		}
 
-		mariadb, err := output.Confirm("Would you like to use MariaDB?", true, "")
+		version, err := selectVersion(cmd, output, "mariadb", "Would you like to use MariaDB?", "Select MariaDB version: ", []string{"10.5", "10.4", "10.3", "10.2", "10.1", "10"})
 		if err != nil {
 			return err
 		}
 
-		if mariadb {
-			// prompt for the version
-			opts := []string{"10.5", "10.4", "10.3", "10.2", "10.1", "10"}
-			selected, err := output.Select(os.Stdin, "Select MariaDB version: ", opts)
-			if err != nil {
-				return err
-			}
-
-			version := opts[selected]
-
+		if version != "" {
 			// check if the port is available
 			var port string
 			for {
@@ -71,21 +66,12 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 			})
 		}
 	default:
-		mysql, err := output.Confirm("Would you like to use MySQL?", true, "")
+		version, err := selectVersion(cmd, output, "mysql", "Would you like to use MySQL?", "Select MySQL version: ", []string{"8.0", "5.7", "5.6"})
 		if err != nil {
 			return err
 		}
 
-		if mysql {
-			// prompt for the version
-			opts := []string{"8.0", "5.7", "5.6"}
-			selected, err := output.Select(os.Stdin, "Select MySQL version: ", opts)
-			if err != nil {
-				return err
-			}
-
-			version := opts[selected]
-
+		if version != "" {
 			// check if the port is available
 			var port string
 			for {
@@ -108,21 +94,12 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 		}
 	}
 
-	postgres, err := output.Confirm("Would you like to use PostgreSQL?", true, "")
+	version, err := selectVersion(cmd, output, "postgres", "Would you like to use PostgreSQL?", "Select PostgreSQL version: ", []string{"13", "12", "11", "10", "9"})
 	if err != nil {
 		return err
 	}
 
-	if postgres {
-		// prompt for the version
-		opts := []string{"13", "12", "11", "10", "9"}
-		selected, err := output.Select(os.Stdin, "Select PostgreSQL version: ", opts)
-		if err != nil {
-			return err
-		}
-
-		version := opts[selected]
-
+	if version != "" {
 		// check if the port is available
 		var port string
 		for {
@@ -144,7 +121,7 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 		})
 	}
 
-	redis, err := output.Confirm("Would you like to use Redis?", true, "")
+	redis, err := prompt.Confirm(cmd, output, "redis", "Would you like to use Redis?", true)
 	if err != nil {
 		return err
 	}
@@ -164,3 +141,47 @@ func FirstTime(home string, reader io.Reader, output terminal.Outputer) error {
 
 	return nil
 }
+
+// Flags adds the flags used instead of the prompts in FirstTime.
+func Flags(cmd *cobra.Command) {
+	cmd.Flags().String("mysql", "", "the MySQL version to use (e.g. 8.0), or none")
+	cmd.Flags().String("mariadb", "", "the MariaDB version to use on ARM computers (e.g. 10.5), or none")
+	cmd.Flags().String("postgres", "", "the PostgreSQL version to use (e.g. 13), or none")
+	cmd.Flags().Bool("redis", true, "use the Redis service")
+}
+
+// selectVersion returns the version from the flag, when it is set, or asks the user if they
+// want to use the database and prompts for the version. An empty version is returned when
+// the database should not be added.
+func selectVersion(cmd *cobra.Command, output terminal.Outputer, flag, confirm, message string, opts []string) (string, error) {
+	if cmd.Flags().Changed(flag) {
+		v, _ := cmd.Flags().GetString(flag)
+		if v == "" || v == "none" {
+			return "", nil
+		}
+
+		for _, o := range opts {
+			if o == v {
+				return v, nil
+			}
+		}
+
+		return "", fmt.Errorf("invalid value for --%s %q, use one of %s or none", flag, v, strings.Join(opts, ", "))
+	}
+
+	use, err := terminal.Flag(output, flag).Confirm(confirm, true, "")
+	if err != nil {
+		return "", err
+	}
+
+	if !use {
+		return "", nil
+	}
+
+	selected, err := terminal.Flag(output, flag).Select(cmd.InOrStdin(), message, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return opts[selected], nil
+}
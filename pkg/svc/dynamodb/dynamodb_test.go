@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -388,6 +389,56 @@ func TestVerifyRemoved(t *testing.T) {
 	}
 }
 
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		containers []types.Container
+		want       plan.Change
+	}{
+		{
+			name:    "missing containers are created when enabled",
+			enabled: true,
+			want:    plan.Change{Action: plan.Create, Kind: "service", Name: Host},
+		},
+		{
+			name:       "stopped containers are started when enabled",
+			enabled:    true,
+			containers: []types.Container{{ID: "some-id", State: "exited"}},
+			want:       plan.Change{Action: plan.Start, Kind: "service", Name: Host, ID: "some-id"},
+		},
+		{
+			name:       "running containers are not changed",
+			enabled:    true,
+			containers: []types.Container{{ID: "some-id", State: "running"}},
+			want:       plan.Change{Action: plan.None, Kind: "service", Name: Host},
+		},
+		{
+			name:       "containers are removed when disabled",
+			containers: []types.Container{{ID: "some-id", State: "running"}},
+			want:       plan.Change{Action: plan.Remove, Kind: "service", Name: Host, ID: "some-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &mockClient{containers: tt.containers}
+
+			got, err := Plan(context.Background(), spy, tt.enabled)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan() = %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(spy.containerCreateConfig, types.ContainerCreateConfig{}) || spy.containerStartID != "" || spy.containerRemoveID != "" {
+				t.Errorf("Plan() made changes to the containers")
+			}
+		})
+	}
+}
+
 type mockClient struct {
 	client.CommonAPIClient
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/craftcms/nitro/pkg/containerlabels"
+	"github.com/craftcms/nitro/pkg/plan"
 	"github.com/craftcms/nitro/pkg/terminal"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -92,15 +93,11 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 		}
 
 		networkConfig := &network.NetworkingConfig{
-			EndpointsConfig: map[string]*network.EndpointSettings{
-				"nitro-network": {
-					NetworkID: networkID,
-				},
-			},
+			EndpointsConfig: containerlabels.EndpointsConfig(networkID, Host),
 		}
 
 		// create the container
-		resp, err := cli.ContainerCreate(ctx, containerConfig, hostconfig, networkConfig, nil, Host)
+		resp, err := cli.ContainerCreate(ctx, containerConfig, hostconfig, networkConfig, nil, containerlabels.ContainerName(Host))
 		if err != nil {
 			return "", "", fmt.Errorf("unable to create the container, %w", err)
 		}
@@ -126,6 +123,39 @@ func VerifyCreated(ctx context.Context, cli client.CommonAPIClient, networkID st
 	return containers[0].ID, Host, nil
 }
 
+// Plan returns the change needed for the redis service container without making any changes. If the
+// service is not enabled, any existing containers will be removed.
+func Plan(ctx context.Context, cli client.CommonAPIClient, enabled bool) (plan.Change, error) {
+	change := plan.Change{Action: plan.None, Kind: "service", Name: Host}
+
+	// add the filter
+	filter := filters.NewArgs()
+	filter.Add("label", containerlabels.Nitro+"=true")
+	filter.Add("label", containerlabels.Type+"="+Label)
+
+	// get a list of containers
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filter,
+	})
+	if err != nil {
+		return change, err
+	}
+
+	switch {
+	case !enabled && len(containers) > 0:
+		change.Action = plan.Remove
+		change.ID = containers[0].ID
+	case enabled && len(containers) == 0:
+		change.Action = plan.Create
+	case enabled && containers[0].State != "running":
+		change.Action = plan.Start
+		change.ID = containers[0].ID
+	}
+
+	return change, nil
+}
+
 // VerifyRemoved will try verify the container is not created for the minio service. If we find any containers that are
 func VerifyRemoved(ctx context.Context, cli client.CommonAPIClient, output terminal.Outputer) error {
 	// add the filter
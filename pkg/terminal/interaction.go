@@ -0,0 +1,118 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNoInteraction is returned when a command needs to prompt for input and
+// prompts are disabled (e.g. --no-interaction or the json output).
+var ErrNoInteraction = fmt.Errorf("unable to prompt for input")
+
+// SetNoInteraction disables the prompts, prompts with a fallback use it and
+// prompts without one return ErrNoInteraction. Confirmations always return
+// ErrNoInteraction, since the fallback would make or skip a change silently.
+func (t *terminal) SetNoInteraction(v bool) {
+	t.noInteraction = v
+}
+
+// SetYes answers every confirmation with yes without a prompt.
+func (t *terminal) SetYes(v bool) {
+	t.yes = v
+}
+
+// Flag returns the output with the name of the flag that can be used instead
+// of the next prompt. When prompts are disabled, the error names the flag
+// (e.g. use the --php flag).
+func Flag(output Outputer, name string) Outputer {
+	return &flagged{Outputer: output, flag: name}
+}
+
+type flagged struct {
+	Outputer
+	flag string
+}
+
+func (f *flagged) Ask(message, fallback, sep string, validator Validator) (string, error) {
+	v, err := f.Outputer.Ask(message, fallback, sep, validator)
+
+	return v, f.wrap(err)
+}
+
+func (f *flagged) Confirm(message string, fallback bool, sep string) (bool, error) {
+	v, err := f.Outputer.Confirm(message, fallback, sep)
+
+	return v, f.wrap(err)
+}
+
+func (f *flagged) Select(r io.Reader, msg string, opts []string) (int, error) {
+	v, err := f.Outputer.Select(r, msg, opts)
+
+	return v, f.wrap(err)
+}
+
+func (f *flagged) wrap(err error) error {
+	if errors.Is(err, ErrNoInteraction) {
+		return fmt.Errorf("%w, use the --%s flag", err, f.flag)
+	}
+
+	return err
+}
+
+// Discard returns an Outputer that does not show any messages and does not
+// prompt for input, it is used when stdout is used for data (e.g. writing a
+// database backup to stdout).
+func Discard() Outputer {
+	return discard{}
+}
+
+type discard struct{}
+
+func (discard) Ask(message, fallback, sep string, validator Validator) (string, error) {
+	return askFallback(message, fallback, validator)
+}
+
+func (discard) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return false, noPrompt(message)
+}
+
+func (discard) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return selectFallback(msg, opts)
+}
+
+func (discard) Info(s ...string)    {}
+func (discard) Success(s ...string) {}
+func (discard) Pending(s ...string) {}
+func (discard) Done()               {}
+func (discard) Warning()            {}
+
+// askFallback returns the answer for Ask when prompts are disabled.
+func askFallback(message, fallback string, validator Validator) (string, error) {
+	if fallback == "" {
+		return "", noPrompt(message)
+	}
+
+	if validator != nil {
+		if err := validator.Validate(fallback); err != nil {
+			return "", err
+		}
+	}
+
+	return fallback, nil
+}
+
+// selectFallback returns the answer for Select when prompts are disabled,
+// only a single option can be selected without a prompt.
+func selectFallback(msg string, opts []string) (int, error) {
+	if len(opts) == 1 {
+		return 0, nil
+	}
+
+	return 0, noPrompt(msg)
+}
+
+func noPrompt(message string) error {
+	return fmt.Errorf("%w %q", ErrNoInteraction, strings.TrimRight(strings.TrimSpace(message), ":?"))
+}
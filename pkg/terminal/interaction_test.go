@@ -0,0 +1,120 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type errValidator struct{}
+
+func (v *errValidator) Validate(input string) error {
+	return fmt.Errorf("invalid input %q", input)
+}
+
+func TestTerminal_NoInteraction(t *testing.T) {
+	term := New()
+	term.SetNoInteraction(true)
+
+	tests := []struct {
+		name    string
+		run     func() (interface{}, error)
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "ask uses the fallback",
+			run: func() (interface{}, error) {
+				return term.Ask("Enter the webroot for the site", "web", ":", nil)
+			},
+			want: "web",
+		},
+		{
+			name: "ask validates the fallback",
+			run: func() (interface{}, error) {
+				return term.Ask("Enter the webroot for the site", "web", ":", &errValidator{})
+			},
+			want:    "",
+			wantErr: `invalid input "web"`,
+		},
+		{
+			name: "ask without a fallback names the flag",
+			run: func() (interface{}, error) {
+				return Flag(term, "hostname").Ask("Enter the hostname", "", ":", nil)
+			},
+			want:    "",
+			wantErr: `unable to prompt for input "Enter the hostname", use the --hostname flag`,
+		},
+		{
+			name: "confirm without a flag returns an error",
+			run: func() (interface{}, error) {
+				return Flag(term, "yes").Confirm("Apply changes now?", true, "?")
+			},
+			want:    false,
+			wantErr: `unable to prompt for input "Apply changes now", use the --yes flag`,
+		},
+		{
+			name: "select with a single option",
+			run: func() (interface{}, error) {
+				return term.Select(strings.NewReader(""), "Select a PHP version: ", []string{"7.4"})
+			},
+			want: 0,
+		},
+		{
+			name: "select with options names the flag",
+			run: func() (interface{}, error) {
+				return Flag(term, "php").Select(strings.NewReader(""), "Select a PHP version: ", []string{"8.0", "7.4"})
+			},
+			want:    0,
+			wantErr: `unable to prompt for input "Select a PHP version", use the --php flag`,
+		},
+		{
+			name: "discard does not prompt",
+			run: func() (interface{}, error) {
+				return Flag(Discard(), "engine").Select(strings.NewReader("1\n"), "Which database engine? ", []string{"mysql-8.0-3306.database.nitro", "postgres-13-5432.database.nitro"})
+			},
+			want:    0,
+			wantErr: `unable to prompt for input "Which database engine", use the --engine flag`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.run()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected the error %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTerminal_Yes(t *testing.T) {
+	term := New()
+	term.SetNoInteraction(true)
+	term.SetYes(true)
+
+	confirm, err := term.Confirm("Are you sure?", false, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !confirm {
+		t.Errorf("expected the confirmation to be answered with yes")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	_, err := Flag(NewJSON(&strings.Builder{}), "name").Ask("Enter the database name", "", ":", nil)
+
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
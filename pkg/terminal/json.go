@@ -0,0 +1,158 @@
+package terminal
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	// FormatText is the default output format for people
+	FormatText = "text"
+
+	// FormatJSON is the output format for scripts and tools, each line is a JSON event
+	FormatJSON = "json"
+)
+
+// Event is a single line of JSON output. Steps are shown with a status of
+// done, failed, or pending and results contain the data for the command
+// (e.g. the containers for ls).
+type Event struct {
+	Type    string      `json:"type"`
+	Message string      `json:"message,omitempty"`
+	Status  string      `json:"status,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// JSON is an Outputer that writes each message as a JSON event on its own
+// line. Prompts are not interactive, so the fallback is used when there is
+// one and an error is returned otherwise.
+type JSON struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending string
+	step    bool
+}
+
+// NewJSON returns a JSON Outputer that writes to w.
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{w: w}
+}
+
+// JSONOutput returns the JSON Outputer if the output is using the json format.
+func JSONOutput(output Outputer) (*JSON, bool) {
+	switch o := output.(type) {
+	case *JSON:
+		return o, true
+	case *terminal:
+		return o.json, o.json != nil
+	}
+
+	return nil, false
+}
+
+// Result writes the data for the command (e.g. a list of containers).
+func (j *JSON) Result(v interface{}) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.flush()
+
+	return j.write(Event{Type: "result", Data: v})
+}
+
+// Error writes the error the command returned.
+func (j *JSON) Error(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.flush()
+
+	j.write(Event{Type: "error", Message: err.Error()})
+}
+
+func (j *JSON) Ask(message, fallback, sep string, validator Validator) (string, error) {
+	return askFallback(message, fallback, validator)
+}
+
+func (j *JSON) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return false, noPrompt(message)
+}
+
+func (j *JSON) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return selectFallback(msg, opts)
+}
+
+func (j *JSON) Info(s ...string) {
+	j.event("info", s)
+}
+
+func (j *JSON) Success(s ...string) {
+	j.event("success", s)
+}
+
+func (j *JSON) Pending(s ...string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.flush()
+
+	j.pending = strings.Join(s, " ")
+	j.step = true
+}
+
+func (j *JSON) Done() {
+	j.finish("done")
+}
+
+func (j *JSON) Warning() {
+	j.finish("failed")
+}
+
+// event writes a message, messages that are empty (e.g. spacing) are skipped.
+func (j *JSON) event(kind string, s []string) {
+	msg := strings.TrimSpace(strings.Join(s, " "))
+	if msg == "" {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.flush()
+
+	j.write(Event{Type: kind, Message: msg})
+}
+
+// finish writes the pending step with the status.
+func (j *JSON) finish(status string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.step {
+		return
+	}
+
+	j.write(Event{Type: "step", Message: j.pending, Status: status})
+
+	j.pending, j.step = "", false
+}
+
+// flush writes a step that has not finished, the caller must hold the lock.
+func (j *JSON) flush() {
+	if !j.step {
+		return
+	}
+
+	j.write(Event{Type: "step", Message: j.pending, Status: "pending"})
+
+	j.pending, j.step = "", false
+}
+
+func (j *JSON) write(e Event) error {
+	enc := json.NewEncoder(j.w)
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(e)
+}
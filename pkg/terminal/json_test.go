@@ -0,0 +1,78 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	j := NewJSON(buf)
+
+	j.Info("Checking network…")
+	j.Success("network ready")
+	j.Pending("checking", "demo.nitro")
+	j.Done()
+	j.Pending("checking", "mysql-8.0-3306.database.nitro")
+	j.Warning()
+	j.Pending("updating proxy")
+	j.Info("")
+	j.Info("Nitro is up and running 😃")
+
+	if err := j.Result([]string{"demo.nitro"}); err != nil {
+		t.Fatal(err)
+	}
+
+	j.Error(errors.New("unable to find the site"))
+
+	want := `{"type":"info","message":"Checking network…"}
+{"type":"success","message":"network ready"}
+{"type":"step","message":"checking demo.nitro","status":"done"}
+{"type":"step","message":"checking mysql-8.0-3306.database.nitro","status":"failed"}
+{"type":"step","message":"updating proxy","status":"pending"}
+{"type":"info","message":"Nitro is up and running 😃"}
+{"type":"result","data":["demo.nitro"]}
+{"type":"error","message":"unable to find the site"}
+`
+
+	if got := buf.String(); got != want {
+		t.Errorf("expected output:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestJSON_Ask(t *testing.T) {
+	j := NewJSON(&bytes.Buffer{})
+
+	if got, err := j.Ask("Enter the hostname", "demo.nitro", ":", nil); err != nil || got != "demo.nitro" {
+		t.Errorf("expected the fallback, got %q and error %v", got, err)
+	}
+
+	if _, err := j.Ask("Enter the hostname", "", ":", nil); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected error %v, got %v", ErrNoInteraction, err)
+	}
+
+	if _, err := j.Select(nil, "Select a site", []string{"demo.nitro", "other.nitro"}); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected error %v, got %v", ErrNoInteraction, err)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	term := New()
+
+	if _, ok := JSONOutput(term); ok {
+		t.Errorf("expected the text format by default")
+	}
+
+	if err := term.SetFormat(FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := JSONOutput(term); !ok {
+		t.Errorf("expected the json format after setting it")
+	}
+
+	if err := term.SetFormat("yaml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
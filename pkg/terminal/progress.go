@@ -0,0 +1,233 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrPromptInProgress is returned when a task tries to prompt for input while
+// other tasks are being rendered.
+var ErrPromptInProgress = fmt.Errorf("unable to prompt for input while tasks are running")
+
+const (
+	taskRunning = iota
+	taskDone
+	taskFailed
+)
+
+// Progress renders the status of tasks that run at the same time, with one
+// line per task. It is safe to use from multiple goroutines. When the output
+// is a terminal the lines are updated in place, otherwise each task is shown
+// with the Outputer once it finishes so the lines are not interleaved.
+type Progress struct {
+	mu       sync.Mutex
+	w        io.Writer
+	output   Outputer
+	live     bool
+	tasks    []*Task
+	rendered int
+}
+
+// Task is a single line in the progress. It implements the Outputer so it can
+// be passed to functions that show their own status (e.g. pulling an image).
+type Task struct {
+	progress *Progress
+	name     string
+	status   string
+	state    int
+	err      error
+	details  []string
+}
+
+// NewProgress returns a Progress for the output, the lines are only updated
+// in place when the output is the terminal using the text format and stdout
+// is not redirected.
+func NewProgress(output Outputer) *Progress {
+	t, ok := output.(*terminal)
+
+	return newProgress(os.Stdout, output, ok && t.json == nil && IsTTY(os.Stdout))
+}
+
+func newProgress(w io.Writer, output Outputer, live bool) *Progress {
+	return &Progress{w: w, output: output, live: live}
+}
+
+// Add adds a task to the progress with the name (e.g. demo.nitro).
+func (p *Progress) Add(name string) *Task {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := &Task{progress: p, name: name}
+	p.tasks = append(p.tasks, t)
+
+	p.render()
+
+	return t
+}
+
+// render redraws every task, the caller must hold the lock.
+func (p *Progress) render() {
+	if !p.live {
+		return
+	}
+
+	// move the cursor to the first line
+	if p.rendered > 0 {
+		fmt.Fprintf(p.w, "\033[%dA", p.rendered)
+	}
+
+	lines := 0
+	for _, t := range p.tasks {
+		for _, l := range t.lines() {
+			fmt.Fprintf(p.w, "\033[2K%s\n", l)
+			lines++
+		}
+	}
+
+	p.rendered = lines
+}
+
+// lines returns the lines for the task and any details.
+func (t *Task) lines() []string {
+	var line string
+	switch t.state {
+	case taskDone:
+		line = fmt.Sprintf("  ✓ %s", t.name)
+	case taskFailed:
+		line = fmt.Sprintf("  ✗ %s", t.name)
+		if t.err != nil {
+			line += ": " + t.err.Error()
+		}
+	default:
+		line = fmt.Sprintf("  … %s", t.name)
+		if t.status != "" {
+			line += " " + t.status
+		}
+	}
+
+	return append([]string{line}, t.details...)
+}
+
+// update changes the status of a running task.
+func (t *Task) update(status string) {
+	p := t.progress
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t.state != taskRunning {
+		return
+	}
+
+	t.status = status
+
+	p.render()
+}
+
+// Finish marks the task as done, or failed if there is an error, and shows the
+// details under the task.
+func (t *Task) Finish(err error, details ...string) {
+	p := t.progress
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t.state = taskDone
+	if err != nil {
+		t.state = taskFailed
+		t.err = err
+	}
+
+	t.details = details
+
+	if p.live {
+		p.render()
+		return
+	}
+
+	// show the task using the output, with the error as only the first error is returned
+	p.output.Pending(t.name)
+	if err != nil {
+		p.output.Warning()
+		p.output.Info("    " + err.Error())
+	} else {
+		p.output.Done()
+	}
+
+	for _, d := range details {
+		p.output.Info(d)
+	}
+}
+
+// Info sets the status of the task.
+func (t *Task) Info(s ...string) {
+	t.update(strings.Join(s, " "))
+}
+
+// Success sets the status of the task.
+func (t *Task) Success(s ...string) {
+	t.update(strings.Join(s, " "))
+}
+
+// Pending sets the status of the task.
+func (t *Task) Pending(s ...string) {
+	t.update(strings.Join(s, " "))
+}
+
+// Done does not change the task, use Finish when the task is complete.
+func (t *Task) Done() {}
+
+// Warning does not change the task, use Finish with the error when the task fails.
+func (t *Task) Warning() {}
+
+// Ask returns an error as tasks cannot prompt for input.
+func (t *Task) Ask(message, fallback, sep string, validator Validator) (string, error) {
+	return "", ErrPromptInProgress
+}
+
+// Confirm returns an error as tasks cannot prompt for input.
+func (t *Task) Confirm(message string, fallback bool, sep string) (bool, error) {
+	return fallback, ErrPromptInProgress
+}
+
+// Select returns an error as tasks cannot prompt for input.
+func (t *Task) Select(r io.Reader, msg string, opts []string) (int, error) {
+	return 0, ErrPromptInProgress
+}
+
+// barWidth is the number of characters inside the progress bar.
+const barWidth = 30
+
+// Bar returns a progress bar with the percentage complete (e.g. [=====>     ]  50%),
+// an empty string is returned when the total is not known.
+func Bar(current, total int64) string {
+	if total <= 0 {
+		return ""
+	}
+
+	if current > total {
+		current = total
+	}
+
+	filled := int(current * barWidth / total)
+
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+
+	return fmt.Sprintf("[%s] %3d%%", bar, current*100/total)
+}
+
+// IsTTY returns true if the file is a terminal.
+func IsTTY(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
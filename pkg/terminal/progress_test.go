@@ -0,0 +1,124 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type spyOutputer struct {
+	Outputer
+	lines []string
+}
+
+func (s *spyOutputer) Pending(m ...string) { s.lines = append(s.lines, strings.Join(m, " ")) }
+func (s *spyOutputer) Done()               { s.lines[len(s.lines)-1] += " done" }
+func (s *spyOutputer) Warning()            { s.lines[len(s.lines)-1] += " failed" }
+func (s *spyOutputer) Info(m ...string)    { s.lines = append(s.lines, strings.Join(m, " ")) }
+
+func TestProgress_Finish(t *testing.T) {
+	spy := &spyOutputer{}
+	buf := &bytes.Buffer{}
+	p := newProgress(buf, spy, false)
+
+	demo := p.Add("demo.nitro")
+	other := p.Add("other.nitro")
+
+	// status updates are not shown until the task finishes
+	demo.Pending("creating")
+	other.Finish(errors.New("unable to pull the image"))
+	demo.Finish(nil, "    recreated because of changes to image")
+
+	want := []string{"other.nitro failed", "    unable to pull the image", "demo.nitro done", "    recreated because of changes to image"}
+	if strings.Join(spy.lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected lines %v, got %v", want, spy.lines)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written when not live, got %q", buf.String())
+	}
+}
+
+func TestProgress_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p := newProgress(buf, &spyOutputer{}, true)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"demo.nitro", "other.nitro", "third.nitro"} {
+		task := p.Add(name)
+
+		wg.Add(1)
+		go func(task *Task) {
+			defer wg.Done()
+
+			task.Pending("creating")
+			task.Finish(nil)
+		}(task)
+	}
+
+	wg.Wait()
+
+	// the last render shows every task as done
+	out := buf.String()
+	last := out[strings.LastIndex(out, "\033[3A"):]
+	for _, name := range []string{"demo.nitro", "other.nitro", "third.nitro"} {
+		if !strings.Contains(last, "✓ "+name) {
+			t.Errorf("expected %s to be done, got %q", name, last)
+		}
+	}
+
+	// updates after a task finishes are ignored
+	before := buf.Len()
+	p.tasks[0].Pending("ignored")
+	if buf.Len() != before {
+		t.Errorf("expected no render after the task finished")
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		total   int64
+		want    string
+	}{
+		{
+			name:  "unknown totals return an empty string",
+			total: 0,
+			want:  "",
+		},
+		{
+			name:    "nothing complete",
+			current: 0,
+			total:   100,
+			want:    "[>                             ]   0%",
+		},
+		{
+			name:    "half complete",
+			current: 50,
+			total:   100,
+			want:    "[===============>              ]  50%",
+		},
+		{
+			name:    "complete",
+			current: 100,
+			total:   100,
+			want:    "[==============================] 100%",
+		},
+		{
+			name:    "current larger than the total is complete",
+			current: 120,
+			total:   100,
+			want:    "[==============================] 100%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Bar(tt.current, tt.total); got != tt.want {
+				t.Errorf("Bar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
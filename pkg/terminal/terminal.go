@@ -32,14 +32,46 @@ type Validator interface {
 	Validate(input string) error
 }
 
-type terminal struct{}
+type terminal struct {
+	// json is used instead of text when the output format is json
+	json *JSON
+
+	// noInteraction disables the prompts
+	noInteraction bool
+
+	// yes answers the confirmations without a prompt
+	yes bool
+}
 
 // New returns an Outputer interface
 func New() *terminal {
 	return &terminal{}
 }
 
+// SetFormat changes the output to text or json, json writes each message as
+// an event to stdout so commands can be used by scripts and other tools.
+func (t *terminal) SetFormat(format string) error {
+	switch format {
+	case "", FormatText:
+		t.json = nil
+	case FormatJSON:
+		t.json = NewJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown output format %q, use %s or %s", format, FormatText, FormatJSON)
+	}
+
+	return nil
+}
+
 func (t *terminal) Ask(message, fallback, sep string, validator Validator) (string, error) {
+	if t.json != nil {
+		return t.json.Ask(message, fallback, sep, validator)
+	}
+
+	if t.noInteraction {
+		return askFallback(message, fallback, validator)
+	}
+
 	t.printStrMessage(message, fallback, sep)
 
 	// create a new scanner
@@ -93,6 +125,18 @@ func (t *terminal) Ask(message, fallback, sep string, validator Validator) (stri
 }
 
 func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, error) {
+	if t.yes {
+		return true, nil
+	}
+
+	if t.json != nil {
+		return t.json.Confirm(message, fallback, sep)
+	}
+
+	if t.noInteraction {
+		return false, noPrompt(message)
+	}
+
 	t.printBoolMessage(message, fallback, sep)
 
 	s := bufio.NewScanner(os.Stdin)
@@ -109,8 +153,6 @@ func (t *terminal) Confirm(message string, fallback bool, sep string) (bool, err
 		default:
 			return fallback, nil
 		}
-
-		break
 	}
 	if err := s.Err(); err != nil {
 		return fallback, err
@@ -142,26 +184,59 @@ func (t *terminal) printValidatorError(err error) {
 }
 
 func (t terminal) Info(s ...string) {
+	if t.json != nil {
+		t.json.Info(s...)
+		return
+	}
+
 	fmt.Printf("%s\n", strings.Join(s, " "))
 }
 
 func (t terminal) Success(s ...string) {
+	if t.json != nil {
+		t.json.Success(s...)
+		return
+	}
+
 	fmt.Printf("  \u2713 %s\n", strings.Join(s, " "))
 }
 
 func (t terminal) Pending(s ...string) {
+	if t.json != nil {
+		t.json.Pending(s...)
+		return
+	}
+
 	fmt.Printf("  … %s ", strings.Join(s, " "))
 }
 
 func (t terminal) Done() {
+	if t.json != nil {
+		t.json.Done()
+		return
+	}
+
 	fmt.Print("\u2713\n")
 }
 
 func (t terminal) Warning() {
+	if t.json != nil {
+		t.json.Warning()
+		return
+	}
+
 	fmt.Print("\u2717\n")
 }
 
 func (t terminal) Select(r io.Reader, msg string, opts []string) (int, error) {
+	if t.json != nil {
+		return t.json.Select(r, msg, opts)
+	}
+
+	if t.noInteraction {
+		return selectFallback(msg, opts)
+	}
+
 	// if the options only have one item, return it
 	if len(opts) == 1 {
 		return 0, nil
@@ -3,6 +3,7 @@ package validate
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -168,3 +169,104 @@ func PhpMaxFileUploads(v string) error {
 
 	return nil
 }
+
+// PHPINI validates a value for a php.ini directive, values for directives
+// that are known are checked by type (e.g. memory_limit must be a size).
+type PHPINI struct {
+	Directive string
+}
+
+func (v *PHPINI) Validate(input string) error {
+	return PHPINISetting(v.Directive, input)
+}
+
+// PHPINIDirective validates the name of a php.ini directive (e.g. session.gc_maxlifetime)
+type PHPINIDirective struct{}
+
+func (v *PHPINIDirective) Validate(input string) error {
+	if !phpINIDirective.MatchString(input) {
+		return fmt.Errorf("the php.ini directive %q is not valid", input)
+	}
+
+	return nil
+}
+
+type phpINIType int
+
+const (
+	phpINIBool phpINIType = iota
+	phpINIInt
+	phpINISize
+)
+
+var (
+	phpINIDirective = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+	phpINISizeValue = regexp.MustCompile(`^(-1|[0-9]+[KMGkmg]?)$`)
+
+	// phpINITypes are the directives that are validated by type
+	phpINITypes = map[string]phpINIType{
+		"display_errors":                  phpINIBool,
+		"display_startup_errors":          phpINIBool,
+		"expose_php":                      phpINIBool,
+		"file_uploads":                    phpINIBool,
+		"log_errors":                      phpINIBool,
+		"opcache.enable":                  phpINIBool,
+		"opcache.enable_cli":              phpINIBool,
+		"opcache.validate_timestamps":     phpINIBool,
+		"short_open_tag":                  phpINIBool,
+		"default_socket_timeout":          phpINIInt,
+		"max_execution_time":              phpINIInt,
+		"max_file_uploads":                phpINIInt,
+		"max_input_nesting_level":         phpINIInt,
+		"max_input_time":                  phpINIInt,
+		"max_input_vars":                  phpINIInt,
+		"opcache.interned_strings_buffer": phpINIInt,
+		"opcache.max_accelerated_files":   phpINIInt,
+		"opcache.memory_consumption":      phpINIInt,
+		"opcache.revalidate_freq":         phpINIInt,
+		"session.gc_maxlifetime":          phpINIInt,
+		"session.gc_probability":          phpINIInt,
+		"session.gc_divisor":              phpINIInt,
+		"memory_limit":                    phpINISize,
+		"post_max_size":                   phpINISize,
+		"realpath_cache_size":             phpINISize,
+		"upload_max_filesize":             phpINISize,
+	}
+)
+
+// PHPINISetting validates a php.ini directive and value. Values can not contain
+// quotes or new lines, and directives that are known are validated by type.
+func PHPINISetting(directive, value string) error {
+	if err := (&PHPINIDirective{}).Validate(directive); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(value, "\"\r\n") {
+		return fmt.Errorf("the value for %s must not contain quotes or new lines", directive)
+	}
+
+	t, ok := phpINITypes[directive]
+	if !ok {
+		return nil
+	}
+
+	switch t {
+	case phpINIBool:
+		switch strings.ToLower(value) {
+		case "1", "0", "on", "off", "true", "false", "yes", "no":
+			return nil
+		}
+
+		return fmt.Errorf("%s must be on or off", directive)
+	case phpINIInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s must be a valid integer", directive)
+		}
+	case phpINISize:
+		if !phpINISizeValue.MatchString(value) {
+			return fmt.Errorf("%s must be a size (e.g. 256M)", directive)
+		}
+	}
+
+	return nil
+}
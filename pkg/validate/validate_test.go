@@ -180,3 +180,70 @@ func TestIsBoolean_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPHPINISetting(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		value     string
+		wantErr   bool
+	}{
+		{
+			name:      "unknown directives accept any value",
+			directive: "date.timezone",
+			value:     "America/Chicago",
+		},
+		{
+			name:      "invalid directive names return an error",
+			directive: "memory limit",
+			value:     "256M",
+			wantErr:   true,
+		},
+		{
+			name:      "booleans accept on and off",
+			directive: "display_errors",
+			value:     "Off",
+		},
+		{
+			name:      "invalid booleans return an error",
+			directive: "display_errors",
+			value:     "sometimes",
+			wantErr:   true,
+		},
+		{
+			name:      "integers must be numbers",
+			directive: "session.gc_maxlifetime",
+			value:     "1h",
+			wantErr:   true,
+		},
+		{
+			name:      "sizes accept a unit",
+			directive: "memory_limit",
+			value:     "1G",
+		},
+		{
+			name:      "sizes accept unlimited",
+			directive: "memory_limit",
+			value:     "-1",
+		},
+		{
+			name:      "invalid sizes return an error",
+			directive: "upload_max_filesize",
+			value:     "lots",
+			wantErr:   true,
+		},
+		{
+			name:      "values with new lines return an error",
+			directive: "date.timezone",
+			value:     "UTC\nmemory_limit = -1",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PHPINISetting(tt.directive, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("PHPINISetting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
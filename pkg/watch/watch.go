@@ -0,0 +1,136 @@
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// Interval is how often the files are checked for changes
+	Interval = 500 * time.Millisecond
+
+	// Debounce is how long to wait after the last change before sending the
+	// changed files, editors often write a file more than once when saving
+	Debounce = time.Second
+)
+
+// Watcher checks a list of files for changes by comparing the checksum of
+// the contents. Files that do not exist are watched so they are reported
+// once created, and files that are removed are reported as changed.
+type Watcher struct {
+	Interval time.Duration
+	Debounce time.Duration
+
+	mu    sync.Mutex
+	files map[string]string
+}
+
+// New returns a watcher for the files using the default interval and debounce.
+func New(files ...string) *Watcher {
+	w := &Watcher{Interval: Interval, Debounce: Debounce, files: make(map[string]string)}
+
+	w.Set(files...)
+
+	return w
+}
+
+// Set replaces the files to watch, files that were already watched keep
+// their checksum so changes are not missed.
+func (w *Watcher) Set(files ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next := make(map[string]string)
+	for _, f := range files {
+		if sum, ok := w.files[f]; ok {
+			next[f] = sum
+			continue
+		}
+
+		next[f] = checksum(f)
+	}
+
+	w.files = next
+}
+
+// Watch checks the files until the context is done and sends the files that
+// changed, sorted by name, once no more changes are found for the debounce.
+// The channel is closed when the context is done.
+func (w *Watcher) Watch(ctx context.Context) <-chan []string {
+	ch := make(chan []string)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		changed := make(map[string]bool)
+		var last time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, f := range w.check() {
+					changed[f] = true
+					last = now
+				}
+
+				// wait until the files stop changing
+				if len(changed) == 0 || now.Sub(last) < w.Debounce {
+					continue
+				}
+
+				var files []string
+				for f := range changed {
+					files = append(files, f)
+				}
+
+				sort.Strings(files)
+
+				select {
+				case ch <- files:
+				case <-ctx.Done():
+					return
+				}
+
+				changed = make(map[string]bool)
+			}
+		}
+	}()
+
+	return ch
+}
+
+// check returns the files with a different checksum and stores the new checksum.
+func (w *Watcher) check() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changed []string
+	for f, sum := range w.files {
+		if current := checksum(f); current != sum {
+			w.files[f] = current
+			changed = append(changed, f)
+		}
+	}
+
+	return changed
+}
+
+// checksum returns the checksum of the file, or an empty string if the file cannot be read.
+func checksum(file string) string {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
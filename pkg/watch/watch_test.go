@@ -0,0 +1,86 @@
+package watch
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+
+	config := filepath.Join(dir, "nitro.yaml")
+	envFile := filepath.Join(dir, ".elasticsearch")
+	unchanged := filepath.Join(dir, ".mysql")
+
+	if err := ioutil.WriteFile(config, []byte("version: 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(unchanged, []byte("KEY=value"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(config, envFile, unchanged)
+	w.Interval = 10 * time.Millisecond
+	w.Debounce = 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes := w.Watch(ctx)
+
+	// write the config more than once and create the missing env file
+	for _, content := range []string{"version: 3\nsites: []", "version: 3\nsites: [] "} {
+		if err := ioutil.WriteFile(config, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := ioutil.WriteFile(envFile, []byte("KEY=value"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changes:
+		want := []string{envFile, config}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Watch() = %v, want %v", got, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("Watch() expected changes before the timeout")
+	}
+
+	cancel()
+
+	// the channel is closed once the context is done
+	for range changes {
+	}
+}
+
+func TestWatcher_Set(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "nitro.yaml")
+	if err := ioutil.WriteFile(file, []byte("version: 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(file)
+
+	// a change before the files are replaced is still reported
+	if err := ioutil.WriteFile(file, []byte("version: 3\nsites: []"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w.Set(file, filepath.Join(dir, ".elasticsearch"))
+
+	if got := w.check(); !reflect.DeepEqual(got, []string{file}) {
+		t.Errorf("check() = %v, want %v", got, []string{file})
+	}
+}